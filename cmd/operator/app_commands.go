@@ -20,4 +20,6 @@ import (
 
 var appCmds = []cli.Command{
 	controllerCmd,
+	jobCmd,
+	removeFinalizersCmd,
 }
@@ -0,0 +1,40 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package main
+
+import (
+	"github.com/minio/cli"
+	"github.com/minio/operator/pkg/controller"
+)
+
+// stops the Operator and removes its finalizer from the tenants before it's uninstalled
+var removeFinalizersCmd = cli.Command{
+	Name:   "remove-finalizers",
+	Usage:  "Stop MinIO Operator and remove its finalizer from all the Tenants before uninstalling it",
+	Action: removeFinalizers,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "kubeconfig",
+			Usage: "Load configuration from `KUBECONFIG`",
+		},
+	},
+}
+
+func removeFinalizers(ctx *cli.Context) error {
+	if err := controller.RemoveTenantFinalizers(ctx.String("kubeconfig")); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
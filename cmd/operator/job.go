@@ -0,0 +1,84 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/minio/cli"
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	"github.com/minio/operator/pkg/controller"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// MinIOJob helpers
+var jobCmd = cli.Command{
+	Name:  "job",
+	Usage: "MinIOJob tools",
+	Subcommands: []cli.Command{
+		jobPlanCmd,
+	},
+}
+
+// renders the commands of a MinIOJob without running them
+var jobPlanCmd = cli.Command{
+	Name:   "plan",
+	Usage:  "Print the mc commands a MinIOJob runs and their execution order",
+	Action: planJob,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "file, f",
+			Usage: "Load the MinIOJob from `FILE`, - to read it from stdin",
+		},
+	},
+}
+
+func planJob(ctx *cli.Context) error {
+	file := ctx.String("file")
+	if file == "" {
+		return cli.NewExitError("the MinIOJob file is required, see --file", 1)
+	}
+	var reader io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		defer f.Close()
+		reader = f
+	}
+	jobCR := &v1alpha1.MinIOJob{}
+	if err := yaml.NewYAMLOrJSONDecoder(reader, 4096).Decode(jobCR); err != nil {
+		return cli.NewExitError(fmt.Sprintf("unable to read the MinIOJob: %v", err), 1)
+	}
+	status, err := controller.PlanMinIOJob(jobCR)
+	if err != nil {
+		return cli.NewExitError(fmt.Sprintf("invalid job: %v", err), 1)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAGE\tNAME\tDEPENDS ON\tCOMMAND")
+	for _, command := range status.Plan {
+		dependsOn := strings.Join(command.DependsOn, ",")
+		if dependsOn == "" {
+			dependsOn = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", command.Stage, command.Name, dependsOn, strings.Join(command.Command, " "))
+	}
+	return w.Flush()
+}
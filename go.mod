@@ -47,6 +47,7 @@ require (
 require (
 	github.com/go-test/deep v1.1.1
 	github.com/minio/kes-go v0.2.1
+	github.com/opencontainers/image-spec v1.1.0
 	golang.org/x/mod v0.18.0
 	sigs.k8s.io/controller-runtime v0.18.4
 )
@@ -94,7 +95,6 @@ require (
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/opencontainers/go-digest v1.0.0 // indirect
-	github.com/opencontainers/image-spec v1.1.0 // indirect
 	github.com/philhofer/fwd v1.1.2 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
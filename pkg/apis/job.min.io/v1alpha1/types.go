@@ -28,6 +28,29 @@ const (
 	StopOnFailure FailureStrategy = "stopOnFailure"
 )
 
+// CredentialMode describes how the jobs of a MinIO Job authenticate against the tenant
+type CredentialMode string
+
+const (
+	// CredentialModeSTS the jobs assume the policies bound to their service account through the operator STS service
+	CredentialModeSTS CredentialMode = "sts"
+
+	// CredentialModeAccessKey the jobs use an access key created for the MinIO Job, scoped to the policies bound to its
+	// service account, and revoked once the MinIO Job is done
+	CredentialModeAccessKey CredentialMode = "accessKey"
+)
+
+// ConcurrencyPolicy describes how a scheduled MinIO Job treats a schedule tick while its previous run is still running
+type ConcurrencyPolicy string
+
+const (
+	// ForbidConcurrent skips the new run if the previous run hasn't finished yet
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+
+	// ReplaceConcurrent cancels the running run and replaces it with the new one
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:defaulter-gen=true
@@ -94,6 +117,78 @@ type MinIOJobSpec struct {
 	// Commands List of MinioClient commands
 	Commands []CommandSpec `json:"commands"`
 
+	// *Optional* +
+	//
+	// Schedule in Cron format, e.g. `0 2 * * *` or `@daily`, evaluated in UTC. When set, all the commands run again
+	// on every tick of the schedule, otherwise they run only once.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// *Optional* +
+	//
+	// ConcurrencyPolicy specifies how to treat a schedule tick while the previous run is still running.
+	// Either `Forbid` to skip the new run, or `Replace` to cancel the running one, defaults to `Forbid`.
+	// +optional
+	// +kubebuilder:default=Forbid
+	// +kubebuilder:validation:Enum=Forbid;Replace;
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// *Optional* +
+	//
+	// The number of successful finished scheduled runs to keep in the history, along with their Kubernetes Jobs.
+	// Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	SuccessfulRunsHistoryLimit *int32 `json:"successfulRunsHistoryLimit,omitempty"`
+
+	// *Optional* +
+	//
+	// The number of failed finished scheduled runs to keep in the history, along with their Kubernetes Jobs.
+	// Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailedRunsHistoryLimit *int32 `json:"failedRunsHistoryLimit,omitempty"`
+
+	// *Optional* +
+	//
+	// CommandDefaults are the retry, backoff, timeout and TTL policies of the commands that don't set their own.
+	// +optional
+	CommandDefaults CommandPolicy `json:"commandDefaults,omitempty"`
+
+	// *Optional* +
+	//
+	// PersistOutput stores the full output of every command in the Secret `<minio-job-name>-output`, owned by
+	// the MinIOJob, under a key named after the command. The status only keeps the last lines of the output. +
+	// +optional
+	PersistOutput bool `json:"persistOutput,omitempty"`
+
+	// *Optional* +
+	//
+	// TTLAfterFinished is the number of seconds a finished MinIO Job is kept before it's deleted, along with its
+	// Kubernetes Jobs. For a MinIO Job with a schedule, the Kubernetes Jobs of every finished run are deleted once their
+	// TTL is over, the run stays in the history. Finished MinIO Jobs are kept forever by default. +
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TTLAfterFinished *int32 `json:"ttlAfterFinished,omitempty"`
+
+	// *Optional* +
+	//
+	// CredentialMode is how the jobs authenticate against the tenant, either `sts` to use the web identity of the
+	// service account through the operator STS service, or `accessKey` to use an access key the operator creates with
+	// the root credentials of the tenant, scoped to the policies of the PolicyBindings of the service account. The access
+	// key is revoked once the MinIO Job finished, or when it's deleted for a scheduled MinIO Job. Defaults to `sts`. +
+	// +optional
+	// +kubebuilder:default=sts
+	// +kubebuilder:validation:Enum=sts;accessKey;
+	CredentialMode CredentialMode `json:"credentialMode,omitempty"`
+
+	// *Optional* +
+	//
+	// DryRun resolves the operations, aliases and dependencies of the commands and reports the rendered `mc`
+	// invocations and their execution order in `status.plan`, without creating any Kubernetes Job. +
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
 	// The Docker image to use when deploying `mc` pods. Defaults to {mc-image}. +
 	// +optional
 	// +kubebuilder:default="quay.io/minio/mc:RELEASE.2024-07-11T18-01-28Z"
@@ -161,10 +256,20 @@ type CommandSpec struct {
 	// +optional
 	Args map[string]string `json:"args,omitempty"`
 
+	// ArgsFrom Arguments to pass to the action whose values are read from Secrets or ConfigMaps, the values are
+	// injected in the container when the command runs and never show in the Kubernetes Job
+	// +optional
+	ArgsFrom []ArgFromSource `json:"argsFrom,omitempty"`
+
 	// Command Execute All User-Defined Commands
 	// +optional
 	Command []string `json:"command,omitempty"`
 
+	// Batch submits a MinIO batch job to the tenant through the admin API and tracks it until it finishes, instead of
+	// running an `mc` command in a Kubernetes Job. `op`, `args`, `argsFrom` and `command` must be empty.
+	// +optional
+	Batch *BatchJobSpec `json:"batch,omitempty"`
+
 	// DependsOn List of named `command` in this MinioJob that have to be scheduled and executed before this command runs
 	// +optional
 	DependsOn []string `json:"dependsOn,omitempty"`
@@ -188,6 +293,9 @@ type CommandSpec struct {
 	// +optional
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// Retry, backoff, timeout and TTL policies of the command, override the `commandDefaults` of the MinIOJob
+	CommandPolicy `json:",inline"`
+
 	// Pod volumes to mount into the container's filesystem.
 	// Cannot be updated.
 	// +optional
@@ -199,6 +307,91 @@ type CommandSpec struct {
 	Volumes []corev1.Volume `json:"volumes,omitempty"`
 }
 
+// ArgFromSource is an argument of a command whose value is read from a Secret or a ConfigMap
+type ArgFromSource struct {
+	// *Required* +
+	//
+	// Name of the argument, like the keys of `args`
+	Name string `json:"name"`
+
+	// *Required* +
+	//
+	// ValueFrom Source of the value of the argument
+	ValueFrom ArgValueSource `json:"valueFrom"`
+
+	// *Optional* +
+	//
+	// AsFile passes the path of a file holding the value instead of the value itself, for the arguments expecting a
+	// file like the `policy` of `admin/policy/create`
+	// +optional
+	AsFile bool `json:"asFile,omitempty"`
+}
+
+// ArgValueSource is the source of the value of an argument, exactly one of its fields must be set
+type ArgValueSource struct {
+	// Selects a key of a Secret in the namespace of the MinIOJob
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// Selects a key of a ConfigMap in the namespace of the MinIOJob
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// BatchJobSpec is the definition of a MinIO batch job, `replicate`, `keyrotate` or `expire`, in the YAML format of
+// `mc batch generate`. Exactly one of its fields must be set.
+type BatchJobSpec struct {
+	// Definition of the batch job inline
+	// +optional
+	Definition string `json:"definition,omitempty"`
+
+	// Selects a key of a ConfigMap in the namespace of the MinIOJob holding the definition of the batch job
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// CommandPolicy controls how the Kubernetes Jobs of a command are retried, timed out and cleaned up
+type CommandPolicy struct {
+	// *Optional* +
+	//
+	// Retries is the number of times a failed command runs again before it's reported as failed, every attempt runs
+	// in its own Kubernetes Job. Defaults to 6.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Retries *int32 `json:"retries,omitempty"`
+
+	// *Optional* +
+	//
+	// BackoffSeconds is the delay before the first retry of a failed command, doubled on every further retry.
+	// Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	BackoffSeconds *int32 `json:"backoffSeconds,omitempty"`
+
+	// *Optional* +
+	//
+	// MaxBackoffSeconds caps the delay between two retries of a command. Defaults to 360.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxBackoffSeconds *int32 `json:"maxBackoffSeconds,omitempty"`
+
+	// *Optional* +
+	//
+	// TimeoutSeconds is how long a single attempt of the command can run before it's stopped and reported as timed
+	// out. No timeout by default.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+
+	// *Optional* +
+	//
+	// TTLSecondsAfterFinished is how long the Kubernetes Job of an attempt is kept after it finished, the outcome of
+	// the command stays in the status once the Job is deleted. Jobs are kept by default.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
 // TenantRef Is the reference to the target tenant of the jobs
 type TenantRef struct {
 	// *Required* +
@@ -215,6 +408,59 @@ type MinIOJobStatus struct {
 	CommandsStatus []CommandStatus `json:"commands"`
 	// +optional
 	Message string `json:"message"`
+	// Run is the name of the scheduled run reported by phase and commands
+	// +optional
+	Run string `json:"run,omitempty"`
+	// LastScheduleTime is the last time a scheduled run was started
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// NextScheduleTime is the next time a scheduled run will start
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// History of the finished scheduled runs, most recent first
+	// +optional
+	History []MinIOJobRun `json:"history,omitempty"`
+	// CompletionTime is when a MinioJob without schedule finished, either succeeded, failed or cancelled
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Plan is the rendered command list and execution graph of a dry run
+	// +optional
+	Plan []CommandPlan `json:"plan,omitempty"`
+}
+
+// CommandPlan is how a command of a MinioJob would run, as reported by a dry run
+type CommandPlan struct {
+	// Name of the command
+	Name string `json:"name"`
+	// Command is the rendered command line of the `mc` container
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// DependsOn are the commands that must succeed before this one runs
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Stage is the order the command runs in, the commands of a stage run once the earlier stages finished.
+	// Commands of the same stage run in parallel.
+	// +optional
+	Stage int32 `json:"stage"`
+}
+
+// MinIOJobRun is the record of a finished scheduled run of a MinioJob
+type MinIOJobRun struct {
+	// Name of the run, the Kubernetes Jobs of the run are labeled with it
+	Name string `json:"name"`
+	// ScheduleTime is the schedule tick that started the run
+	// +optional
+	ScheduleTime *metav1.Time `json:"scheduleTime,omitempty"`
+	// CompletionTime is when the run was seen finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Phase the run finished with
+	// +optional
+	Phase string `json:"phase"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	CommandsStatus []CommandStatus `json:"commands,omitempty"`
 }
 
 // CommandStatus Status of MinioJob command execution
@@ -225,4 +471,54 @@ type CommandStatus struct {
 	Result string `json:"result"`
 	// +optional
 	Message string `json:"message"`
+	// ExitCode of the last attempt of the command container
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// StartTime is when the Kubernetes Job of the command started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is when the Kubernetes Job of the command finished, either succeeded or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Attempts is the number of times the command ran, including its retries
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// NextRetryTime is when a failed command runs again
+	// +optional
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+	// Output is the tail of the output of the last attempt of the command
+	// +optional
+	Output string `json:"output,omitempty"`
+	// Batch is the progress of the MinIO batch job submitted by the last attempt of a batch command
+	// +optional
+	Batch *BatchJobStatus `json:"batch,omitempty"`
+}
+
+// BatchJobStatus is the progress of a MinIO batch job, as reported by the tenant
+type BatchJobStatus struct {
+	// ID of the batch job in the tenant
+	ID string `json:"id"`
+	// Type of the batch job, `replicate`, `keyrotate` or `expire`
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Objects is the number of objects processed so far
+	// +optional
+	Objects int64 `json:"objects,omitempty"`
+	// ObjectsFailed is the number of objects that could not be processed
+	// +optional
+	ObjectsFailed int64 `json:"objectsFailed,omitempty"`
+	// BytesTransferred is the amount of data replicated so far, only reported by `replicate` batch jobs
+	// +optional
+	BytesTransferred int64 `json:"bytesTransferred,omitempty"`
+	// BytesFailed is the amount of data that could not be replicated, only reported by `replicate` batch jobs
+	// +optional
+	BytesFailed int64 `json:"bytesFailed,omitempty"`
+	// LastBucket and LastObject are the last object processed
+	// +optional
+	LastBucket string `json:"lastBucket,omitempty"`
+	// +optional
+	LastObject string `json:"lastObject,omitempty"`
+	// LastUpdate is the last time the tenant reported progress
+	// +optional
+	LastUpdate *metav1.Time `json:"lastUpdate,omitempty"`
 }
@@ -26,6 +26,157 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgFromSource) DeepCopyInto(out *ArgFromSource) {
+	*out = *in
+	in.ValueFrom.DeepCopyInto(&out.ValueFrom)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgFromSource.
+func (in *ArgFromSource) DeepCopy() *ArgFromSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgFromSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgValueSource) DeepCopyInto(out *ArgValueSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgValueSource.
+func (in *ArgValueSource) DeepCopy() *ArgValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchJobSpec) DeepCopyInto(out *BatchJobSpec) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchJobSpec.
+func (in *BatchJobSpec) DeepCopy() *BatchJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchJobStatus) DeepCopyInto(out *BatchJobStatus) {
+	*out = *in
+	if in.LastUpdate != nil {
+		in, out := &in.LastUpdate, &out.LastUpdate
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchJobStatus.
+func (in *BatchJobStatus) DeepCopy() *BatchJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandPlan) DeepCopyInto(out *CommandPlan) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandPlan.
+func (in *CommandPlan) DeepCopy() *CommandPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandPolicy) DeepCopyInto(out *CommandPolicy) {
+	*out = *in
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackoffSeconds != nil {
+		in, out := &in.BackoffSeconds, &out.BackoffSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxBackoffSeconds != nil {
+		in, out := &in.MaxBackoffSeconds, &out.MaxBackoffSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandPolicy.
+func (in *CommandPolicy) DeepCopy() *CommandPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommandSpec) DeepCopyInto(out *CommandSpec) {
 	*out = *in
@@ -36,11 +187,23 @@ func (in *CommandSpec) DeepCopyInto(out *CommandSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ArgsFrom != nil {
+		in, out := &in.ArgsFrom, &out.ArgsFrom
+		*out = make([]ArgFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Command != nil {
 		in, out := &in.Command, &out.Command
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Batch != nil {
+		in, out := &in.Batch, &out.Batch
+		*out = new(BatchJobSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DependsOn != nil {
 		in, out := &in.DependsOn, &out.DependsOn
 		*out = make([]string, len(*in))
@@ -61,6 +224,7 @@ func (in *CommandSpec) DeepCopyInto(out *CommandSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.CommandPolicy.DeepCopyInto(&out.CommandPolicy)
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
 		*out = make([]v1.VolumeMount, len(*in))
@@ -91,6 +255,28 @@ func (in *CommandSpec) DeepCopy() *CommandSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommandStatus) DeepCopyInto(out *CommandStatus) {
 	*out = *in
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Batch != nil {
+		in, out := &in.Batch, &out.Batch
+		*out = new(BatchJobStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -165,6 +351,37 @@ func (in *MinIOJobList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MinIOJobRun) DeepCopyInto(out *MinIOJobRun) {
+	*out = *in
+	if in.ScheduleTime != nil {
+		in, out := &in.ScheduleTime, &out.ScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CommandsStatus != nil {
+		in, out := &in.CommandsStatus, &out.CommandsStatus
+		*out = make([]CommandStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MinIOJobRun.
+func (in *MinIOJobRun) DeepCopy() *MinIOJobRun {
+	if in == nil {
+		return nil
+	}
+	out := new(MinIOJobRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MinIOJobSpec) DeepCopyInto(out *MinIOJobSpec) {
 	*out = *in
@@ -176,6 +393,22 @@ func (in *MinIOJobSpec) DeepCopyInto(out *MinIOJobSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SuccessfulRunsHistoryLimit != nil {
+		in, out := &in.SuccessfulRunsHistoryLimit, &out.SuccessfulRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedRunsHistoryLimit != nil {
+		in, out := &in.FailedRunsHistoryLimit, &out.FailedRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	in.CommandDefaults.DeepCopyInto(&out.CommandDefaults)
+	if in.TTLAfterFinished != nil {
+		in, out := &in.TTLAfterFinished, &out.TTLAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
 	if in.ImagePullSecret != nil {
 		in, out := &in.ImagePullSecret, &out.ImagePullSecret
 		*out = make([]v1.LocalObjectReference, len(*in))
@@ -210,7 +443,35 @@ func (in *MinIOJobStatus) DeepCopyInto(out *MinIOJobStatus) {
 	if in.CommandsStatus != nil {
 		in, out := &in.CommandsStatus, &out.CommandsStatus
 		*out = make([]CommandStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]MinIOJobRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]CommandPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -57,6 +57,12 @@ const CfgFile = CfgPath + "config.env"
 // TenantLabel is applied to all components of a Tenant cluster
 const TenantLabel = "v1.min.io/tenant"
 
+// TenantFinalizer is set on the tenants so the Operator cleans up after them according to their deletion policy
+const TenantFinalizer = "min.io/tenant-cleanup"
+
+// RetainedFromLabel is applied to the PVCs and secrets kept after their tenant was deleted, with the tenant name as value
+const RetainedFromLabel = "min.io/retained-from"
+
 // PoolLabel is applied to all components in a Pool of a Tenant cluster
 const PoolLabel = "v1.min.io/pool"
 
@@ -66,6 +72,15 @@ const ZoneLabel = "v1.min.io/zone"
 // Revision is applied to all statefulsets
 const Revision = "min.io/revision"
 
+// PoolRebalanceAnnotation is set on a Tenant to stop a running pool rebalance, removing it resumes the rebalance
+const PoolRebalanceAnnotation = "min.io/pool-rebalance"
+
+// PoolRebalanceStop is the PoolRebalanceAnnotation value that stops a running pool rebalance
+const PoolRebalanceStop = "stop"
+
+// HibernateReplicasAnnotation is set on the statefulsets of a hibernated Tenant with the replicas to restore on resume
+const HibernateReplicasAnnotation = "min.io/hibernate-replicas"
+
 // MinIOPort specifies the default Tenant port number.
 const MinIOPort = 9000
 
@@ -103,6 +118,13 @@ const DefaultMinIOImage = "minio/minio:RELEASE.2024-07-13T01-46-15Z"
 // pulled from during MinIO upgrades
 const DefaultMinIOUpdateURL = "https://dl.min.io/server/minio/release/" + runtime.GOOS + "-" + runtime.GOARCH + "/archive/"
 
+// PoolReplacementSuffix specifies the suffix added, along with a sequence number, to the statefulset name of a pool
+// replacing another one
+const PoolReplacementSuffix = "-r"
+
+// DefaultMinIOServiceName specifies the name of the MinIO service of a Tenant without service name
+const DefaultMinIOServiceName = "minio"
+
 // MinIOHLSvcNameSuffix specifies the suffix added to Tenant name to create a headless service
 const MinIOHLSvcNameSuffix = "-hl"
 
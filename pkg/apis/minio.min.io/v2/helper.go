@@ -45,6 +45,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 
 	"github.com/golang-jwt/jwt"
@@ -410,15 +411,67 @@ func (t *Tenant) GenBearerToken(accessKey, secretKey string) string {
 	return token
 }
 
+// IsPoolRemoved returns true if the pool status belongs to a pool that was removed from the
+// spec and is being, or has been, decommissioned by MinIO, or to a pool being replaced
+func (t *Tenant) IsPoolRemoved(poolStatus PoolStatus) bool {
+	if poolStatus.Replacement != nil {
+		return true
+	}
+	if poolStatus.State != PoolDecommissioning && poolStatus.State != PoolDecommissioned {
+		return false
+	}
+	for i := range t.Spec.Pools {
+		if t.IsPoolStatefulset(&t.Spec.Pools[i], poolStatus.SSName) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPoolStatefulset returns true if the statefulset was deployed for the pool, under its name, its legacy name or
+// the name of one of its replacements
+func (t *Tenant) IsPoolStatefulset(pool *Pool, ssName string) bool {
+	if ssName == t.PoolStatefulsetName(pool) || ssName == t.LegacyStatefulsetName(pool) {
+		return true
+	}
+	n, ok := strings.CutPrefix(ssName, t.PoolStatefulsetName(pool)+PoolReplacementSuffix)
+	if !ok || n == "" {
+		return false
+	}
+	_, err := strconv.ParseUint(n, 10, 32)
+	return err == nil
+}
+
+// PoolStatusForPool returns the status of the statefulset serving the pool, nil if the pool isn't deployed yet
+func (t *Tenant) PoolStatusForPool(pool *Pool) *PoolStatus {
+	for i := range t.Status.Pools {
+		if t.Status.Pools[i].Replacement == nil && t.IsPoolStatefulset(pool, t.Status.Pools[i].SSName) {
+			return &t.Status.Pools[i]
+		}
+	}
+	return nil
+}
+
+// StatefulsetNameForPool returns the name of the statefulset serving the pool: the one recorded in the status, the
+// replacement of the pool while it's being replaced, or the default name for a pool that isn't deployed yet
+func (t *Tenant) StatefulsetNameForPool(pool *Pool) string {
+	if poolStatus := t.PoolStatusForPool(pool); poolStatus != nil {
+		return poolStatus.SSName
+	}
+	for _, poolStatus := range t.Status.Pools {
+		if poolStatus.Replacement != nil && t.IsPoolStatefulset(pool, poolStatus.SSName) {
+			return poolStatus.Replacement.SSName
+		}
+	}
+	return t.PoolStatefulsetName(pool)
+}
+
 // MinIOHosts returns the domain names in ellipses format created for current Tenant
 func (t *Tenant) MinIOHosts() (hosts []string) {
 	// Create the ellipses style URL
-	for pi, pool := range t.Spec.Pools {
+	for _, pool := range t.Spec.Pools {
 		// determine the proper statefulset name
-		ssName := t.PoolStatefulsetName(&pool)
-		if len(t.Status.Pools) > pi {
-			ssName = t.Status.Pools[pi].SSName
-		}
+		ssName := t.StatefulsetNameForPool(&pool)
 
 		if pool.Servers == 1 {
 			hosts = append(hosts, fmt.Sprintf("%s-%s.%s.%s.svc.%s", ssName, "0", t.MinIOHLServiceName(), t.Namespace, GetClusterDomain()))
@@ -442,7 +495,7 @@ func (t *Tenant) TemplatedMinIOHosts(hostsTemplate string) (hosts []string) {
 	for _, pool := range t.Spec.Pools {
 		max = max + pool.Servers
 		data := hostsTemplateValues{
-			StatefulSet: t.MinIOStatefulSetNameForPool(&pool),
+			StatefulSet: t.StatefulsetNameForPool(&pool),
 			CIService:   t.MinIOCIServiceName(),
 			HLService:   t.MinIOHLServiceName(),
 			Ellipsis:    genEllipsis(int(index), int(max)-1),
@@ -838,14 +891,91 @@ func (t *Tenant) Validate() error {
 			return err
 		}
 	}
+	// a pool can't be named like the replacement of another pool, their statefulsets would collide
+	for i := range t.Spec.Pools {
+		for j := range t.Spec.Pools {
+			if i != j && t.IsPoolStatefulset(&t.Spec.Pools[i], t.PoolStatefulsetName(&t.Spec.Pools[j])) {
+				return fmt.Errorf("pool name '%s' collides with the statefulsets of pool '%s'", t.Spec.Pools[j].Name, t.Spec.Pools[i].Name)
+			}
+		}
+	}
+	if source := t.Spec.UpgradeSource; source != nil && source.Type != "" && source.Type != UpgradeSourceRegistry {
+		if !path.IsAbs(source.Path) {
+			return fmt.Errorf("upgradeSource of type %s requires an absolute path, got '%s'", source.Type, source.Path)
+		}
+		for _, elem := range strings.Split(source.Path, "/") {
+			if elem == ".." {
+				return fmt.Errorf("upgradeSource path '%s' can't have '..' elements", source.Path)
+			}
+		}
+	}
+	if policy := t.Spec.ImageVerification; policy != nil {
+		for _, digest := range policy.AllowedDigests {
+			if hex, ok := strings.CutPrefix(digest, "sha256:"); !ok || len(hex) != 64 {
+				return fmt.Errorf("invalid allowed digest '%s', expected sha256:<hex>", digest)
+			}
+		}
+		if policy.MinimumRelease != "" {
+			if _, err := ReleaseTagToReleaseTime(policy.MinimumRelease); err != nil {
+				return fmt.Errorf("invalid minimum release '%s': %v", policy.MinimumRelease, err)
+			}
+		}
+	}
 	// make sure all the domains are valid
 	if err := t.ValidateDomains(); err != nil {
 		return err
 	}
 
+	if t.Spec.ServiceName != "" {
+		if errs := validation.IsDNS1035Label(t.Spec.ServiceName); len(errs) > 0 {
+			return fmt.Errorf("invalid serviceName '%s': %s", t.Spec.ServiceName, strings.Join(errs, ", "))
+		}
+		if t.Spec.ServiceName == t.MinIOHLServiceName() || t.Spec.ServiceName == t.ConsoleCIServiceName() {
+			return fmt.Errorf("serviceName '%s' collides with another service of the tenant", t.Spec.ServiceName)
+		}
+	}
+
+	return nil
+}
+
+// ValidateUpdate returns an error if the update of the MinIO Tenant changes its service name or the fields of an
+// existing pool that can't be changed once the pool is deployed, pools are matched by name. The server and volume
+// count of a pool can only change when the Operator is allowed to replace the pool
+func (t *Tenant) ValidateUpdate(old *Tenant) error {
+	if t.MinIOCIServiceName() != old.MinIOCIServiceName() {
+		return fmt.Errorf("serviceName can't be changed from '%s' to '%s'", old.MinIOCIServiceName(), t.MinIOCIServiceName())
+	}
+	oldPools := map[string]Pool{}
+	for _, pool := range old.Spec.Pools {
+		oldPools[pool.Name] = pool
+	}
+	for _, pool := range t.Spec.Pools {
+		oldPool, ok := oldPools[pool.Name]
+		if !ok {
+			continue
+		}
+		// with allowPoolReplacement the Operator replaces the pools whose geometry changed
+		if pool.Servers != oldPool.Servers && !t.Spec.AllowPoolReplacement {
+			return fmt.Errorf("pool %s: servers can't be changed from %d to %d without allowPoolReplacement", pool.Name, oldPool.Servers, pool.Servers)
+		}
+		if pool.VolumesPerServer != oldPool.VolumesPerServer && !t.Spec.AllowPoolReplacement {
+			return fmt.Errorf("pool %s: volumesPerServer can't be changed from %d to %d without allowPoolReplacement", pool.Name, oldPool.VolumesPerServer, pool.VolumesPerServer)
+		}
+		if storageClass, oldStorageClass := pool.storageClassName(), oldPool.storageClassName(); storageClass != oldStorageClass {
+			return fmt.Errorf("pool %s: the storage class of the volume claim template can't be changed from '%s' to '%s'", pool.Name, oldStorageClass, storageClass)
+		}
+	}
 	return nil
 }
 
+// storageClassName returns the storage class of the volume claim template of the pool, empty for the default class
+func (z *Pool) storageClassName() string {
+	if z.VolumeClaimTemplate == nil || z.VolumeClaimTemplate.Spec.StorageClassName == nil {
+		return ""
+	}
+	return *z.VolumeClaimTemplate.Spec.StorageClassName
+}
+
 // OwnerRef returns the OwnerReference to be added to all resources created by Tenant
 func (t *Tenant) OwnerRef() []metav1.OwnerReference {
 	return []metav1.OwnerReference{
@@ -523,3 +523,99 @@ func TestTenant_ValidateDomains(t1 *testing.T) {
 		})
 	}
 }
+
+func TestTenant_ValidateUpdate(t *testing.T) {
+	standard := "standard"
+	fast := "fast"
+	pool := func(name string, servers, volumes int32, storageClass *string) Pool {
+		return Pool{
+			Name:             name,
+			Servers:          servers,
+			VolumesPerServer: volumes,
+			VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
+				Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: storageClass},
+			},
+		}
+	}
+	old := &Tenant{Spec: TenantSpec{Pools: []Pool{pool("pool-0", 4, 4, &standard)}}}
+	tests := []struct {
+		name                 string
+		pools                []Pool
+		serviceName          string
+		allowPoolReplacement bool
+		wantErr              bool
+	}{
+		{name: "unchanged", pools: []Pool{pool("pool-0", 4, 4, &standard)}},
+		{name: "default service name set", pools: []Pool{pool("pool-0", 4, 4, &standard)}, serviceName: DefaultMinIOServiceName},
+		{name: "service name changed", pools: []Pool{pool("pool-0", 4, 4, &standard)}, serviceName: "analytics", wantErr: true},
+		{name: "new pool", pools: []Pool{pool("pool-0", 4, 4, &standard), pool("pool-1", 2, 2, &fast)}},
+		{name: "pool replaced", pools: []Pool{pool("pool-1", 2, 2, &fast)}},
+		{name: "servers changed", pools: []Pool{pool("pool-0", 8, 4, &standard)}, wantErr: true},
+		{name: "volumes changed", pools: []Pool{pool("pool-0", 4, 2, &standard)}, wantErr: true},
+		{name: "servers changed with pool replacement", pools: []Pool{pool("pool-0", 8, 4, &standard)}, allowPoolReplacement: true},
+		{name: "volumes changed with pool replacement", pools: []Pool{pool("pool-0", 4, 2, &standard)}, allowPoolReplacement: true},
+		{name: "storage class changed", pools: []Pool{pool("pool-0", 4, 4, &fast)}, wantErr: true},
+		{name: "storage class changed with pool replacement", pools: []Pool{pool("pool-0", 4, 4, &fast)}, allowPoolReplacement: true, wantErr: true},
+		{name: "storage class removed", pools: []Pool{pool("pool-0", 4, 4, nil)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &Tenant{Spec: TenantSpec{Pools: tt.pools, ServiceName: tt.serviceName, AllowPoolReplacement: tt.allowPoolReplacement}}
+			err := tenant.ValidateUpdate(old)
+			assert.Equal(t, tt.wantErr, err != nil, "unexpected error %v", err)
+		})
+	}
+}
+
+func TestTenant_StatefulsetNameForPool(t *testing.T) {
+	pool := Pool{Name: "pool-0"}
+	tests := []struct {
+		name   string
+		status []PoolStatus
+		want   string
+	}{
+		{name: "not deployed", want: "minio-pool-0"},
+		{name: "deployed", status: []PoolStatus{{SSName: "minio-pool-0"}}, want: "minio-pool-0"},
+		{
+			name:   "being replaced",
+			status: []PoolStatus{{SSName: "minio-pool-0", Replacement: &PoolReplacementStatus{SSName: "minio-pool-0-r1"}}},
+			want:   "minio-pool-0-r1",
+		},
+		{
+			name: "replacement deployed",
+			status: []PoolStatus{
+				{SSName: "minio-pool-0", Replacement: &PoolReplacementStatus{SSName: "minio-pool-0-r1"}},
+				{SSName: "minio-pool-0-r1"},
+			},
+			want: "minio-pool-0-r1",
+		},
+		{name: "replaced", status: []PoolStatus{{SSName: "minio-pool-0-r2"}}, want: "minio-pool-0-r2"},
+		{name: "other pool", status: []PoolStatus{{SSName: "minio-pool-0-rack"}}, want: "minio-pool-0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &Tenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "minio"},
+				Status:     TenantStatus{Pools: tt.status},
+			}
+			assert.Equal(t, tt.want, tenant.StatefulsetNameForPool(&pool))
+		})
+	}
+
+	legacy := &Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio"},
+		Status:     TenantStatus{Pools: []PoolStatus{{SSName: "minio-zone-0"}}},
+	}
+	assert.Equal(t, "minio-zone-0", legacy.StatefulsetNameForPool(&Pool{Name: "ss-0"}))
+}
+
+func TestTenant_IsPoolRemoved(t *testing.T) {
+	tenant := &Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio"},
+		Spec:       TenantSpec{Pools: []Pool{{Name: "pool-0"}}},
+	}
+	assert.False(t, tenant.IsPoolRemoved(PoolStatus{SSName: "minio-pool-0", State: PoolInitialized}))
+	assert.False(t, tenant.IsPoolRemoved(PoolStatus{SSName: "minio-pool-0-r1", State: PoolDecommissioning}))
+	assert.True(t, tenant.IsPoolRemoved(PoolStatus{SSName: "minio-pool-1", State: PoolDecommissioning}))
+	assert.True(t, tenant.IsPoolRemoved(PoolStatus{SSName: "minio-pool-0", State: PoolInitialized, Replacement: &PoolReplacementStatus{}}))
+}
@@ -57,9 +57,11 @@ func (t *Tenant) MinIOHLServiceName() string {
 // MinIOCIServiceName returns the name of Cluster IP service that is created to communicate
 // with current MinIO StatefulSet pods
 func (t *Tenant) MinIOCIServiceName() string {
-	// DO NOT CHANGE, this should be constant
-	// This is possible because each namespace has only one Tenant
-	return "minio"
+	if t.Spec.ServiceName != "" {
+		return t.Spec.ServiceName
+	}
+	// DO NOT CHANGE, tenants created before the service name was configurable rely on it
+	return DefaultMinIOServiceName
 }
 
 // MinIOBucketBaseDomain returns the base domain name for buckets
@@ -163,6 +165,12 @@ func (t *Tenant) PoolStatefulsetName(pool *Pool) string {
 	return fmt.Sprintf("%s-%s", t.Name, pool.Name)
 }
 
+// PoolReplacementStatefulsetName returns the name of the statefulset of the n-th replacement of a pool, every
+// replacement gets a new name so the volumes of a replaced pool are never reused
+func (t *Tenant) PoolReplacementStatefulsetName(pool *Pool, n int) string {
+	return fmt.Sprintf("%s%s%d", t.PoolStatefulsetName(pool), PoolReplacementSuffix, n)
+}
+
 // LegacyStatefulsetName returns the name of a statefulset for a given pool
 func (t *Tenant) LegacyStatefulsetName(pool *Pool) string {
 	zoneName := strings.Replace(pool.Name, StatefulSetPrefix, StatefulSetLegacyPrefix, 1)
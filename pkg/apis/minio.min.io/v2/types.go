@@ -30,6 +30,7 @@ import (
 // +kubebuilder:resource:scope=Namespaced,shortName=tenant,singular=tenant
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.currentState"
 // +kubebuilder:printcolumn:name="Health",type="string",JSONPath=".status.healthStatus"
+// +kubebuilder:printcolumn:name="Paused",type="boolean",JSONPath=".spec.paused"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:metadata:annotations=operator.min.io/version=v6.0.0
 // +kubebuilder:storageversion
@@ -110,6 +111,47 @@ type TenantSpec struct {
 	Pools []Pool `json:"pools"`
 	// *Optional* +
 	//
+	// Allows the Operator to replace a pool whose `servers` or `volumesPerServer` changed. The Operator deploys a new pool with the new geometry, waits for it to initialize, decommissions the existing pool through MinIO and removes it. +
+	//
+	// Without it, the server and volume count of a deployed pool can't be changed. Replacing a pool moves all its data and requires enough capacity in the new pool. +
+	// +optional
+	AllowPoolReplacement bool `json:"allowPoolReplacement,omitempty"`
+	// *Optional* +
+	//
+	// Pauses the reconciliation of the tenant. While paused, the Operator doesn't create, update or restart any of the tenant resources, doesn't upgrade MinIO and doesn't check the tenant health. Deleting the tenant is still handled. +
+	//
+	// Set it back to `false` to resume the reconciliation. +
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// *Optional* +
+	//
+	// Hibernates the tenant. The Operator scales every pool and KES to zero pods, the volumes and their data are kept. The health of a hibernated tenant isn't checked. +
+	//
+	// Set it back to `false` to resume the tenant, the Operator restores the pods and waits for MinIO to be healthy before reporting the tenant as `Initialized`. +
+	// +optional
+	Hibernate bool `json:"hibernate,omitempty"`
+	// *Optional* +
+	//
+	// Controls how the Operator upgrades MinIO when `spec.image` changes. When set, the Operator checks the health of the tenant before upgrading, verifies every server runs the new release after the upgrade and can roll back to the previous image if the upgrade fails. +
+	// +optional
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+	// *Optional* +
+	//
+	// Where the Operator gets the MinIO binary served to the tenant for in-place upgrades. Defaults to pulling `spec.image` from its registry, set it to upgrade tenants of air-gapped clusters from an OCI image layout, an image tarball or a binary bundle in a volume mounted in the Operator pod. +
+	// +optional
+	UpgradeSource *UpgradeSource `json:"upgradeSource,omitempty"`
+	// *Optional* +
+	//
+	// Checks the Operator runs on the image of an upgrade before serving its MinIO binary to the tenant. The upgrade is rejected if any check fails. +
+	// +optional
+	ImageVerification *ImageVerification `json:"imageVerification,omitempty"`
+	// *Optional* +
+	//
+	// Windows during which the Operator applies disruptive changes: MinIO upgrades, restarts of MinIO to add a pool and rollouts of the pool StatefulSets. Outside of the windows these changes are reported as pending in `status.pendingChanges` until the next window opens. When empty, changes are applied right away. +
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// *Optional* +
+	//
 	// The Docker image to use when deploying `minio` server pods. Defaults to {minio-image}. +
 	//
 	// +optional
@@ -314,6 +356,13 @@ type TenantSpec struct {
 	ServiceMetadata *ServiceMetadata `json:"serviceMetadata,omitempty"`
 	// *Optional* +
 	//
+	// The name of the MinIO service of the tenant. Defaults to `minio`. +
+	//
+	// Every Tenant in a namespace must use its own service name, set a distinct value when deploying several Tenants in the same namespace. The service name cannot be changed after the Tenant is created. +
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+	// *Optional* +
+	//
 	// An array of https://kubernetes.io/docs/concepts/configuration/secret/[Kubernetes opaque secrets] to use for generating MinIO users during tenant provisioning. +
 	//
 	// Each element in the array is an object consisting of a key-value pair `name: <string>`, where the `<string>` references an opaque Kubernetes secret. +
@@ -358,6 +407,48 @@ type TenantSpec struct {
 	// If provided, statefulset will add these volumes. You should set the rules for the corresponding volumes and volume mounts. We will not test this rule, k8s will show the result.
 	// +optional
 	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+	// *Optional* +
+	//
+	// Directs the Operator to rebalance the data across all pools once a new pool is added to the tenant and all pools are initialized. +
+	//
+	// A running rebalance can be stopped by annotating the tenant with `min.io/pool-rebalance: stop` and resumed by removing the annotation. +
+	// +optional
+	PoolRebalance *PoolRebalanceConfig `json:"poolRebalance,omitempty"`
+	// *Optional* +
+	//
+	// Directs the Operator on what to keep once the tenant is deleted. Specify one of the following: +
+	//
+	// * `Retain` (Default) keeps the PVCs holding the data and the TLS certificate secrets generated by the Operator +
+	//
+	// * `RetainAndLabel` keeps them and labels them with `min.io/retained-from: <tenant-name>` so they can be found later +
+	//
+	// * `Delete` deletes them along with the tenant +
+	//
+	// The certificate signing requests, the Prometheus scrape config and the PodDisruptionBudgets of the tenant are deleted with any policy. +
+	// +optional
+	// +kubebuilder:validation:Enum=Retain;RetainAndLabel;Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// DeletionPolicy is what the Operator keeps of a deleted tenant
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the PVCs and the generated TLS certificate secrets of a deleted tenant
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyRetainAndLabel keeps the PVCs and the generated TLS certificate secrets of a deleted tenant, and labels them
+	DeletionPolicyRetainAndLabel DeletionPolicy = "RetainAndLabel"
+	// DeletionPolicyDelete deletes the PVCs and the generated TLS certificate secrets along with the tenant
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+)
+
+// PoolRebalanceConfig (`poolRebalance`) defines the policy to rebalance the data across the pools of the tenant after an expansion. +
+type PoolRebalanceConfig struct {
+	// *Optional* +
+	//
+	// Directs the Operator to start a rebalance after a new pool is added to the tenant. Defaults to `false`. +
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
 }
 
 // Logging describes Logging for MinIO tenants.
@@ -437,6 +528,10 @@ const (
 	PoolCreated PoolState = "PoolCreated"
 	// PoolInitialized indicates if a pool has been observed to be online
 	PoolInitialized PoolState = "PoolInitialized"
+	// PoolDecommissioning indicates a pool was removed from the spec and MinIO is moving its data to the remaining pools
+	PoolDecommissioning PoolState = "PoolDecommissioning"
+	// PoolDecommissioned indicates MinIO finished moving the data out of a removed pool, and it can be deleted
+	PoolDecommissioned PoolState = "PoolDecommissioned"
 )
 
 // PoolStatus keeps track of all the pools and their current state
@@ -448,6 +543,298 @@ type PoolStatus struct {
 	// Security Context
 	// +optional
 	LegacySecurityContext bool `json:"legacySecurityContext"`
+	// *Optional* +
+	//
+	// Progress of the decommission of this pool, only set while the pool is being decommissioned
+	// +optional
+	Decommission *PoolDecommissionStatus `json:"decommission,omitempty"`
+	// *Optional* +
+	//
+	// Progress of the replacement of this pool by a pool with a new geometry, only set while the pool is being replaced
+	// +optional
+	Replacement *PoolReplacementStatus `json:"replacement,omitempty"`
+}
+
+// PoolReplacementStage represents the stage of the replacement of a pool
+type PoolReplacementStage string
+
+const (
+	// PoolReplacementCreating indicates the statefulset of the new pool is being created
+	PoolReplacementCreating PoolReplacementStage = "CreatingPool"
+	// PoolReplacementInitializing indicates the Operator waits for MinIO to bring the new pool online
+	PoolReplacementInitializing PoolReplacementStage = "InitializingPool"
+	// PoolReplacementDecommissioning indicates MinIO is moving the data of the replaced pool to the other pools
+	PoolReplacementDecommissioning PoolReplacementStage = "Decommissioning"
+	// PoolReplacementRemoving indicates the replaced pool is being removed from MinIO and deleted
+	PoolReplacementRemoving PoolReplacementStage = "RemovingPool"
+)
+
+// PoolReplacementStatus reports the progress of the replacement of a pool
+type PoolReplacementStatus struct {
+	// Stage of the replacement
+	Stage PoolReplacementStage `json:"stage"`
+	// SSName is the name of the statefulset of the new pool
+	SSName string `json:"ssName"`
+	// CmdLine is the pool argument MinIO serves the replaced pool with, kept until the pool is removed
+	CmdLine string `json:"cmdLine"`
+	// Servers is the number of servers of the replaced pool
+	Servers int32 `json:"servers"`
+	// VolumesPerServer is the number of volumes per server of the replaced pool
+	VolumesPerServer int32 `json:"volumesPerServer"`
+	// StartTime is when the Operator started replacing the pool
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// PoolDecommissionStatus reports the progress of a pool decommission as reported by MinIO
+type PoolDecommissionStatus struct {
+	// CmdLine is the pool argument MinIO was started with, used to address the pool in the decommission API
+	CmdLine string `json:"cmdLine"`
+	// StartTime is when MinIO started decommissioning the pool
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// LastUpdate is the last time the progress was fetched from MinIO
+	// +optional
+	LastUpdate *metav1.Time `json:"lastUpdate,omitempty"`
+	// ETA is the estimated completion time of the decommission
+	// +optional
+	ETA *metav1.Time `json:"eta,omitempty"`
+	// TotalSize is the total capacity of the pool in bytes
+	// +optional
+	TotalSize int64 `json:"totalSize,omitempty"`
+	// CurrentSize is the free space of the pool in bytes
+	// +optional
+	CurrentSize int64 `json:"currentSize,omitempty"`
+	// BytesDone is the amount of bytes moved out of the pool
+	// +optional
+	BytesDone int64 `json:"bytesDone,omitempty"`
+	// BytesFailed is the amount of bytes that failed to be moved out of the pool
+	// +optional
+	BytesFailed int64 `json:"bytesFailed,omitempty"`
+	// ObjectsDone is the number of objects moved out of the pool
+	// +optional
+	ObjectsDone int64 `json:"objectsDone,omitempty"`
+	// ObjectsFailed is the number of objects that failed to be moved out of the pool
+	// +optional
+	ObjectsFailed int64 `json:"objectsFailed,omitempty"`
+	// Complete is set once MinIO reports all the data was moved out of the pool
+	// +optional
+	Complete bool `json:"complete,omitempty"`
+	// Failed is set if MinIO reports the decommission failed
+	// +optional
+	Failed bool `json:"failed,omitempty"`
+}
+
+// UpgradeStrategy (`upgradeStrategy`) defines the checks the Operator runs around a MinIO upgrade.
+type UpgradeStrategy struct {
+	// *Optional* +
+	//
+	// Checks the tenant is healthy, has no healing drives and keeps the quorum margin before upgrading MinIO. The upgrade is postponed until the checks pass. +
+	// +optional
+	PreflightChecks bool `json:"preflightChecks,omitempty"`
+	// *Optional* +
+	//
+	// The minimum number of online drives above the write quorum required by the pre-flight checks. Defaults to `0`. +
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	QuorumMargin int32 `json:"quorumMargin,omitempty"`
+	// *Optional* +
+	//
+	// Restarts MinIO on the previous image if the upgrade fails or a server doesn't report the new release afterwards. The upgrade isn't retried until `spec.image` changes. +
+	// +optional
+	AutoRollback bool `json:"autoRollback,omitempty"`
+}
+
+// MaintenanceWindow (`maintenanceWindows`) defines a recurring window during which the Operator applies disruptive changes to the tenant.
+type MaintenanceWindow struct {
+	// *Required* +
+	//
+	// When the window opens, in Cron format, e.g. `0 2 * * sat` or `@daily`. +
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// *Required* +
+	//
+	// How long the window stays open, e.g. `4h`. A change is started only while the window is open, a change started within the window is not interrupted when it closes. +
+	Duration metav1.Duration `json:"duration"`
+	// *Optional* +
+	//
+	// The IANA time zone the schedule is evaluated in, e.g. `Europe/Paris`. Defaults to `UTC`. +
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// UpgradeSourceType is the kind of source the Operator gets the MinIO binary from
+type UpgradeSourceType string
+
+const (
+	// UpgradeSourceRegistry pulls the image from its registry
+	UpgradeSourceRegistry UpgradeSourceType = "Registry"
+	// UpgradeSourceOCILayout reads the image from an OCI image layout directory
+	UpgradeSourceOCILayout UpgradeSourceType = "OCILayout"
+	// UpgradeSourceImageTarball reads the image from a tarball written by `docker save` or `crane pull`
+	UpgradeSourceImageTarball UpgradeSourceType = "ImageTarball"
+	// UpgradeSourceBundle reads the `minio`, `minio.sha256sum` and `minio.minisig` files from a directory
+	UpgradeSourceBundle UpgradeSourceType = "Bundle"
+)
+
+// UpgradeSource (`upgradeSource`) defines where the Operator gets the MinIO binary from for in-place upgrades.
+type UpgradeSource struct {
+	// *Optional* +
+	//
+	// The kind of source: `Registry`, `OCILayout`, `ImageTarball` or `Bundle`. Defaults to `Registry`. +
+	// +optional
+	// +kubebuilder:validation:Enum=Registry;OCILayout;ImageTarball;Bundle
+	Type UpgradeSourceType `json:"type,omitempty"`
+	// *Optional* +
+	//
+	// The absolute path of the source in a volume mounted in the Operator pod under its upgrade source root, `/artifacts` unless `OPERATOR_UPGRADE_SOURCE_ROOT` is set: the OCI image layout directory, the image tarball or the directory of the bundle. The volume can be a PersistentVolumeClaim or a ConfigMap mounted through the Helm chart, the Operator doesn't read them from the tenant namespace. Required for all the types but `Registry`. +
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// ImageVerification (`imageVerification`) defines the checks run on the image of a MinIO upgrade.
+type ImageVerification struct {
+	// *Optional* +
+	//
+	// The digests the image may have, e.g. `sha256:4f9d...`. Either the digest of the image or the digest of its multi-platform index must be listed. +
+	// +optional
+	AllowedDigests []string `json:"allowedDigests,omitempty"`
+	// *Optional* +
+	//
+	// The Secret key holding the PEM encoded cosign public key the image must be signed with. The signature is looked up in the registry of the image, it can't be verified for the other upgrade sources. +
+	// +optional
+	CosignPublicKey *corev1.SecretKeySelector `json:"cosignPublicKey,omitempty"`
+	// *Optional* +
+	//
+	// Requires the `release` label of the image to match the tag of `spec.image`. +
+	// +optional
+	RequireMatchingRelease bool `json:"requireMatchingRelease,omitempty"`
+	// *Optional* +
+	//
+	// The oldest release the image may have, e.g. `RELEASE.2024-03-15T01-07-19Z`. +
+	// +optional
+	MinimumRelease string `json:"minimumRelease,omitempty"`
+}
+
+// UpgradeResult represents the outcome of a MinIO upgrade
+type UpgradeResult string
+
+const (
+	// UpgradeInProgress indicates the operator is upgrading MinIO
+	UpgradeInProgress UpgradeResult = "InProgress"
+	// UpgradeVerifying indicates MinIO swapped its binary and the operator waits for every server to report the new release
+	UpgradeVerifying UpgradeResult = "Verifying"
+	// UpgradeRollingBack indicates the upgrade failed and the operator restarts the MinIO pods one at a time on the previous image
+	UpgradeRollingBack UpgradeResult = "RollingBack"
+	// UpgradeSucceeded indicates every MinIO server runs the new release
+	UpgradeSucceeded UpgradeResult = "Succeeded"
+	// UpgradeFailed indicates the upgrade failed, it is retried on the next reconciliation
+	UpgradeFailed UpgradeResult = "Failed"
+	// UpgradeRolledBack indicates the upgrade failed and MinIO was restarted on the previous image
+	UpgradeRolledBack UpgradeResult = "RolledBack"
+	// UpgradeRejected indicates the image failed the verification policy, it's verified again once the policy, the
+	// upgrade source or the cosign public key changes
+	UpgradeRejected UpgradeResult = "Rejected"
+)
+
+// UpgradeRecord reports a MinIO upgrade run by the operator
+type UpgradeRecord struct {
+	// FromImage is the image MinIO was running before the upgrade
+	// +optional
+	FromImage string `json:"fromImage,omitempty"`
+	// ToImage is the image MinIO is upgraded to
+	ToImage string `json:"toImage"`
+	// FromVersion is the release MinIO reported before the upgrade
+	// +optional
+	FromVersion string `json:"fromVersion,omitempty"`
+	// ToVersion is the release of the new image
+	// +optional
+	ToVersion string `json:"toVersion,omitempty"`
+	// Digest is the digest the new image was verified with, the pools run the image pinned to this digest
+	// +optional
+	Digest string `json:"digest,omitempty"`
+	// VerificationHash identifies the verification policy, upgrade source and cosign public key the image was
+	// rejected with
+	// +optional
+	VerificationHash string `json:"verificationHash,omitempty"`
+	// Result of the upgrade
+	Result UpgradeResult `json:"result"`
+	// Message explains why the upgrade failed or was rolled back
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is when the operator started the upgrade
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// UpdateTime is when MinIO was asked to swap its binary, the pods started before run the new release until restarted
+	// +optional
+	UpdateTime *metav1.Time `json:"updateTime,omitempty"`
+	// CompletionTime is when the upgrade succeeded, failed or was rolled back
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// PoolRebalanceState represents the state of a pool rebalance started by the operator
+type PoolRebalanceState string
+
+const (
+	// PoolRebalancePending indicates a new pool was added and a rebalance will start once all pools are initialized
+	PoolRebalancePending PoolRebalanceState = "Pending"
+	// PoolRebalanceRunning indicates MinIO is rebalancing the data across the pools
+	PoolRebalanceRunning PoolRebalanceState = "Running"
+	// PoolRebalanceStopped indicates the rebalance was stopped through the tenant annotation or in MinIO
+	PoolRebalanceStopped PoolRebalanceState = "Stopped"
+	// PoolRebalanceCompleted indicates MinIO finished rebalancing the data across the pools
+	PoolRebalanceCompleted PoolRebalanceState = "Completed"
+	// PoolRebalanceFailed indicates MinIO stopped the rebalance on its own before completing it
+	PoolRebalanceFailed PoolRebalanceState = "Failed"
+)
+
+// PoolRebalanceStatus reports the progress of a pool rebalance as reported by MinIO
+type PoolRebalanceStatus struct {
+	// ID identifies the rebalance operation in MinIO
+	// +optional
+	ID string `json:"id,omitempty"`
+	// State of the rebalance
+	State PoolRebalanceState `json:"state"`
+	// StartTime is when the operator started the rebalance
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// StopTime is when the rebalance completed, failed or was stopped
+	// +optional
+	StopTime *metav1.Time `json:"stopTime,omitempty"`
+	// LastUpdate is the last time the progress was fetched from MinIO
+	// +optional
+	LastUpdate *metav1.Time `json:"lastUpdate,omitempty"`
+	// Pools reports the progress of the rebalance for each pool
+	// +optional
+	Pools []PoolRebalanceProgress `json:"pools,omitempty"`
+}
+
+// PoolRebalanceProgress reports the progress of a rebalance on a single pool
+type PoolRebalanceProgress struct {
+	// ID is the index of the pool in MinIO
+	ID int32 `json:"id"`
+	// SSName is the name of the statefulset of the pool, if known
+	// +optional
+	SSName string `json:"ssName,omitempty"`
+	// Status is reported by MinIO as `Active` while the rebalance is running on the pool
+	// +optional
+	Status string `json:"status,omitempty"`
+	// UsedPercentage is the percentage of the pool capacity in use, with two decimals
+	// +optional
+	UsedPercentage string `json:"usedPercentage,omitempty"`
+	// Objects is the number of objects moved out of the pool
+	// +optional
+	Objects int64 `json:"objects,omitempty"`
+	// Versions is the number of object versions moved out of the pool
+	// +optional
+	Versions int64 `json:"versions,omitempty"`
+	// Bytes is the amount of bytes moved out of the pool
+	// +optional
+	Bytes int64 `json:"bytes,omitempty"`
+	// ETA is the estimated completion time of the rebalance on the pool
+	// +optional
+	ETA *metav1.Time `json:"eta,omitempty"`
 }
 
 // HealthStatus represents whether the tenant is healthy, with decreased service or offline
@@ -462,6 +849,25 @@ const (
 	HealthStatusRed HealthStatus = "red"
 )
 
+// Types of the conditions of a tenant
+const (
+	// TenantConditionReady indicates the tenant is deployed as specified, initialized and hasn't lost its quorum
+	TenantConditionReady = "Ready"
+	// TenantConditionProgressing indicates the Operator is deploying or changing the tenant
+	TenantConditionProgressing = "Progressing"
+	// TenantConditionDegraded indicates the Operator can't reconcile the tenant or the tenant is unhealthy, the message
+	// tells why
+	TenantConditionDegraded = "Degraded"
+	// TenantConditionCertificatesReady indicates the TLS certificates of the tenant are available
+	TenantConditionCertificatesReady = "CertificatesReady"
+	// TenantConditionKESReady indicates KES is deployed, only set if KES is enabled
+	TenantConditionKESReady = "KESReady"
+	// TenantConditionUpgradeInProgress indicates the Operator is updating the MinIO or KES version of the tenant
+	TenantConditionUpgradeInProgress = "UpgradeInProgress"
+	// TenantConditionPoolsInitialized indicates all the pools of the tenant have been observed online
+	TenantConditionPoolsInitialized = "PoolsInitialized"
+)
+
 // TierUsage represents the usage from a tier setup by the tenant
 type TierUsage struct {
 	// Name of the tier
@@ -493,6 +899,7 @@ type TenantUsage struct {
 
 // TenantStatus is the status for a Tenant resource
 type TenantStatus struct {
+	// CurrentState is the last state reported by the Operator, the conditions keep track of each aspect of the tenant
 	CurrentState      string `json:"currentState"`
 	AvailableReplicas int32  `json:"availableReplicas"`
 	Revision          int32  `json:"revision"`
@@ -543,6 +950,39 @@ type TenantStatus struct {
 	// Health Message regarding the State of the tenant
 	// ProvisionedBuckets keeps track for telling if operator already created initial buckets for the tenant
 	ProvisionedBuckets bool `json:"provisionedBuckets,omitempty"`
+	// *Optional* +
+	//
+	// Progress of the pool rebalance started by the operator after an expansion
+	// +optional
+	Rebalance *PoolRebalanceStatus `json:"rebalance,omitempty"`
+	// *Optional* +
+	//
+	// The generation of the tenant spec the status reflects
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// *Optional* +
+	//
+	// The last MinIO upgrades of the tenant, the most recent last
+	// +optional
+	UpgradeHistory []UpgradeRecord `json:"upgradeHistory,omitempty"`
+	// *Optional* +
+	//
+	// The disruptive changes waiting for the next maintenance window
+	// +optional
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+	// *Optional* +
+	//
+	// When the next maintenance window opens, set while changes are pending
+	// +optional
+	NextMaintenanceWindow *metav1.Time `json:"nextMaintenanceWindow,omitempty"`
+	// *Optional* +
+	//
+	// Conditions of the tenant: Ready, Progressing, Degraded, CertificatesReady, KESReady, UpgradeInProgress and
+	// PoolsInitialized
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // CertificateConfig (`certConfig`) defines controlling attributes associated to any TLS certificate automatically generated by the Operator as part of tenant creation. These fields have no effect if `spec.autoCert: false`.
@@ -611,14 +1051,12 @@ type Pool struct {
 	// Specify the name of the pool. The Operator automatically generates the pool name if this field is omitted.
 	Name string `json:"name"`
 	// *Required*
-	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="servers is immutable"
 	//
 	// The number of MinIO server pods to deploy in the pool. The minimum value is `2`.
 	//
 	// The MinIO Operator requires a minimum of `4` volumes per pool. Specifically, the result of `pools.servers X pools.volumesPerServer` must be greater than `4`. +
 	Servers int32 `json:"servers"`
 	// *Required* +
-	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="volumesPerServer is immutable"
 	//
 	// The number of Persistent Volume Claims to generate for each MinIO server pod in the pool. +
 	//
@@ -23,6 +23,7 @@ package v2
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -227,6 +228,32 @@ func (in *Features) DeepCopy() *Features {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVerification) DeepCopyInto(out *ImageVerification) {
+	*out = *in
+	if in.AllowedDigests != nil {
+		in, out := &in.AllowedDigests, &out.AllowedDigests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CosignPublicKey != nil {
+		in, out := &in.CosignPublicKey, &out.CosignPublicKey
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVerification.
+func (in *ImageVerification) DeepCopy() *ImageVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KESConfig) DeepCopyInto(out *KESConfig) {
 	*out = *in
@@ -348,6 +375,23 @@ func (in *Logging) DeepCopy() *Logging {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pool) DeepCopyInto(out *Pool) {
 	*out = *in
@@ -425,9 +469,138 @@ func (in *Pool) DeepCopy() *Pool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolDecommissionStatus) DeepCopyInto(out *PoolDecommissionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUpdate != nil {
+		in, out := &in.LastUpdate, &out.LastUpdate
+		*out = (*in).DeepCopy()
+	}
+	if in.ETA != nil {
+		in, out := &in.ETA, &out.ETA
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolDecommissionStatus.
+func (in *PoolDecommissionStatus) DeepCopy() *PoolDecommissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolDecommissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolRebalanceConfig) DeepCopyInto(out *PoolRebalanceConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolRebalanceConfig.
+func (in *PoolRebalanceConfig) DeepCopy() *PoolRebalanceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolRebalanceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolRebalanceProgress) DeepCopyInto(out *PoolRebalanceProgress) {
+	*out = *in
+	if in.ETA != nil {
+		in, out := &in.ETA, &out.ETA
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolRebalanceProgress.
+func (in *PoolRebalanceProgress) DeepCopy() *PoolRebalanceProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolRebalanceProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolRebalanceStatus) DeepCopyInto(out *PoolRebalanceStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StopTime != nil {
+		in, out := &in.StopTime, &out.StopTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUpdate != nil {
+		in, out := &in.LastUpdate, &out.LastUpdate
+		*out = (*in).DeepCopy()
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]PoolRebalanceProgress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolRebalanceStatus.
+func (in *PoolRebalanceStatus) DeepCopy() *PoolRebalanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolRebalanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolReplacementStatus) DeepCopyInto(out *PoolReplacementStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolReplacementStatus.
+func (in *PoolReplacementStatus) DeepCopy() *PoolReplacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolReplacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolStatus) DeepCopyInto(out *PoolStatus) {
 	*out = *in
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = new(PoolDecommissionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replacement != nil {
+		in, out := &in.Replacement, &out.Replacement
+		*out = new(PoolReplacementStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -636,6 +809,26 @@ func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(UpgradeStrategy)
+		**out = **in
+	}
+	if in.UpgradeSource != nil {
+		in, out := &in.UpgradeSource, &out.UpgradeSource
+		*out = new(UpgradeSource)
+		**out = **in
+	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(ImageVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
 	out.ImagePullSecret = in.ImagePullSecret
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
@@ -778,6 +971,11 @@ func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PoolRebalance != nil {
+		in, out := &in.PoolRebalance, &out.PoolRebalance
+		*out = new(PoolRebalanceConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -798,13 +996,43 @@ func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 	if in.Pools != nil {
 		in, out := &in.Pools, &out.Pools
 		*out = make([]PoolStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.WaitingOnReady != nil {
 		in, out := &in.WaitingOnReady, &out.WaitingOnReady
 		*out = (*in).DeepCopy()
 	}
 	in.Usage.DeepCopyInto(&out.Usage)
+	if in.Rebalance != nil {
+		in, out := &in.Rebalance, &out.Rebalance
+		*out = new(PoolRebalanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeHistory != nil {
+		in, out := &in.UpgradeHistory, &out.UpgradeHistory
+		*out = make([]UpgradeRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NextMaintenanceWindow != nil {
+		in, out := &in.NextMaintenanceWindow, &out.NextMaintenanceWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -854,3 +1082,63 @@ func (in *TierUsage) DeepCopy() *TierUsage {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeRecord) DeepCopyInto(out *UpgradeRecord) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdateTime != nil {
+		in, out := &in.UpdateTime, &out.UpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeRecord.
+func (in *UpgradeRecord) DeepCopy() *UpgradeRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeSource) DeepCopyInto(out *UpgradeSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeSource.
+func (in *UpgradeSource) DeepCopy() *UpgradeSource {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStrategy) DeepCopyInto(out *UpgradeStrategy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStrategy.
+func (in *UpgradeStrategy) DeepCopy() *UpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -69,6 +69,13 @@ type PolicyBindingSpec struct {
 	Application *Application `json:"application"`
 	// *Required* +
 	Policies []string `json:"policies"`
+	// *Optional* +
+	//
+	// The name of the Tenant in the namespace of the PolicyBinding the policies are granted on. +
+	//
+	// Required to tell the Tenants apart when the namespace holds more than one Tenant, a PolicyBinding without tenant only applies while the namespace holds a single Tenant.
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
 }
 
 // Application defines the `Namespace` and `ServiceAccount` to authorize the usage of the policies listed
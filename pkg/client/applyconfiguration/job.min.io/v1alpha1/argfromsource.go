@@ -0,0 +1,57 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// ArgFromSourceApplyConfiguration represents an declarative configuration of the ArgFromSource type for use
+// with apply.
+type ArgFromSourceApplyConfiguration struct {
+	Name      *string                           `json:"name,omitempty"`
+	ValueFrom *ArgValueSourceApplyConfiguration `json:"valueFrom,omitempty"`
+	AsFile    *bool                             `json:"asFile,omitempty"`
+}
+
+// ArgFromSourceApplyConfiguration constructs an declarative configuration of the ArgFromSource type for use with
+// apply.
+func ArgFromSource() *ArgFromSourceApplyConfiguration {
+	return &ArgFromSourceApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *ArgFromSourceApplyConfiguration) WithName(value string) *ArgFromSourceApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithValueFrom sets the ValueFrom field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ValueFrom field is set to the value of the last call.
+func (b *ArgFromSourceApplyConfiguration) WithValueFrom(value *ArgValueSourceApplyConfiguration) *ArgFromSourceApplyConfiguration {
+	b.ValueFrom = value
+	return b
+}
+
+// WithAsFile sets the AsFile field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AsFile field is set to the value of the last call.
+func (b *ArgFromSourceApplyConfiguration) WithAsFile(value bool) *ArgFromSourceApplyConfiguration {
+	b.AsFile = &value
+	return b
+}
@@ -0,0 +1,52 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// BatchJobSpecApplyConfiguration represents an declarative configuration of the BatchJobSpec type for use
+// with apply.
+type BatchJobSpecApplyConfiguration struct {
+	Definition      *string                  `json:"definition,omitempty"`
+	ConfigMapKeyRef *v1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// BatchJobSpecApplyConfiguration constructs an declarative configuration of the BatchJobSpec type for use with
+// apply.
+func BatchJobSpec() *BatchJobSpecApplyConfiguration {
+	return &BatchJobSpecApplyConfiguration{}
+}
+
+// WithDefinition sets the Definition field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Definition field is set to the value of the last call.
+func (b *BatchJobSpecApplyConfiguration) WithDefinition(value string) *BatchJobSpecApplyConfiguration {
+	b.Definition = &value
+	return b
+}
+
+// WithConfigMapKeyRef sets the ConfigMapKeyRef field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ConfigMapKeyRef field is set to the value of the last call.
+func (b *BatchJobSpecApplyConfiguration) WithConfigMapKeyRef(value v1.ConfigMapKeySelector) *BatchJobSpecApplyConfiguration {
+	b.ConfigMapKeyRef = &value
+	return b
+}
@@ -0,0 +1,115 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BatchJobStatusApplyConfiguration represents an declarative configuration of the BatchJobStatus type for use
+// with apply.
+type BatchJobStatusApplyConfiguration struct {
+	ID               *string  `json:"id,omitempty"`
+	Type             *string  `json:"type,omitempty"`
+	Objects          *int64   `json:"objects,omitempty"`
+	ObjectsFailed    *int64   `json:"objectsFailed,omitempty"`
+	BytesTransferred *int64   `json:"bytesTransferred,omitempty"`
+	BytesFailed      *int64   `json:"bytesFailed,omitempty"`
+	LastBucket       *string  `json:"lastBucket,omitempty"`
+	LastObject       *string  `json:"lastObject,omitempty"`
+	LastUpdate       *v1.Time `json:"lastUpdate,omitempty"`
+}
+
+// BatchJobStatusApplyConfiguration constructs an declarative configuration of the BatchJobStatus type for use with
+// apply.
+func BatchJobStatus() *BatchJobStatusApplyConfiguration {
+	return &BatchJobStatusApplyConfiguration{}
+}
+
+// WithID sets the ID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ID field is set to the value of the last call.
+func (b *BatchJobStatusApplyConfiguration) WithID(value string) *BatchJobStatusApplyConfiguration {
+	b.ID = &value
+	return b
+}
+
+// WithType sets the Type field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Type field is set to the value of the last call.
+func (b *BatchJobStatusApplyConfiguration) WithType(value string) *BatchJobStatusApplyConfiguration {
+	b.Type = &value
+	return b
+}
+
+// WithObjects sets the Objects field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Objects field is set to the value of the last call.
+func (b *BatchJobStatusApplyConfiguration) WithObjects(value int64) *BatchJobStatusApplyConfiguration {
+	b.Objects = &value
+	return b
+}
+
+// WithObjectsFailed sets the ObjectsFailed field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObjectsFailed field is set to the value of the last call.
+func (b *BatchJobStatusApplyConfiguration) WithObjectsFailed(value int64) *BatchJobStatusApplyConfiguration {
+	b.ObjectsFailed = &value
+	return b
+}
+
+// WithBytesTransferred sets the BytesTransferred field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BytesTransferred field is set to the value of the last call.
+func (b *BatchJobStatusApplyConfiguration) WithBytesTransferred(value int64) *BatchJobStatusApplyConfiguration {
+	b.BytesTransferred = &value
+	return b
+}
+
+// WithBytesFailed sets the BytesFailed field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BytesFailed field is set to the value of the last call.
+func (b *BatchJobStatusApplyConfiguration) WithBytesFailed(value int64) *BatchJobStatusApplyConfiguration {
+	b.BytesFailed = &value
+	return b
+}
+
+// WithLastBucket sets the LastBucket field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastBucket field is set to the value of the last call.
+func (b *BatchJobStatusApplyConfiguration) WithLastBucket(value string) *BatchJobStatusApplyConfiguration {
+	b.LastBucket = &value
+	return b
+}
+
+// WithLastObject sets the LastObject field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastObject field is set to the value of the last call.
+func (b *BatchJobStatusApplyConfiguration) WithLastObject(value string) *BatchJobStatusApplyConfiguration {
+	b.LastObject = &value
+	return b
+}
+
+// WithLastUpdate sets the LastUpdate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastUpdate field is set to the value of the last call.
+func (b *BatchJobStatusApplyConfiguration) WithLastUpdate(value v1.Time) *BatchJobStatusApplyConfiguration {
+	b.LastUpdate = &value
+	return b
+}
@@ -0,0 +1,70 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// CommandPlanApplyConfiguration represents an declarative configuration of the CommandPlan type for use
+// with apply.
+type CommandPlanApplyConfiguration struct {
+	Name      *string  `json:"name,omitempty"`
+	Command   []string `json:"command,omitempty"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+	Stage     *int32   `json:"stage,omitempty"`
+}
+
+// CommandPlanApplyConfiguration constructs an declarative configuration of the CommandPlan type for use with
+// apply.
+func CommandPlan() *CommandPlanApplyConfiguration {
+	return &CommandPlanApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *CommandPlanApplyConfiguration) WithName(value string) *CommandPlanApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithCommand adds the given value to the Command field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Command field.
+func (b *CommandPlanApplyConfiguration) WithCommand(values ...string) *CommandPlanApplyConfiguration {
+	for i := range values {
+		b.Command = append(b.Command, values[i])
+	}
+	return b
+}
+
+// WithDependsOn adds the given value to the DependsOn field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the DependsOn field.
+func (b *CommandPlanApplyConfiguration) WithDependsOn(values ...string) *CommandPlanApplyConfiguration {
+	for i := range values {
+		b.DependsOn = append(b.DependsOn, values[i])
+	}
+	return b
+}
+
+// WithStage sets the Stage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Stage field is set to the value of the last call.
+func (b *CommandPlanApplyConfiguration) WithStage(value int32) *CommandPlanApplyConfiguration {
+	b.Stage = &value
+	return b
+}
@@ -0,0 +1,75 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// CommandPolicyApplyConfiguration represents an declarative configuration of the CommandPolicy type for use
+// with apply.
+type CommandPolicyApplyConfiguration struct {
+	Retries                 *int32 `json:"retries,omitempty"`
+	BackoffSeconds          *int32 `json:"backoffSeconds,omitempty"`
+	MaxBackoffSeconds       *int32 `json:"maxBackoffSeconds,omitempty"`
+	TimeoutSeconds          *int64 `json:"timeoutSeconds,omitempty"`
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// CommandPolicyApplyConfiguration constructs an declarative configuration of the CommandPolicy type for use with
+// apply.
+func CommandPolicy() *CommandPolicyApplyConfiguration {
+	return &CommandPolicyApplyConfiguration{}
+}
+
+// WithRetries sets the Retries field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Retries field is set to the value of the last call.
+func (b *CommandPolicyApplyConfiguration) WithRetries(value int32) *CommandPolicyApplyConfiguration {
+	b.Retries = &value
+	return b
+}
+
+// WithBackoffSeconds sets the BackoffSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BackoffSeconds field is set to the value of the last call.
+func (b *CommandPolicyApplyConfiguration) WithBackoffSeconds(value int32) *CommandPolicyApplyConfiguration {
+	b.BackoffSeconds = &value
+	return b
+}
+
+// WithMaxBackoffSeconds sets the MaxBackoffSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxBackoffSeconds field is set to the value of the last call.
+func (b *CommandPolicyApplyConfiguration) WithMaxBackoffSeconds(value int32) *CommandPolicyApplyConfiguration {
+	b.MaxBackoffSeconds = &value
+	return b
+}
+
+// WithTimeoutSeconds sets the TimeoutSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TimeoutSeconds field is set to the value of the last call.
+func (b *CommandPolicyApplyConfiguration) WithTimeoutSeconds(value int64) *CommandPolicyApplyConfiguration {
+	b.TimeoutSeconds = &value
+	return b
+}
+
+// WithTTLSecondsAfterFinished sets the TTLSecondsAfterFinished field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TTLSecondsAfterFinished field is set to the value of the last call.
+func (b *CommandPolicyApplyConfiguration) WithTTLSecondsAfterFinished(value int32) *CommandPolicyApplyConfiguration {
+	b.TTLSecondsAfterFinished = &value
+	return b
+}
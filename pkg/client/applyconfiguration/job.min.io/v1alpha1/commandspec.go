@@ -25,16 +25,19 @@ import (
 // CommandSpecApplyConfiguration represents an declarative configuration of the CommandSpec type for use
 // with apply.
 type CommandSpecApplyConfiguration struct {
-	Operation    *string                  `json:"op,omitempty"`
-	Name         *string                  `json:"name,omitempty"`
-	Args         map[string]string        `json:"args,omitempty"`
-	Command      []string                 `json:"command,omitempty"`
-	DependsOn    []string                 `json:"dependsOn,omitempty"`
-	Resources    *v1.ResourceRequirements `json:"resources,omitempty"`
-	EnvFrom      []v1.EnvFromSource       `json:"envFrom,omitempty"`
-	Env          []v1.EnvVar              `json:"env,omitempty"`
-	VolumeMounts []v1.VolumeMount         `json:"volumeMounts,omitempty"`
-	Volumes      []v1.Volume              `json:"volumes,omitempty"`
+	Operation                       *string                           `json:"op,omitempty"`
+	Name                            *string                           `json:"name,omitempty"`
+	Args                            map[string]string                 `json:"args,omitempty"`
+	ArgsFrom                        []ArgFromSourceApplyConfiguration `json:"argsFrom,omitempty"`
+	Command                         []string                          `json:"command,omitempty"`
+	Batch                           *BatchJobSpecApplyConfiguration   `json:"batch,omitempty"`
+	DependsOn                       []string                          `json:"dependsOn,omitempty"`
+	Resources                       *v1.ResourceRequirements          `json:"resources,omitempty"`
+	EnvFrom                         []v1.EnvFromSource                `json:"envFrom,omitempty"`
+	Env                             []v1.EnvVar                       `json:"env,omitempty"`
+	CommandPolicyApplyConfiguration `json:",inline"`
+	VolumeMounts                    []v1.VolumeMount `json:"volumeMounts,omitempty"`
+	Volumes                         []v1.Volume      `json:"volumes,omitempty"`
 }
 
 // CommandSpecApplyConfiguration constructs an declarative configuration of the CommandSpec type for use with
@@ -73,6 +76,19 @@ func (b *CommandSpecApplyConfiguration) WithArgs(entries map[string]string) *Com
 	return b
 }
 
+// WithArgsFrom adds the given value to the ArgsFrom field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ArgsFrom field.
+func (b *CommandSpecApplyConfiguration) WithArgsFrom(values ...*ArgFromSourceApplyConfiguration) *CommandSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithArgsFrom")
+		}
+		b.ArgsFrom = append(b.ArgsFrom, *values[i])
+	}
+	return b
+}
+
 // WithCommand adds the given value to the Command field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Command field.
@@ -83,6 +99,14 @@ func (b *CommandSpecApplyConfiguration) WithCommand(values ...string) *CommandSp
 	return b
 }
 
+// WithBatch sets the Batch field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Batch field is set to the value of the last call.
+func (b *CommandSpecApplyConfiguration) WithBatch(value *BatchJobSpecApplyConfiguration) *CommandSpecApplyConfiguration {
+	b.Batch = value
+	return b
+}
+
 // WithDependsOn adds the given value to the DependsOn field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the DependsOn field.
@@ -121,6 +145,46 @@ func (b *CommandSpecApplyConfiguration) WithEnv(values ...v1.EnvVar) *CommandSpe
 	return b
 }
 
+// WithRetries sets the Retries field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Retries field is set to the value of the last call.
+func (b *CommandSpecApplyConfiguration) WithRetries(value int32) *CommandSpecApplyConfiguration {
+	b.CommandPolicyApplyConfiguration.Retries = &value
+	return b
+}
+
+// WithBackoffSeconds sets the BackoffSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BackoffSeconds field is set to the value of the last call.
+func (b *CommandSpecApplyConfiguration) WithBackoffSeconds(value int32) *CommandSpecApplyConfiguration {
+	b.CommandPolicyApplyConfiguration.BackoffSeconds = &value
+	return b
+}
+
+// WithMaxBackoffSeconds sets the MaxBackoffSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxBackoffSeconds field is set to the value of the last call.
+func (b *CommandSpecApplyConfiguration) WithMaxBackoffSeconds(value int32) *CommandSpecApplyConfiguration {
+	b.CommandPolicyApplyConfiguration.MaxBackoffSeconds = &value
+	return b
+}
+
+// WithTimeoutSeconds sets the TimeoutSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TimeoutSeconds field is set to the value of the last call.
+func (b *CommandSpecApplyConfiguration) WithTimeoutSeconds(value int64) *CommandSpecApplyConfiguration {
+	b.CommandPolicyApplyConfiguration.TimeoutSeconds = &value
+	return b
+}
+
+// WithTTLSecondsAfterFinished sets the TTLSecondsAfterFinished field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TTLSecondsAfterFinished field is set to the value of the last call.
+func (b *CommandSpecApplyConfiguration) WithTTLSecondsAfterFinished(value int32) *CommandSpecApplyConfiguration {
+	b.CommandPolicyApplyConfiguration.TTLSecondsAfterFinished = &value
+	return b
+}
+
 // WithVolumeMounts adds the given value to the VolumeMounts field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the VolumeMounts field.
@@ -18,12 +18,23 @@
 
 package v1alpha1
 
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // CommandStatusApplyConfiguration represents an declarative configuration of the CommandStatus type for use
 // with apply.
 type CommandStatusApplyConfiguration struct {
-	Name    *string `json:"name,omitempty"`
-	Result  *string `json:"result,omitempty"`
-	Message *string `json:"message,omitempty"`
+	Name           *string                           `json:"name,omitempty"`
+	Result         *string                           `json:"result,omitempty"`
+	Message        *string                           `json:"message,omitempty"`
+	ExitCode       *int32                            `json:"exitCode,omitempty"`
+	StartTime      *v1.Time                          `json:"startTime,omitempty"`
+	CompletionTime *v1.Time                          `json:"completionTime,omitempty"`
+	Attempts       *int32                            `json:"attempts,omitempty"`
+	NextRetryTime  *v1.Time                          `json:"nextRetryTime,omitempty"`
+	Output         *string                           `json:"output,omitempty"`
+	Batch          *BatchJobStatusApplyConfiguration `json:"batch,omitempty"`
 }
 
 // CommandStatusApplyConfiguration constructs an declarative configuration of the CommandStatus type for use with
@@ -55,3 +66,59 @@ func (b *CommandStatusApplyConfiguration) WithMessage(value string) *CommandStat
 	b.Message = &value
 	return b
 }
+
+// WithExitCode sets the ExitCode field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ExitCode field is set to the value of the last call.
+func (b *CommandStatusApplyConfiguration) WithExitCode(value int32) *CommandStatusApplyConfiguration {
+	b.ExitCode = &value
+	return b
+}
+
+// WithStartTime sets the StartTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StartTime field is set to the value of the last call.
+func (b *CommandStatusApplyConfiguration) WithStartTime(value v1.Time) *CommandStatusApplyConfiguration {
+	b.StartTime = &value
+	return b
+}
+
+// WithCompletionTime sets the CompletionTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CompletionTime field is set to the value of the last call.
+func (b *CommandStatusApplyConfiguration) WithCompletionTime(value v1.Time) *CommandStatusApplyConfiguration {
+	b.CompletionTime = &value
+	return b
+}
+
+// WithAttempts sets the Attempts field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Attempts field is set to the value of the last call.
+func (b *CommandStatusApplyConfiguration) WithAttempts(value int32) *CommandStatusApplyConfiguration {
+	b.Attempts = &value
+	return b
+}
+
+// WithNextRetryTime sets the NextRetryTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NextRetryTime field is set to the value of the last call.
+func (b *CommandStatusApplyConfiguration) WithNextRetryTime(value v1.Time) *CommandStatusApplyConfiguration {
+	b.NextRetryTime = &value
+	return b
+}
+
+// WithOutput sets the Output field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Output field is set to the value of the last call.
+func (b *CommandStatusApplyConfiguration) WithOutput(value string) *CommandStatusApplyConfiguration {
+	b.Output = &value
+	return b
+}
+
+// WithBatch sets the Batch field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Batch field is set to the value of the last call.
+func (b *CommandStatusApplyConfiguration) WithBatch(value *BatchJobStatusApplyConfiguration) *CommandStatusApplyConfiguration {
+	b.Batch = value
+	return b
+}
@@ -0,0 +1,93 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MinIOJobRunApplyConfiguration represents an declarative configuration of the MinIOJobRun type for use
+// with apply.
+type MinIOJobRunApplyConfiguration struct {
+	Name           *string                           `json:"name,omitempty"`
+	ScheduleTime   *v1.Time                          `json:"scheduleTime,omitempty"`
+	CompletionTime *v1.Time                          `json:"completionTime,omitempty"`
+	Phase          *string                           `json:"phase,omitempty"`
+	Message        *string                           `json:"message,omitempty"`
+	CommandsStatus []CommandStatusApplyConfiguration `json:"commands,omitempty"`
+}
+
+// MinIOJobRunApplyConfiguration constructs an declarative configuration of the MinIOJobRun type for use with
+// apply.
+func MinIOJobRun() *MinIOJobRunApplyConfiguration {
+	return &MinIOJobRunApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *MinIOJobRunApplyConfiguration) WithName(value string) *MinIOJobRunApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithScheduleTime sets the ScheduleTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ScheduleTime field is set to the value of the last call.
+func (b *MinIOJobRunApplyConfiguration) WithScheduleTime(value v1.Time) *MinIOJobRunApplyConfiguration {
+	b.ScheduleTime = &value
+	return b
+}
+
+// WithCompletionTime sets the CompletionTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CompletionTime field is set to the value of the last call.
+func (b *MinIOJobRunApplyConfiguration) WithCompletionTime(value v1.Time) *MinIOJobRunApplyConfiguration {
+	b.CompletionTime = &value
+	return b
+}
+
+// WithPhase sets the Phase field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Phase field is set to the value of the last call.
+func (b *MinIOJobRunApplyConfiguration) WithPhase(value string) *MinIOJobRunApplyConfiguration {
+	b.Phase = &value
+	return b
+}
+
+// WithMessage sets the Message field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Message field is set to the value of the last call.
+func (b *MinIOJobRunApplyConfiguration) WithMessage(value string) *MinIOJobRunApplyConfiguration {
+	b.Message = &value
+	return b
+}
+
+// WithCommandsStatus adds the given value to the CommandsStatus field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the CommandsStatus field.
+func (b *MinIOJobRunApplyConfiguration) WithCommandsStatus(values ...*CommandStatusApplyConfiguration) *MinIOJobRunApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithCommandsStatus")
+		}
+		b.CommandsStatus = append(b.CommandsStatus, *values[i])
+	}
+	return b
+}
@@ -26,16 +26,25 @@ import (
 // MinIOJobSpecApplyConfiguration represents an declarative configuration of the MinIOJobSpec type for use
 // with apply.
 type MinIOJobSpecApplyConfiguration struct {
-	ServiceAccountName       *string                           `json:"serviceAccountName,omitempty"`
-	TenantRef                *TenantRefApplyConfiguration      `json:"tenant,omitempty"`
-	Execution                *jobminiov1alpha1.Execution       `json:"execution,omitempty"`
-	FailureStrategy          *jobminiov1alpha1.FailureStrategy `json:"failureStrategy,omitempty"`
-	Commands                 []CommandSpecApplyConfiguration   `json:"commands,omitempty"`
-	MCImage                  *string                           `json:"mcImage,omitempty"`
-	ImagePullPolicy          *v1.PullPolicy                    `json:"imagePullPolicy,omitempty"`
-	ImagePullSecret          []v1.LocalObjectReference         `json:"imagePullSecret,omitempty"`
-	SecurityContext          *v1.PodSecurityContext            `json:"securityContext,omitempty"`
-	ContainerSecurityContext *v1.SecurityContext               `json:"containerSecurityContext,omitempty"`
+	ServiceAccountName         *string                             `json:"serviceAccountName,omitempty"`
+	TenantRef                  *TenantRefApplyConfiguration        `json:"tenant,omitempty"`
+	Execution                  *jobminiov1alpha1.Execution         `json:"execution,omitempty"`
+	FailureStrategy            *jobminiov1alpha1.FailureStrategy   `json:"failureStrategy,omitempty"`
+	Commands                   []CommandSpecApplyConfiguration     `json:"commands,omitempty"`
+	Schedule                   *string                             `json:"schedule,omitempty"`
+	ConcurrencyPolicy          *jobminiov1alpha1.ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	SuccessfulRunsHistoryLimit *int32                              `json:"successfulRunsHistoryLimit,omitempty"`
+	FailedRunsHistoryLimit     *int32                              `json:"failedRunsHistoryLimit,omitempty"`
+	CommandDefaults            *CommandPolicyApplyConfiguration    `json:"commandDefaults,omitempty"`
+	PersistOutput              *bool                               `json:"persistOutput,omitempty"`
+	TTLAfterFinished           *int32                              `json:"ttlAfterFinished,omitempty"`
+	CredentialMode             *jobminiov1alpha1.CredentialMode    `json:"credentialMode,omitempty"`
+	DryRun                     *bool                               `json:"dryRun,omitempty"`
+	MCImage                    *string                             `json:"mcImage,omitempty"`
+	ImagePullPolicy            *v1.PullPolicy                      `json:"imagePullPolicy,omitempty"`
+	ImagePullSecret            []v1.LocalObjectReference           `json:"imagePullSecret,omitempty"`
+	SecurityContext            *v1.PodSecurityContext              `json:"securityContext,omitempty"`
+	ContainerSecurityContext   *v1.SecurityContext                 `json:"containerSecurityContext,omitempty"`
 }
 
 // MinIOJobSpecApplyConfiguration constructs an declarative configuration of the MinIOJobSpec type for use with
@@ -89,6 +98,78 @@ func (b *MinIOJobSpecApplyConfiguration) WithCommands(values ...*CommandSpecAppl
 	return b
 }
 
+// WithSchedule sets the Schedule field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Schedule field is set to the value of the last call.
+func (b *MinIOJobSpecApplyConfiguration) WithSchedule(value string) *MinIOJobSpecApplyConfiguration {
+	b.Schedule = &value
+	return b
+}
+
+// WithConcurrencyPolicy sets the ConcurrencyPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ConcurrencyPolicy field is set to the value of the last call.
+func (b *MinIOJobSpecApplyConfiguration) WithConcurrencyPolicy(value jobminiov1alpha1.ConcurrencyPolicy) *MinIOJobSpecApplyConfiguration {
+	b.ConcurrencyPolicy = &value
+	return b
+}
+
+// WithSuccessfulRunsHistoryLimit sets the SuccessfulRunsHistoryLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SuccessfulRunsHistoryLimit field is set to the value of the last call.
+func (b *MinIOJobSpecApplyConfiguration) WithSuccessfulRunsHistoryLimit(value int32) *MinIOJobSpecApplyConfiguration {
+	b.SuccessfulRunsHistoryLimit = &value
+	return b
+}
+
+// WithFailedRunsHistoryLimit sets the FailedRunsHistoryLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FailedRunsHistoryLimit field is set to the value of the last call.
+func (b *MinIOJobSpecApplyConfiguration) WithFailedRunsHistoryLimit(value int32) *MinIOJobSpecApplyConfiguration {
+	b.FailedRunsHistoryLimit = &value
+	return b
+}
+
+// WithCommandDefaults sets the CommandDefaults field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CommandDefaults field is set to the value of the last call.
+func (b *MinIOJobSpecApplyConfiguration) WithCommandDefaults(value *CommandPolicyApplyConfiguration) *MinIOJobSpecApplyConfiguration {
+	b.CommandDefaults = value
+	return b
+}
+
+// WithPersistOutput sets the PersistOutput field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PersistOutput field is set to the value of the last call.
+func (b *MinIOJobSpecApplyConfiguration) WithPersistOutput(value bool) *MinIOJobSpecApplyConfiguration {
+	b.PersistOutput = &value
+	return b
+}
+
+// WithTTLAfterFinished sets the TTLAfterFinished field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TTLAfterFinished field is set to the value of the last call.
+func (b *MinIOJobSpecApplyConfiguration) WithTTLAfterFinished(value int32) *MinIOJobSpecApplyConfiguration {
+	b.TTLAfterFinished = &value
+	return b
+}
+
+// WithCredentialMode sets the CredentialMode field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CredentialMode field is set to the value of the last call.
+func (b *MinIOJobSpecApplyConfiguration) WithCredentialMode(value jobminiov1alpha1.CredentialMode) *MinIOJobSpecApplyConfiguration {
+	b.CredentialMode = &value
+	return b
+}
+
+// WithDryRun sets the DryRun field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DryRun field is set to the value of the last call.
+func (b *MinIOJobSpecApplyConfiguration) WithDryRun(value bool) *MinIOJobSpecApplyConfiguration {
+	b.DryRun = &value
+	return b
+}
+
 // WithMCImage sets the MCImage field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the MCImage field is set to the value of the last call.
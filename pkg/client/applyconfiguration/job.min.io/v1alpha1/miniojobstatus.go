@@ -18,12 +18,22 @@
 
 package v1alpha1
 
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // MinIOJobStatusApplyConfiguration represents an declarative configuration of the MinIOJobStatus type for use
 // with apply.
 type MinIOJobStatusApplyConfiguration struct {
-	Phase          *string                           `json:"phase,omitempty"`
-	CommandsStatus []CommandStatusApplyConfiguration `json:"commands,omitempty"`
-	Message        *string                           `json:"message,omitempty"`
+	Phase            *string                           `json:"phase,omitempty"`
+	CommandsStatus   []CommandStatusApplyConfiguration `json:"commands,omitempty"`
+	Message          *string                           `json:"message,omitempty"`
+	Run              *string                           `json:"run,omitempty"`
+	LastScheduleTime *v1.Time                          `json:"lastScheduleTime,omitempty"`
+	NextScheduleTime *v1.Time                          `json:"nextScheduleTime,omitempty"`
+	History          []MinIOJobRunApplyConfiguration   `json:"history,omitempty"`
+	CompletionTime   *v1.Time                          `json:"completionTime,omitempty"`
+	Plan             []CommandPlanApplyConfiguration   `json:"plan,omitempty"`
 }
 
 // MinIOJobStatusApplyConfiguration constructs an declarative configuration of the MinIOJobStatus type for use with
@@ -60,3 +70,61 @@ func (b *MinIOJobStatusApplyConfiguration) WithMessage(value string) *MinIOJobSt
 	b.Message = &value
 	return b
 }
+
+// WithRun sets the Run field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Run field is set to the value of the last call.
+func (b *MinIOJobStatusApplyConfiguration) WithRun(value string) *MinIOJobStatusApplyConfiguration {
+	b.Run = &value
+	return b
+}
+
+// WithLastScheduleTime sets the LastScheduleTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastScheduleTime field is set to the value of the last call.
+func (b *MinIOJobStatusApplyConfiguration) WithLastScheduleTime(value v1.Time) *MinIOJobStatusApplyConfiguration {
+	b.LastScheduleTime = &value
+	return b
+}
+
+// WithNextScheduleTime sets the NextScheduleTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NextScheduleTime field is set to the value of the last call.
+func (b *MinIOJobStatusApplyConfiguration) WithNextScheduleTime(value v1.Time) *MinIOJobStatusApplyConfiguration {
+	b.NextScheduleTime = &value
+	return b
+}
+
+// WithHistory adds the given value to the History field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the History field.
+func (b *MinIOJobStatusApplyConfiguration) WithHistory(values ...*MinIOJobRunApplyConfiguration) *MinIOJobStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithHistory")
+		}
+		b.History = append(b.History, *values[i])
+	}
+	return b
+}
+
+// WithCompletionTime sets the CompletionTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CompletionTime field is set to the value of the last call.
+func (b *MinIOJobStatusApplyConfiguration) WithCompletionTime(value v1.Time) *MinIOJobStatusApplyConfiguration {
+	b.CompletionTime = &value
+	return b
+}
+
+// WithPlan adds the given value to the Plan field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Plan field.
+func (b *MinIOJobStatusApplyConfiguration) WithPlan(values ...*CommandPlanApplyConfiguration) *MinIOJobStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithPlan")
+		}
+		b.Plan = append(b.Plan, *values[i])
+	}
+	return b
+}
@@ -0,0 +1,72 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// ImageVerificationApplyConfiguration represents an declarative configuration of the ImageVerification type for use
+// with apply.
+type ImageVerificationApplyConfiguration struct {
+	AllowedDigests         []string              `json:"allowedDigests,omitempty"`
+	CosignPublicKey        *v1.SecretKeySelector `json:"cosignPublicKey,omitempty"`
+	RequireMatchingRelease *bool                 `json:"requireMatchingRelease,omitempty"`
+	MinimumRelease         *string               `json:"minimumRelease,omitempty"`
+}
+
+// ImageVerificationApplyConfiguration constructs an declarative configuration of the ImageVerification type for use with
+// apply.
+func ImageVerification() *ImageVerificationApplyConfiguration {
+	return &ImageVerificationApplyConfiguration{}
+}
+
+// WithAllowedDigests adds the given value to the AllowedDigests field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the AllowedDigests field.
+func (b *ImageVerificationApplyConfiguration) WithAllowedDigests(values ...string) *ImageVerificationApplyConfiguration {
+	for i := range values {
+		b.AllowedDigests = append(b.AllowedDigests, values[i])
+	}
+	return b
+}
+
+// WithCosignPublicKey sets the CosignPublicKey field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CosignPublicKey field is set to the value of the last call.
+func (b *ImageVerificationApplyConfiguration) WithCosignPublicKey(value v1.SecretKeySelector) *ImageVerificationApplyConfiguration {
+	b.CosignPublicKey = &value
+	return b
+}
+
+// WithRequireMatchingRelease sets the RequireMatchingRelease field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RequireMatchingRelease field is set to the value of the last call.
+func (b *ImageVerificationApplyConfiguration) WithRequireMatchingRelease(value bool) *ImageVerificationApplyConfiguration {
+	b.RequireMatchingRelease = &value
+	return b
+}
+
+// WithMinimumRelease sets the MinimumRelease field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MinimumRelease field is set to the value of the last call.
+func (b *ImageVerificationApplyConfiguration) WithMinimumRelease(value string) *ImageVerificationApplyConfiguration {
+	b.MinimumRelease = &value
+	return b
+}
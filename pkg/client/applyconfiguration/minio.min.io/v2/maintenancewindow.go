@@ -0,0 +1,61 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaintenanceWindowApplyConfiguration represents an declarative configuration of the MaintenanceWindow type for use
+// with apply.
+type MaintenanceWindowApplyConfiguration struct {
+	Schedule *string      `json:"schedule,omitempty"`
+	Duration *v1.Duration `json:"duration,omitempty"`
+	TimeZone *string      `json:"timeZone,omitempty"`
+}
+
+// MaintenanceWindowApplyConfiguration constructs an declarative configuration of the MaintenanceWindow type for use with
+// apply.
+func MaintenanceWindow() *MaintenanceWindowApplyConfiguration {
+	return &MaintenanceWindowApplyConfiguration{}
+}
+
+// WithSchedule sets the Schedule field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Schedule field is set to the value of the last call.
+func (b *MaintenanceWindowApplyConfiguration) WithSchedule(value string) *MaintenanceWindowApplyConfiguration {
+	b.Schedule = &value
+	return b
+}
+
+// WithDuration sets the Duration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Duration field is set to the value of the last call.
+func (b *MaintenanceWindowApplyConfiguration) WithDuration(value v1.Duration) *MaintenanceWindowApplyConfiguration {
+	b.Duration = &value
+	return b
+}
+
+// WithTimeZone sets the TimeZone field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TimeZone field is set to the value of the last call.
+func (b *MaintenanceWindowApplyConfiguration) WithTimeZone(value string) *MaintenanceWindowApplyConfiguration {
+	b.TimeZone = &value
+	return b
+}
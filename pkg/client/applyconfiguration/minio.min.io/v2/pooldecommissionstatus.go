@@ -0,0 +1,142 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PoolDecommissionStatusApplyConfiguration represents an declarative configuration of the PoolDecommissionStatus type for use
+// with apply.
+type PoolDecommissionStatusApplyConfiguration struct {
+	CmdLine       *string  `json:"cmdLine,omitempty"`
+	StartTime     *v1.Time `json:"startTime,omitempty"`
+	LastUpdate    *v1.Time `json:"lastUpdate,omitempty"`
+	ETA           *v1.Time `json:"eta,omitempty"`
+	TotalSize     *int64   `json:"totalSize,omitempty"`
+	CurrentSize   *int64   `json:"currentSize,omitempty"`
+	BytesDone     *int64   `json:"bytesDone,omitempty"`
+	BytesFailed   *int64   `json:"bytesFailed,omitempty"`
+	ObjectsDone   *int64   `json:"objectsDone,omitempty"`
+	ObjectsFailed *int64   `json:"objectsFailed,omitempty"`
+	Complete      *bool    `json:"complete,omitempty"`
+	Failed        *bool    `json:"failed,omitempty"`
+}
+
+// PoolDecommissionStatusApplyConfiguration constructs an declarative configuration of the PoolDecommissionStatus type for use with
+// apply.
+func PoolDecommissionStatus() *PoolDecommissionStatusApplyConfiguration {
+	return &PoolDecommissionStatusApplyConfiguration{}
+}
+
+// WithCmdLine sets the CmdLine field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CmdLine field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithCmdLine(value string) *PoolDecommissionStatusApplyConfiguration {
+	b.CmdLine = &value
+	return b
+}
+
+// WithStartTime sets the StartTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StartTime field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithStartTime(value v1.Time) *PoolDecommissionStatusApplyConfiguration {
+	b.StartTime = &value
+	return b
+}
+
+// WithLastUpdate sets the LastUpdate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastUpdate field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithLastUpdate(value v1.Time) *PoolDecommissionStatusApplyConfiguration {
+	b.LastUpdate = &value
+	return b
+}
+
+// WithETA sets the ETA field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ETA field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithETA(value v1.Time) *PoolDecommissionStatusApplyConfiguration {
+	b.ETA = &value
+	return b
+}
+
+// WithTotalSize sets the TotalSize field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TotalSize field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithTotalSize(value int64) *PoolDecommissionStatusApplyConfiguration {
+	b.TotalSize = &value
+	return b
+}
+
+// WithCurrentSize sets the CurrentSize field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CurrentSize field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithCurrentSize(value int64) *PoolDecommissionStatusApplyConfiguration {
+	b.CurrentSize = &value
+	return b
+}
+
+// WithBytesDone sets the BytesDone field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BytesDone field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithBytesDone(value int64) *PoolDecommissionStatusApplyConfiguration {
+	b.BytesDone = &value
+	return b
+}
+
+// WithBytesFailed sets the BytesFailed field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BytesFailed field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithBytesFailed(value int64) *PoolDecommissionStatusApplyConfiguration {
+	b.BytesFailed = &value
+	return b
+}
+
+// WithObjectsDone sets the ObjectsDone field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObjectsDone field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithObjectsDone(value int64) *PoolDecommissionStatusApplyConfiguration {
+	b.ObjectsDone = &value
+	return b
+}
+
+// WithObjectsFailed sets the ObjectsFailed field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObjectsFailed field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithObjectsFailed(value int64) *PoolDecommissionStatusApplyConfiguration {
+	b.ObjectsFailed = &value
+	return b
+}
+
+// WithComplete sets the Complete field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Complete field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithComplete(value bool) *PoolDecommissionStatusApplyConfiguration {
+	b.Complete = &value
+	return b
+}
+
+// WithFailed sets the Failed field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Failed field is set to the value of the last call.
+func (b *PoolDecommissionStatusApplyConfiguration) WithFailed(value bool) *PoolDecommissionStatusApplyConfiguration {
+	b.Failed = &value
+	return b
+}
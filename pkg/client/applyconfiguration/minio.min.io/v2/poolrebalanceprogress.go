@@ -0,0 +1,106 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PoolRebalanceProgressApplyConfiguration represents an declarative configuration of the PoolRebalanceProgress type for use
+// with apply.
+type PoolRebalanceProgressApplyConfiguration struct {
+	ID             *int32   `json:"id,omitempty"`
+	SSName         *string  `json:"ssName,omitempty"`
+	Status         *string  `json:"status,omitempty"`
+	UsedPercentage *string  `json:"usedPercentage,omitempty"`
+	Objects        *int64   `json:"objects,omitempty"`
+	Versions       *int64   `json:"versions,omitempty"`
+	Bytes          *int64   `json:"bytes,omitempty"`
+	ETA            *v1.Time `json:"eta,omitempty"`
+}
+
+// PoolRebalanceProgressApplyConfiguration constructs an declarative configuration of the PoolRebalanceProgress type for use with
+// apply.
+func PoolRebalanceProgress() *PoolRebalanceProgressApplyConfiguration {
+	return &PoolRebalanceProgressApplyConfiguration{}
+}
+
+// WithID sets the ID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ID field is set to the value of the last call.
+func (b *PoolRebalanceProgressApplyConfiguration) WithID(value int32) *PoolRebalanceProgressApplyConfiguration {
+	b.ID = &value
+	return b
+}
+
+// WithSSName sets the SSName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SSName field is set to the value of the last call.
+func (b *PoolRebalanceProgressApplyConfiguration) WithSSName(value string) *PoolRebalanceProgressApplyConfiguration {
+	b.SSName = &value
+	return b
+}
+
+// WithStatus sets the Status field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Status field is set to the value of the last call.
+func (b *PoolRebalanceProgressApplyConfiguration) WithStatus(value string) *PoolRebalanceProgressApplyConfiguration {
+	b.Status = &value
+	return b
+}
+
+// WithUsedPercentage sets the UsedPercentage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UsedPercentage field is set to the value of the last call.
+func (b *PoolRebalanceProgressApplyConfiguration) WithUsedPercentage(value string) *PoolRebalanceProgressApplyConfiguration {
+	b.UsedPercentage = &value
+	return b
+}
+
+// WithObjects sets the Objects field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Objects field is set to the value of the last call.
+func (b *PoolRebalanceProgressApplyConfiguration) WithObjects(value int64) *PoolRebalanceProgressApplyConfiguration {
+	b.Objects = &value
+	return b
+}
+
+// WithVersions sets the Versions field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Versions field is set to the value of the last call.
+func (b *PoolRebalanceProgressApplyConfiguration) WithVersions(value int64) *PoolRebalanceProgressApplyConfiguration {
+	b.Versions = &value
+	return b
+}
+
+// WithBytes sets the Bytes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Bytes field is set to the value of the last call.
+func (b *PoolRebalanceProgressApplyConfiguration) WithBytes(value int64) *PoolRebalanceProgressApplyConfiguration {
+	b.Bytes = &value
+	return b
+}
+
+// WithETA sets the ETA field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ETA field is set to the value of the last call.
+func (b *PoolRebalanceProgressApplyConfiguration) WithETA(value v1.Time) *PoolRebalanceProgressApplyConfiguration {
+	b.ETA = &value
+	return b
+}
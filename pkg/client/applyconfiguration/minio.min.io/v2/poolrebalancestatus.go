@@ -0,0 +1,94 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	miniominiov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PoolRebalanceStatusApplyConfiguration represents an declarative configuration of the PoolRebalanceStatus type for use
+// with apply.
+type PoolRebalanceStatusApplyConfiguration struct {
+	ID         *string                                   `json:"id,omitempty"`
+	State      *miniominiov2.PoolRebalanceState          `json:"state,omitempty"`
+	StartTime  *v1.Time                                  `json:"startTime,omitempty"`
+	StopTime   *v1.Time                                  `json:"stopTime,omitempty"`
+	LastUpdate *v1.Time                                  `json:"lastUpdate,omitempty"`
+	Pools      []PoolRebalanceProgressApplyConfiguration `json:"pools,omitempty"`
+}
+
+// PoolRebalanceStatusApplyConfiguration constructs an declarative configuration of the PoolRebalanceStatus type for use with
+// apply.
+func PoolRebalanceStatus() *PoolRebalanceStatusApplyConfiguration {
+	return &PoolRebalanceStatusApplyConfiguration{}
+}
+
+// WithID sets the ID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ID field is set to the value of the last call.
+func (b *PoolRebalanceStatusApplyConfiguration) WithID(value string) *PoolRebalanceStatusApplyConfiguration {
+	b.ID = &value
+	return b
+}
+
+// WithState sets the State field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the State field is set to the value of the last call.
+func (b *PoolRebalanceStatusApplyConfiguration) WithState(value miniominiov2.PoolRebalanceState) *PoolRebalanceStatusApplyConfiguration {
+	b.State = &value
+	return b
+}
+
+// WithStartTime sets the StartTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StartTime field is set to the value of the last call.
+func (b *PoolRebalanceStatusApplyConfiguration) WithStartTime(value v1.Time) *PoolRebalanceStatusApplyConfiguration {
+	b.StartTime = &value
+	return b
+}
+
+// WithStopTime sets the StopTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StopTime field is set to the value of the last call.
+func (b *PoolRebalanceStatusApplyConfiguration) WithStopTime(value v1.Time) *PoolRebalanceStatusApplyConfiguration {
+	b.StopTime = &value
+	return b
+}
+
+// WithLastUpdate sets the LastUpdate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastUpdate field is set to the value of the last call.
+func (b *PoolRebalanceStatusApplyConfiguration) WithLastUpdate(value v1.Time) *PoolRebalanceStatusApplyConfiguration {
+	b.LastUpdate = &value
+	return b
+}
+
+// WithPools adds the given value to the Pools field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Pools field.
+func (b *PoolRebalanceStatusApplyConfiguration) WithPools(values ...*PoolRebalanceProgressApplyConfiguration) *PoolRebalanceStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithPools")
+		}
+		b.Pools = append(b.Pools, *values[i])
+	}
+	return b
+}
@@ -0,0 +1,89 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	miniominiov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PoolReplacementStatusApplyConfiguration represents an declarative configuration of the PoolReplacementStatus type for use
+// with apply.
+type PoolReplacementStatusApplyConfiguration struct {
+	Stage            *miniominiov2.PoolReplacementStage `json:"stage,omitempty"`
+	SSName           *string                            `json:"ssName,omitempty"`
+	CmdLine          *string                            `json:"cmdLine,omitempty"`
+	Servers          *int32                             `json:"servers,omitempty"`
+	VolumesPerServer *int32                             `json:"volumesPerServer,omitempty"`
+	StartTime        *v1.Time                           `json:"startTime,omitempty"`
+}
+
+// PoolReplacementStatusApplyConfiguration constructs an declarative configuration of the PoolReplacementStatus type for use with
+// apply.
+func PoolReplacementStatus() *PoolReplacementStatusApplyConfiguration {
+	return &PoolReplacementStatusApplyConfiguration{}
+}
+
+// WithStage sets the Stage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Stage field is set to the value of the last call.
+func (b *PoolReplacementStatusApplyConfiguration) WithStage(value miniominiov2.PoolReplacementStage) *PoolReplacementStatusApplyConfiguration {
+	b.Stage = &value
+	return b
+}
+
+// WithSSName sets the SSName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SSName field is set to the value of the last call.
+func (b *PoolReplacementStatusApplyConfiguration) WithSSName(value string) *PoolReplacementStatusApplyConfiguration {
+	b.SSName = &value
+	return b
+}
+
+// WithCmdLine sets the CmdLine field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CmdLine field is set to the value of the last call.
+func (b *PoolReplacementStatusApplyConfiguration) WithCmdLine(value string) *PoolReplacementStatusApplyConfiguration {
+	b.CmdLine = &value
+	return b
+}
+
+// WithServers sets the Servers field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Servers field is set to the value of the last call.
+func (b *PoolReplacementStatusApplyConfiguration) WithServers(value int32) *PoolReplacementStatusApplyConfiguration {
+	b.Servers = &value
+	return b
+}
+
+// WithVolumesPerServer sets the VolumesPerServer field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the VolumesPerServer field is set to the value of the last call.
+func (b *PoolReplacementStatusApplyConfiguration) WithVolumesPerServer(value int32) *PoolReplacementStatusApplyConfiguration {
+	b.VolumesPerServer = &value
+	return b
+}
+
+// WithStartTime sets the StartTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StartTime field is set to the value of the last call.
+func (b *PoolReplacementStatusApplyConfiguration) WithStartTime(value v1.Time) *PoolReplacementStatusApplyConfiguration {
+	b.StartTime = &value
+	return b
+}
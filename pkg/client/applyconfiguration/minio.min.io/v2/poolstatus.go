@@ -25,9 +25,11 @@ import (
 // PoolStatusApplyConfiguration represents an declarative configuration of the PoolStatus type for use
 // with apply.
 type PoolStatusApplyConfiguration struct {
-	SSName                *string       `json:"ssName,omitempty"`
-	State                 *v2.PoolState `json:"state,omitempty"`
-	LegacySecurityContext *bool         `json:"legacySecurityContext,omitempty"`
+	SSName                *string                                   `json:"ssName,omitempty"`
+	State                 *v2.PoolState                             `json:"state,omitempty"`
+	LegacySecurityContext *bool                                     `json:"legacySecurityContext,omitempty"`
+	Decommission          *PoolDecommissionStatusApplyConfiguration `json:"decommission,omitempty"`
+	Replacement           *PoolReplacementStatusApplyConfiguration  `json:"replacement,omitempty"`
 }
 
 // PoolStatusApplyConfiguration constructs an declarative configuration of the PoolStatus type for use with
@@ -59,3 +61,19 @@ func (b *PoolStatusApplyConfiguration) WithLegacySecurityContext(value bool) *Po
 	b.LegacySecurityContext = &value
 	return b
 }
+
+// WithDecommission sets the Decommission field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Decommission field is set to the value of the last call.
+func (b *PoolStatusApplyConfiguration) WithDecommission(value *PoolDecommissionStatusApplyConfiguration) *PoolStatusApplyConfiguration {
+	b.Decommission = value
+	return b
+}
+
+// WithReplacement sets the Replacement field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Replacement field is set to the value of the last call.
+func (b *PoolStatusApplyConfiguration) WithReplacement(value *PoolReplacementStatusApplyConfiguration) *PoolStatusApplyConfiguration {
+	b.Replacement = value
+	return b
+}
@@ -28,6 +28,13 @@ import (
 // with apply.
 type TenantSpecApplyConfiguration struct {
 	Pools                     []PoolApplyConfiguration                     `json:"pools,omitempty"`
+	AllowPoolReplacement      *bool                                        `json:"allowPoolReplacement,omitempty"`
+	Paused                    *bool                                        `json:"paused,omitempty"`
+	Hibernate                 *bool                                        `json:"hibernate,omitempty"`
+	UpgradeStrategy           *UpgradeStrategyApplyConfiguration           `json:"upgradeStrategy,omitempty"`
+	UpgradeSource             *UpgradeSourceApplyConfiguration             `json:"upgradeSource,omitempty"`
+	ImageVerification         *ImageVerificationApplyConfiguration         `json:"imageVerification,omitempty"`
+	MaintenanceWindows        []MaintenanceWindowApplyConfiguration        `json:"maintenanceWindows,omitempty"`
 	Image                     *string                                      `json:"image,omitempty"`
 	ImagePullSecret           *v1.LocalObjectReference                     `json:"imagePullSecret,omitempty"`
 	PodManagementPolicy       *appsv1.PodManagementPolicyType              `json:"podManagementPolicy,omitempty"`
@@ -53,6 +60,7 @@ type TenantSpecApplyConfiguration struct {
 	SideCars                  *SideCarsApplyConfiguration                  `json:"sideCars,omitempty"`
 	ExposeServices            *ExposeServicesApplyConfiguration            `json:"exposeServices,omitempty"`
 	ServiceMetadata           *ServiceMetadataApplyConfiguration           `json:"serviceMetadata,omitempty"`
+	ServiceName               *string                                      `json:"serviceName,omitempty"`
 	Users                     []v1.LocalObjectReference                    `json:"users,omitempty"`
 	Buckets                   []BucketApplyConfiguration                   `json:"buckets,omitempty"`
 	Logging                   *LoggingApplyConfiguration                   `json:"logging,omitempty"`
@@ -60,6 +68,8 @@ type TenantSpecApplyConfiguration struct {
 	InitContainers            []v1.Container                               `json:"initContainers,omitempty"`
 	AdditionalVolumes         []v1.Volume                                  `json:"additionalVolumes,omitempty"`
 	AdditionalVolumeMounts    []v1.VolumeMount                             `json:"additionalVolumeMounts,omitempty"`
+	PoolRebalance             *PoolRebalanceConfigApplyConfiguration       `json:"poolRebalance,omitempty"`
+	DeletionPolicy            *miniominiov2.DeletionPolicy                 `json:"deletionPolicy,omitempty"`
 }
 
 // TenantSpecApplyConfiguration constructs an declarative configuration of the TenantSpec type for use with
@@ -81,6 +91,67 @@ func (b *TenantSpecApplyConfiguration) WithPools(values ...*PoolApplyConfigurati
 	return b
 }
 
+// WithAllowPoolReplacement sets the AllowPoolReplacement field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AllowPoolReplacement field is set to the value of the last call.
+func (b *TenantSpecApplyConfiguration) WithAllowPoolReplacement(value bool) *TenantSpecApplyConfiguration {
+	b.AllowPoolReplacement = &value
+	return b
+}
+
+// WithPaused sets the Paused field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Paused field is set to the value of the last call.
+func (b *TenantSpecApplyConfiguration) WithPaused(value bool) *TenantSpecApplyConfiguration {
+	b.Paused = &value
+	return b
+}
+
+// WithHibernate sets the Hibernate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Hibernate field is set to the value of the last call.
+func (b *TenantSpecApplyConfiguration) WithHibernate(value bool) *TenantSpecApplyConfiguration {
+	b.Hibernate = &value
+	return b
+}
+
+// WithUpgradeStrategy sets the UpgradeStrategy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UpgradeStrategy field is set to the value of the last call.
+func (b *TenantSpecApplyConfiguration) WithUpgradeStrategy(value *UpgradeStrategyApplyConfiguration) *TenantSpecApplyConfiguration {
+	b.UpgradeStrategy = value
+	return b
+}
+
+// WithUpgradeSource sets the UpgradeSource field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UpgradeSource field is set to the value of the last call.
+func (b *TenantSpecApplyConfiguration) WithUpgradeSource(value *UpgradeSourceApplyConfiguration) *TenantSpecApplyConfiguration {
+	b.UpgradeSource = value
+	return b
+}
+
+// WithImageVerification sets the ImageVerification field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ImageVerification field is set to the value of the last call.
+func (b *TenantSpecApplyConfiguration) WithImageVerification(value *ImageVerificationApplyConfiguration) *TenantSpecApplyConfiguration {
+	b.ImageVerification = value
+	return b
+}
+
+// WithMaintenanceWindows adds the given value to the MaintenanceWindows field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the MaintenanceWindows field.
+func (b *TenantSpecApplyConfiguration) WithMaintenanceWindows(values ...*MaintenanceWindowApplyConfiguration) *TenantSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithMaintenanceWindows")
+		}
+		b.MaintenanceWindows = append(b.MaintenanceWindows, *values[i])
+	}
+	return b
+}
+
 // WithImage sets the Image field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Image field is set to the value of the last call.
@@ -298,6 +369,14 @@ func (b *TenantSpecApplyConfiguration) WithServiceMetadata(value *ServiceMetadat
 	return b
 }
 
+// WithServiceName sets the ServiceName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ServiceName field is set to the value of the last call.
+func (b *TenantSpecApplyConfiguration) WithServiceName(value string) *TenantSpecApplyConfiguration {
+	b.ServiceName = &value
+	return b
+}
+
 // WithUsers adds the given value to the Users field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Users field.
@@ -366,3 +445,19 @@ func (b *TenantSpecApplyConfiguration) WithAdditionalVolumeMounts(values ...v1.V
 	}
 	return b
 }
+
+// WithPoolRebalance sets the PoolRebalance field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PoolRebalance field is set to the value of the last call.
+func (b *TenantSpecApplyConfiguration) WithPoolRebalance(value *PoolRebalanceConfigApplyConfiguration) *TenantSpecApplyConfiguration {
+	b.PoolRebalance = value
+	return b
+}
+
+// WithDeletionPolicy sets the DeletionPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DeletionPolicy field is set to the value of the last call.
+func (b *TenantSpecApplyConfiguration) WithDeletionPolicy(value miniominiov2.DeletionPolicy) *TenantSpecApplyConfiguration {
+	b.DeletionPolicy = &value
+	return b
+}
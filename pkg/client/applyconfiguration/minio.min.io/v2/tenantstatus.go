@@ -21,27 +21,34 @@ package v2
 import (
 	miniominiov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 )
 
 // TenantStatusApplyConfiguration represents an declarative configuration of the TenantStatus type for use
 // with apply.
 type TenantStatusApplyConfiguration struct {
-	CurrentState       *string                              `json:"currentState,omitempty"`
-	AvailableReplicas  *int32                               `json:"availableReplicas,omitempty"`
-	Revision           *int32                               `json:"revision,omitempty"`
-	SyncVersion        *string                              `json:"syncVersion,omitempty"`
-	Certificates       *CertificateStatusApplyConfiguration `json:"certificates,omitempty"`
-	Pools              []PoolStatusApplyConfiguration       `json:"pools,omitempty"`
-	WriteQuorum        *int32                               `json:"writeQuorum,omitempty"`
-	DrivesOnline       *int32                               `json:"drivesOnline,omitempty"`
-	DrivesOffline      *int32                               `json:"drivesOffline,omitempty"`
-	DrivesHealing      *int32                               `json:"drivesHealing,omitempty"`
-	HealthStatus       *miniominiov2.HealthStatus           `json:"healthStatus,omitempty"`
-	HealthMessage      *string                              `json:"healthMessage,omitempty"`
-	WaitingOnReady     *v1.Time                             `json:"waitingOnReady,omitempty"`
-	Usage              *TenantUsageApplyConfiguration       `json:"usage,omitempty"`
-	ProvisionedUsers   *bool                                `json:"provisionedUsers,omitempty"`
-	ProvisionedBuckets *bool                                `json:"provisionedBuckets,omitempty"`
+	CurrentState          *string                                `json:"currentState,omitempty"`
+	AvailableReplicas     *int32                                 `json:"availableReplicas,omitempty"`
+	Revision              *int32                                 `json:"revision,omitempty"`
+	SyncVersion           *string                                `json:"syncVersion,omitempty"`
+	Certificates          *CertificateStatusApplyConfiguration   `json:"certificates,omitempty"`
+	Pools                 []PoolStatusApplyConfiguration         `json:"pools,omitempty"`
+	WriteQuorum           *int32                                 `json:"writeQuorum,omitempty"`
+	DrivesOnline          *int32                                 `json:"drivesOnline,omitempty"`
+	DrivesOffline         *int32                                 `json:"drivesOffline,omitempty"`
+	DrivesHealing         *int32                                 `json:"drivesHealing,omitempty"`
+	HealthStatus          *miniominiov2.HealthStatus             `json:"healthStatus,omitempty"`
+	HealthMessage         *string                                `json:"healthMessage,omitempty"`
+	WaitingOnReady        *v1.Time                               `json:"waitingOnReady,omitempty"`
+	Usage                 *TenantUsageApplyConfiguration         `json:"usage,omitempty"`
+	ProvisionedUsers      *bool                                  `json:"provisionedUsers,omitempty"`
+	ProvisionedBuckets    *bool                                  `json:"provisionedBuckets,omitempty"`
+	Rebalance             *PoolRebalanceStatusApplyConfiguration `json:"rebalance,omitempty"`
+	ObservedGeneration    *int64                                 `json:"observedGeneration,omitempty"`
+	UpgradeHistory        []UpgradeRecordApplyConfiguration      `json:"upgradeHistory,omitempty"`
+	PendingChanges        []string                               `json:"pendingChanges,omitempty"`
+	NextMaintenanceWindow *v1.Time                               `json:"nextMaintenanceWindow,omitempty"`
+	Conditions            []metav1.ConditionApplyConfiguration   `json:"conditions,omitempty"`
 }
 
 // TenantStatusApplyConfiguration constructs an declarative configuration of the TenantStatus type for use with
@@ -182,3 +189,63 @@ func (b *TenantStatusApplyConfiguration) WithProvisionedBuckets(value bool) *Ten
 	b.ProvisionedBuckets = &value
 	return b
 }
+
+// WithRebalance sets the Rebalance field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Rebalance field is set to the value of the last call.
+func (b *TenantStatusApplyConfiguration) WithRebalance(value *PoolRebalanceStatusApplyConfiguration) *TenantStatusApplyConfiguration {
+	b.Rebalance = value
+	return b
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *TenantStatusApplyConfiguration) WithObservedGeneration(value int64) *TenantStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
+
+// WithUpgradeHistory adds the given value to the UpgradeHistory field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the UpgradeHistory field.
+func (b *TenantStatusApplyConfiguration) WithUpgradeHistory(values ...*UpgradeRecordApplyConfiguration) *TenantStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithUpgradeHistory")
+		}
+		b.UpgradeHistory = append(b.UpgradeHistory, *values[i])
+	}
+	return b
+}
+
+// WithPendingChanges adds the given value to the PendingChanges field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the PendingChanges field.
+func (b *TenantStatusApplyConfiguration) WithPendingChanges(values ...string) *TenantStatusApplyConfiguration {
+	for i := range values {
+		b.PendingChanges = append(b.PendingChanges, values[i])
+	}
+	return b
+}
+
+// WithNextMaintenanceWindow sets the NextMaintenanceWindow field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NextMaintenanceWindow field is set to the value of the last call.
+func (b *TenantStatusApplyConfiguration) WithNextMaintenanceWindow(value v1.Time) *TenantStatusApplyConfiguration {
+	b.NextMaintenanceWindow = &value
+	return b
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *TenantStatusApplyConfiguration) WithConditions(values ...*metav1.ConditionApplyConfiguration) *TenantStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
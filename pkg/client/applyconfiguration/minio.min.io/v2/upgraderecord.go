@@ -0,0 +1,134 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	miniominiov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UpgradeRecordApplyConfiguration represents an declarative configuration of the UpgradeRecord type for use
+// with apply.
+type UpgradeRecordApplyConfiguration struct {
+	FromImage        *string                     `json:"fromImage,omitempty"`
+	ToImage          *string                     `json:"toImage,omitempty"`
+	FromVersion      *string                     `json:"fromVersion,omitempty"`
+	ToVersion        *string                     `json:"toVersion,omitempty"`
+	Digest           *string                     `json:"digest,omitempty"`
+	VerificationHash *string                     `json:"verificationHash,omitempty"`
+	Result           *miniominiov2.UpgradeResult `json:"result,omitempty"`
+	Message          *string                     `json:"message,omitempty"`
+	StartTime        *v1.Time                    `json:"startTime,omitempty"`
+	UpdateTime       *v1.Time                    `json:"updateTime,omitempty"`
+	CompletionTime   *v1.Time                    `json:"completionTime,omitempty"`
+}
+
+// UpgradeRecordApplyConfiguration constructs an declarative configuration of the UpgradeRecord type for use with
+// apply.
+func UpgradeRecord() *UpgradeRecordApplyConfiguration {
+	return &UpgradeRecordApplyConfiguration{}
+}
+
+// WithFromImage sets the FromImage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FromImage field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithFromImage(value string) *UpgradeRecordApplyConfiguration {
+	b.FromImage = &value
+	return b
+}
+
+// WithToImage sets the ToImage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ToImage field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithToImage(value string) *UpgradeRecordApplyConfiguration {
+	b.ToImage = &value
+	return b
+}
+
+// WithFromVersion sets the FromVersion field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FromVersion field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithFromVersion(value string) *UpgradeRecordApplyConfiguration {
+	b.FromVersion = &value
+	return b
+}
+
+// WithToVersion sets the ToVersion field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ToVersion field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithToVersion(value string) *UpgradeRecordApplyConfiguration {
+	b.ToVersion = &value
+	return b
+}
+
+// WithDigest sets the Digest field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Digest field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithDigest(value string) *UpgradeRecordApplyConfiguration {
+	b.Digest = &value
+	return b
+}
+
+// WithVerificationHash sets the VerificationHash field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the VerificationHash field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithVerificationHash(value string) *UpgradeRecordApplyConfiguration {
+	b.VerificationHash = &value
+	return b
+}
+
+// WithResult sets the Result field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Result field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithResult(value miniominiov2.UpgradeResult) *UpgradeRecordApplyConfiguration {
+	b.Result = &value
+	return b
+}
+
+// WithMessage sets the Message field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Message field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithMessage(value string) *UpgradeRecordApplyConfiguration {
+	b.Message = &value
+	return b
+}
+
+// WithStartTime sets the StartTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StartTime field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithStartTime(value v1.Time) *UpgradeRecordApplyConfiguration {
+	b.StartTime = &value
+	return b
+}
+
+// WithUpdateTime sets the UpdateTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UpdateTime field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithUpdateTime(value v1.Time) *UpgradeRecordApplyConfiguration {
+	b.UpdateTime = &value
+	return b
+}
+
+// WithCompletionTime sets the CompletionTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CompletionTime field is set to the value of the last call.
+func (b *UpgradeRecordApplyConfiguration) WithCompletionTime(value v1.Time) *UpgradeRecordApplyConfiguration {
+	b.CompletionTime = &value
+	return b
+}
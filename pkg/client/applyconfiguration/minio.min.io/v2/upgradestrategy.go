@@ -0,0 +1,57 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v2
+
+// UpgradeStrategyApplyConfiguration represents an declarative configuration of the UpgradeStrategy type for use
+// with apply.
+type UpgradeStrategyApplyConfiguration struct {
+	PreflightChecks *bool  `json:"preflightChecks,omitempty"`
+	QuorumMargin    *int32 `json:"quorumMargin,omitempty"`
+	AutoRollback    *bool  `json:"autoRollback,omitempty"`
+}
+
+// UpgradeStrategyApplyConfiguration constructs an declarative configuration of the UpgradeStrategy type for use with
+// apply.
+func UpgradeStrategy() *UpgradeStrategyApplyConfiguration {
+	return &UpgradeStrategyApplyConfiguration{}
+}
+
+// WithPreflightChecks sets the PreflightChecks field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PreflightChecks field is set to the value of the last call.
+func (b *UpgradeStrategyApplyConfiguration) WithPreflightChecks(value bool) *UpgradeStrategyApplyConfiguration {
+	b.PreflightChecks = &value
+	return b
+}
+
+// WithQuorumMargin sets the QuorumMargin field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the QuorumMargin field is set to the value of the last call.
+func (b *UpgradeStrategyApplyConfiguration) WithQuorumMargin(value int32) *UpgradeStrategyApplyConfiguration {
+	b.QuorumMargin = &value
+	return b
+}
+
+// WithAutoRollback sets the AutoRollback field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AutoRollback field is set to the value of the last call.
+func (b *UpgradeStrategyApplyConfiguration) WithAutoRollback(value bool) *UpgradeStrategyApplyConfiguration {
+	b.AutoRollback = &value
+	return b
+}
@@ -23,6 +23,7 @@ package v1beta1
 type PolicyBindingSpecApplyConfiguration struct {
 	Application *ApplicationApplyConfiguration `json:"application,omitempty"`
 	Policies    []string                       `json:"policies,omitempty"`
+	Tenant      *string                        `json:"tenant,omitempty"`
 }
 
 // PolicyBindingSpecApplyConfiguration constructs an declarative configuration of the PolicyBindingSpec type for use with
@@ -48,3 +49,11 @@ func (b *PolicyBindingSpecApplyConfiguration) WithPolicies(values ...string) *Po
 	}
 	return b
 }
+
+// WithTenant sets the Tenant field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Tenant field is set to the value of the last call.
+func (b *PolicyBindingSpecApplyConfiguration) WithTenant(value string) *PolicyBindingSpecApplyConfiguration {
+	b.Tenant = &value
+	return b
+}
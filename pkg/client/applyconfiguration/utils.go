@@ -35,12 +35,26 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=job.min.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("ArgFromSource"):
+		return &jobminiov1alpha1.ArgFromSourceApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ArgValueSource"):
+		return &jobminiov1alpha1.ArgValueSourceApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("BatchJobSpec"):
+		return &jobminiov1alpha1.BatchJobSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("BatchJobStatus"):
+		return &jobminiov1alpha1.BatchJobStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("CommandPlan"):
+		return &jobminiov1alpha1.CommandPlanApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("CommandPolicy"):
+		return &jobminiov1alpha1.CommandPolicyApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("CommandSpec"):
 		return &jobminiov1alpha1.CommandSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("CommandStatus"):
 		return &jobminiov1alpha1.CommandStatusApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("MinIOJob"):
 		return &jobminiov1alpha1.MinIOJobApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("MinIOJobRun"):
+		return &jobminiov1alpha1.MinIOJobRunApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("MinIOJobSpec"):
 		return &jobminiov1alpha1.MinIOJobSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("MinIOJobStatus"):
@@ -63,14 +77,28 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &miniominiov2.ExposeServicesApplyConfiguration{}
 	case v2.SchemeGroupVersion.WithKind("Features"):
 		return &miniominiov2.FeaturesApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("ImageVerification"):
+		return &miniominiov2.ImageVerificationApplyConfiguration{}
 	case v2.SchemeGroupVersion.WithKind("KESConfig"):
 		return &miniominiov2.KESConfigApplyConfiguration{}
 	case v2.SchemeGroupVersion.WithKind("LocalCertificateReference"):
 		return &miniominiov2.LocalCertificateReferenceApplyConfiguration{}
 	case v2.SchemeGroupVersion.WithKind("Logging"):
 		return &miniominiov2.LoggingApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("MaintenanceWindow"):
+		return &miniominiov2.MaintenanceWindowApplyConfiguration{}
 	case v2.SchemeGroupVersion.WithKind("Pool"):
 		return &miniominiov2.PoolApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("PoolDecommissionStatus"):
+		return &miniominiov2.PoolDecommissionStatusApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("PoolRebalanceConfig"):
+		return &miniominiov2.PoolRebalanceConfigApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("PoolRebalanceProgress"):
+		return &miniominiov2.PoolRebalanceProgressApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("PoolRebalanceStatus"):
+		return &miniominiov2.PoolRebalanceStatusApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("PoolReplacementStatus"):
+		return &miniominiov2.PoolReplacementStatusApplyConfiguration{}
 	case v2.SchemeGroupVersion.WithKind("PoolStatus"):
 		return &miniominiov2.PoolStatusApplyConfiguration{}
 	case v2.SchemeGroupVersion.WithKind("ServiceMetadata"):
@@ -91,6 +119,12 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &miniominiov2.TenantUsageApplyConfiguration{}
 	case v2.SchemeGroupVersion.WithKind("TierUsage"):
 		return &miniominiov2.TierUsageApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("UpgradeRecord"):
+		return &miniominiov2.UpgradeRecordApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("UpgradeSource"):
+		return &miniominiov2.UpgradeSourceApplyConfiguration{}
+	case v2.SchemeGroupVersion.WithKind("UpgradeStrategy"):
+		return &miniominiov2.UpgradeStrategyApplyConfiguration{}
 
 		// Group=sts.min.io, Version=v1alpha1
 	case stsminiov1alpha1.SchemeGroupVersion.WithKind("Application"):
@@ -0,0 +1,593 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	stsv1beta1 "github.com/minio/operator/pkg/apis/sts.min.io/v1beta1"
+	"github.com/minio/operator/pkg/certs"
+	xcerts "github.com/minio/pkg/certs"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+// Admission webhook API constants
+const (
+	AdmissionDefaultPort                   int = 4225
+	AdmissionValidateTenantEndpoint            = "/admission/v1/validate/tenants"
+	AdmissionValidateMinIOJobEndpoint          = "/admission/v1/validate/miniojobs"
+	AdmissionValidatePolicyBindingEndpoint     = "/admission/v1/validate/policybindings"
+	AdmissionMutateTenantEndpoint              = "/admission/v1/mutate/tenants"
+)
+
+const (
+	// AdmissionWebhookEnabled Env variable name to turn on and off the admission webhook, enabled by default
+	AdmissionWebhookEnabled = "OPERATOR_ADMISSION_WEBHOOK_ENABLED"
+
+	// AdmissionWebhookAutoTLSEnabled Env variable name to turn on and off generation of the admission webhook TLS
+	// automatically using CSR, if disabled a certificate issued externally needs to be provided
+	AdmissionWebhookAutoTLSEnabled = "OPERATOR_ADMISSION_WEBHOOK_AUTO_TLS_ENABLED"
+
+	// AdmissionServiceName is the name of the service the API server reaches the admission webhook through
+	AdmissionServiceName = "operator-webhook"
+
+	// AdmissionTLSSecretName is the name of secret created for the Operator admission webhook TLS certs
+	AdmissionTLSSecretName = "operator-webhook-tls"
+
+	// ValidatingWebhookConfigurationName is the name of the ValidatingWebhookConfiguration the Operator maintains
+	ValidatingWebhookConfigurationName = "minio-operator-validating-webhook"
+
+	// MutatingWebhookConfigurationName is the name of the MutatingWebhookConfiguration the Operator maintains
+	MutatingWebhookConfigurationName = "minio-operator-mutating-webhook"
+)
+
+// admissionValidator returns an error if the object of the admission request must be rejected
+type admissionValidator func(request *admissionv1.AdmissionRequest) error
+
+// admissionMutator returns the JSON patch to apply to the object of the admission request, nil to leave it untouched,
+// or an error if the object must be rejected
+type admissionMutator func(request *admissionv1.AdmissionRequest) ([]byte, error)
+
+func configureAdmissionServer() *http.Server {
+	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
+
+	router.Methods(http.MethodPost).
+		Path(AdmissionValidateTenantEndpoint).
+		Handler(admissionHandler(validateTenantAdmission))
+	router.Methods(http.MethodPost).
+		Path(AdmissionValidateMinIOJobEndpoint).
+		Handler(admissionHandler(validateMinIOJobAdmission))
+	router.Methods(http.MethodPost).
+		Path(AdmissionValidatePolicyBindingEndpoint).
+		Handler(admissionHandler(validatePolicyBindingAdmission))
+	router.Methods(http.MethodPost).
+		Path(AdmissionMutateTenantEndpoint).
+		Handler(mutatingAdmissionHandler(mutateTenantAdmission))
+
+	router.NotFoundHandler = http.NotFoundHandler()
+
+	s := &http.Server{
+		Addr:           fmt.Sprintf(":%d", AdmissionDefaultPort),
+		Handler:        router,
+		ReadTimeout:    time.Minute,
+		WriteTimeout:   time.Minute,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	return s
+}
+
+// IsAdmissionWebhookEnabled Validates if the admission webhook is turned on, it is enabled by default
+func IsAdmissionWebhookEnabled() bool {
+	value, set := os.LookupEnv(AdmissionWebhookEnabled)
+	if set {
+		return value == "on"
+	}
+	return true
+}
+
+// IsAdmissionWebhookAutoTLSEnabled Validates if the admission webhook Autocert is turned on, it is enabled by default
+func IsAdmissionWebhookAutoTLSEnabled() bool {
+	value, set := os.LookupEnv(AdmissionWebhookAutoTLSEnabled)
+	if set {
+		return value == "on"
+	}
+	return true
+}
+
+// generateAdmissionTLSCert Issues the Operator admission webhook TLS Certificate
+func (c *Controller) generateAdmissionTLSCert() (*string, *string) {
+	return c.generateTLSCertificateForService(AdmissionServiceName, AdmissionTLSSecretName, getOperatorDeploymentName())
+}
+
+// waitAdmissionTLSCert Waits for the Operator leader to issue the TLS Certificate for the admission webhook
+func (c *Controller) waitAdmissionTLSCert() (string, string) {
+	return c.waitForCertSecretReady(AdmissionServiceName, AdmissionTLSSecretName)
+}
+
+// startAdmissionServer Starts the admission webhook server and notifies the stop via notificationChannel
+func (c *Controller) startAdmissionServer(ctx context.Context, notificationChannel chan<- *EventNotification) {
+	klog.Infof("Starting admission webhook server")
+
+	publicCertPath, privateKeyPath := c.waitAdmissionTLSCert()
+	certsManager, err := xcerts.NewManager(ctx, publicCertPath, privateKeyPath, LoadX509KeyPair)
+	if err != nil {
+		klog.Errorf("HTTPS admission webhook server failed to load certificate: %v", err)
+		notificationChannel <- &EventNotification{
+			Type: AdmissionServerNotification,
+			Err:  err,
+		}
+		return
+	}
+	c.admission.TLSConfig = c.createTLSConfig(certsManager)
+
+	if err := c.admission.ListenAndServeTLS("", ""); !errors.Is(err, http.ErrServerClosed) {
+		// only notify on server failure, on http.ErrServerClosed the channel should be already closed
+		notificationChannel <- &EventNotification{
+			Type: AdmissionServerNotification,
+			Err:  err,
+		}
+	}
+}
+
+// admissionHandler decodes the AdmissionReview sent by the API server and allows the object unless the validator
+// rejects it
+func admissionHandler(validate admissionValidator) http.HandlerFunc {
+	return mutatingAdmissionHandler(func(request *admissionv1.AdmissionRequest) ([]byte, error) {
+		return nil, validate(request)
+	})
+}
+
+// mutatingAdmissionHandler decodes the AdmissionReview sent by the API server and allows the object along with the
+// patch of the mutator, unless the mutator rejects it
+func mutatingAdmissionHandler(mutate admissionMutator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		review := &admissionv1.AdmissionReview{}
+		if err = json.Unmarshal(body, review); err != nil || review.Request == nil {
+			http.Error(w, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+		response := &admissionv1.AdmissionResponse{
+			UID:     review.Request.UID,
+			Allowed: true,
+		}
+		patch, err := mutate(review.Request)
+		if err != nil {
+			klog.V(2).Infof("Rejecting %s of %s %s/%s: %v", review.Request.Operation, review.Request.Kind.Kind, review.Request.Namespace, review.Request.Name, err)
+			response.Allowed = false
+			response.Result = &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+				Message: err.Error(),
+			}
+		} else if patch != nil {
+			patchType := admissionv1.PatchTypeJSONPatch
+			response.Patch = patch
+			response.PatchType = &patchType
+		}
+		review.Response = response
+		review.Request = nil
+		payload, err := json.Marshal(review)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(payload)
+	}
+}
+
+// decodeAdmissionObjects decodes the object of the admission request, and the old object on updates. It returns
+// false if the update leaves the spec untouched, like the finalizers and annotations the Operator sets, or if the
+// object is being deleted, those are never rejected
+func decodeAdmissionObjects(request *admissionv1.AdmissionRequest, object, oldObject metav1.Object, spec func(metav1.Object) interface{}) (bool, error) {
+	if err := json.Unmarshal(request.Object.Raw, object); err != nil {
+		return false, err
+	}
+	if object.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+	if request.Operation != admissionv1.Update {
+		return true, nil
+	}
+	if err := json.Unmarshal(request.OldObject.Raw, oldObject); err != nil {
+		return false, err
+	}
+	return !equality.Semantic.DeepEqual(spec(object), spec(oldObject)), nil
+}
+
+// validateTenantAdmission validates the tenant the way the Operator does before reconciling it, and that the update
+// doesn't change the fields of the existing pools that can't be changed
+func validateTenantAdmission(request *admissionv1.AdmissionRequest) error {
+	tenant, oldTenant := &miniov2.Tenant{}, &miniov2.Tenant{}
+	validate, err := decodeAdmissionObjects(request, tenant, oldTenant, func(o metav1.Object) interface{} {
+		return o.(*miniov2.Tenant).Spec
+	})
+	if err != nil || !validate {
+		return err
+	}
+	if request.Operation == admissionv1.Update {
+		if err = validatePoolRemoval(tenant, oldTenant); err != nil {
+			return err
+		}
+	}
+	tenant.EnsureDefaults()
+	if err = tenant.Validate(); err != nil {
+		return err
+	}
+	if request.Operation == admissionv1.Update {
+		oldTenant.EnsureDefaults()
+		return tenant.ValidateUpdate(oldTenant)
+	}
+	return nil
+}
+
+// validatePoolRemoval rejects the removal of a pool while some pools have no name, the pools without name are told
+// apart by their index which changes when a pool is removed
+func validatePoolRemoval(tenant, oldTenant *miniov2.Tenant) error {
+	if len(tenant.Spec.Pools) >= len(oldTenant.Spec.Pools) {
+		return nil
+	}
+	for i, pool := range oldTenant.Spec.Pools {
+		if pool.Name == "" {
+			return fmt.Errorf("pool #%d has no name, name every pool before removing one", i)
+		}
+	}
+	for i, pool := range tenant.Spec.Pools {
+		if pool.Name == "" {
+			return fmt.Errorf("pool #%d has no name, name every pool before removing one", i)
+		}
+	}
+	return nil
+}
+
+// mutateTenantAdmission persists the defaults the Operator applies to the tenant, so the spec stored in the cluster
+// is the one the Operator and the sidecar work with
+func mutateTenantAdmission(request *admissionv1.AdmissionRequest) ([]byte, error) {
+	tenant := &miniov2.Tenant{}
+	if err := json.Unmarshal(request.Object.Raw, tenant); err != nil {
+		return nil, err
+	}
+	if tenant.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	defaulted := tenant.DeepCopy()
+	defaulted.EnsureDefaults()
+	// the default DNS names of the certificate follow the pools, they are computed at every reconcile instead
+	if tenant.Spec.CertConfig == nil || len(tenant.Spec.CertConfig.DNSNames) == 0 {
+		defaulted.Spec.CertConfig.DNSNames = nil
+	}
+	if request.Operation == admissionv1.Update {
+		oldTenant := &miniov2.Tenant{}
+		if err := json.Unmarshal(request.OldObject.Raw, oldTenant); err != nil {
+			return nil, err
+		}
+		defaultPoolNames(defaulted, tenant, oldTenant)
+	}
+	patch := tenantDefaultsPatch(tenant, defaulted)
+	if len(patch) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(patch)
+}
+
+// defaultPoolNames names the pools without name of an updated tenant after the pools of the stored tenant, a pool
+// keeps the name of the pool at its index. While a pool is removed the indexes don't match the deployed pools
+// anymore, the pools are left without name and the removal is rejected.
+func defaultPoolNames(defaulted, tenant, oldTenant *miniov2.Tenant) {
+	for i, pool := range tenant.Spec.Pools {
+		if pool.Name != "" {
+			continue
+		}
+		switch {
+		case len(tenant.Spec.Pools) < len(oldTenant.Spec.Pools):
+			defaulted.Spec.Pools[i].Name = ""
+		case i < len(oldTenant.Spec.Pools) && oldTenant.Spec.Pools[i].Name != "":
+			defaulted.Spec.Pools[i].Name = oldTenant.Spec.Pools[i].Name
+		}
+	}
+}
+
+// tenantDefaultsPatch returns the JSON patch setting the fields of the tenant its defaults changed, the other fields
+// are left as they are stored
+func tenantDefaultsPatch(tenant, defaulted *miniov2.Tenant) []map[string]interface{} {
+	var patch []map[string]interface{}
+	// "add" replaces the field if present
+	add := func(path string, value, defaultValue interface{}) {
+		if !equality.Semantic.DeepEqual(value, defaultValue) {
+			patch = append(patch, map[string]interface{}{"op": "add", "path": path, "value": defaultValue})
+		}
+	}
+	spec, defaults := tenant.Spec, defaulted.Spec
+	add("/spec/podManagementPolicy", spec.PodManagementPolicy, defaults.PodManagementPolicy)
+	add("/spec/image", spec.Image, defaults.Image)
+	add("/spec/imagePullPolicy", spec.ImagePullPolicy, defaults.ImagePullPolicy)
+	for i := range spec.Pools {
+		add(fmt.Sprintf("/spec/pools/%d/name", i), spec.Pools[i].Name, defaults.Pools[i].Name)
+	}
+	add("/spec/mountPath", spec.Mountpath, defaults.Mountpath)
+	add("/spec/subPath", spec.Subpath, defaults.Subpath)
+	if spec.CertConfig == nil {
+		add("/spec/certConfig", spec.CertConfig, defaults.CertConfig)
+	} else {
+		add("/spec/certConfig/commonName", spec.CertConfig.CommonName, defaults.CertConfig.CommonName)
+		add("/spec/certConfig/organizationName", spec.CertConfig.OrganizationName, defaults.CertConfig.OrganizationName)
+	}
+	if spec.KES != nil {
+		add("/spec/kes/image", spec.KES.Image, defaults.KES.Image)
+		add("/spec/kes/replicas", spec.KES.Replicas, defaults.KES.Replicas)
+		add("/spec/kes/imagePullPolicy", spec.KES.ImagePullPolicy, defaults.KES.ImagePullPolicy)
+		add("/spec/kes/keyName", spec.KES.KeyName, defaults.KES.KeyName)
+		add("/spec/kes/serviceAccountName", spec.KES.ServiceAccountName, defaults.KES.ServiceAccountName)
+	}
+	add("/spec/serviceAccountName", spec.ServiceAccountName, defaults.ServiceAccountName)
+	return patch
+}
+
+// validateMinIOJobAdmission validates the commands of the MinIOJob and their dependencies
+func validateMinIOJobAdmission(request *admissionv1.AdmissionRequest) error {
+	jobCR, oldJobCR := &v1alpha1.MinIOJob{}, &v1alpha1.MinIOJob{}
+	validate, err := decodeAdmissionObjects(request, jobCR, oldJobCR, func(o metav1.Object) interface{} {
+		return o.(*v1alpha1.MinIOJob).Spec
+	})
+	if err != nil || !validate {
+		return err
+	}
+	_, err = checkMinIOJob(jobCR)
+	return err
+}
+
+// validatePolicyBindingAdmission validates the application and the policies of the PolicyBinding
+func validatePolicyBindingAdmission(request *admissionv1.AdmissionRequest) error {
+	policyBinding, oldPolicyBinding := &stsv1beta1.PolicyBinding{}, &stsv1beta1.PolicyBinding{}
+	validate, err := decodeAdmissionObjects(request, policyBinding, oldPolicyBinding, func(o metav1.Object) interface{} {
+		return o.(*stsv1beta1.PolicyBinding).Spec
+	})
+	if err != nil || !validate {
+		return err
+	}
+	return validatePolicyBinding(policyBinding)
+}
+
+// validatePolicyBinding returns an error if the PolicyBinding can't authorize any application
+func validatePolicyBinding(policyBinding *stsv1beta1.PolicyBinding) error {
+	application := policyBinding.Spec.Application
+	if application == nil {
+		return errors.New("application must be specified")
+	}
+	if application.Namespace == "" {
+		return errors.New("application namespace is empty")
+	}
+	if application.ServiceAccount == "" {
+		return errors.New("application serviceaccount is empty")
+	}
+	if len(policyBinding.Spec.Policies) == 0 {
+		return errors.New("at least one policy must be specified")
+	}
+	for i, policy := range policyBinding.Spec.Policies {
+		if policy == "" {
+			return fmt.Errorf("policy #%d is empty", i)
+		}
+	}
+	return nil
+}
+
+// admissionCABundle returns the CA the API server verifies the admission webhook certificate with, the CA of the
+// certificate secret if provided, otherwise the cluster CA that signs the certificates issued through CSR
+func (c *Controller) admissionCABundle(ctx context.Context) ([]byte, error) {
+	secret, err := c.getCertificateSecret(ctx, miniov2.GetNSFromFile(), AdmissionTLSSecretName)
+	if err != nil {
+		return nil, err
+	}
+	if caBundle, ok := secret.Data[certs.CAPublicCertFile]; ok && len(caBundle) > 0 {
+		return caBundle, nil
+	}
+	caBundle := miniov2.GetPodCAFromFile()
+	if len(caBundle) == 0 {
+		return nil, errors.New("unable to read the cluster CA certificate")
+	}
+	return caBundle, nil
+}
+
+// admissionWebhookClientConfig returns how the API server reaches the admission webhook endpoint at path
+func admissionWebhookClientConfig(path string, caBundle []byte) admissionregistrationv1.WebhookClientConfig {
+	port := int32(AdmissionDefaultPort)
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: miniov2.GetNSFromFile(),
+			Name:      AdmissionServiceName,
+			Path:      &path,
+			Port:      &port,
+		},
+		CABundle: caBundle,
+	}
+}
+
+// admissionWebhookRules returns the rules sending the creation and the updates of the resource to the webhook
+func admissionWebhookRules(groupVersion schema.GroupVersion, resource string) []admissionregistrationv1.RuleWithOperations {
+	scope := admissionregistrationv1.NamespacedScope
+	return []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{groupVersion.Group},
+			APIVersions: []string{groupVersion.Version},
+			Resources:   []string{resource},
+			Scope:       &scope,
+		},
+	}}
+}
+
+// admissionNamespaceSelector restricts the webhooks to the namespaces watched by the Operator, if any
+func (c *Controller) admissionNamespaceSelector() *metav1.LabelSelector {
+	if len(c.namespacesToWatch) == 0 {
+		return nil
+	}
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      corev1.LabelMetadataName,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   c.namespacesToWatch.ToSlice(),
+		}},
+	}
+}
+
+// newValidatingWebhookConfiguration returns the ValidatingWebhookConfiguration that sends the Tenants, MinIOJobs and
+// PolicyBindings to the admission webhook of the Operator
+func (c *Controller) newValidatingWebhookConfiguration(caBundle []byte) *admissionregistrationv1.ValidatingWebhookConfiguration {
+	// the reconcile loop validates the objects anyway, don't block them while the Operator is unavailable
+	failurePolicy := admissionregistrationv1.Ignore
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	timeoutSeconds := int32(10)
+	webhook := func(name, path string, groupVersion schema.GroupVersion, resource string) admissionregistrationv1.ValidatingWebhook {
+		return admissionregistrationv1.ValidatingWebhook{
+			Name:                    name,
+			ClientConfig:            admissionWebhookClientConfig(path, caBundle),
+			Rules:                   admissionWebhookRules(groupVersion, resource),
+			NamespaceSelector:       c.admissionNamespaceSelector(),
+			FailurePolicy:           &failurePolicy,
+			SideEffects:             &sideEffects,
+			TimeoutSeconds:          &timeoutSeconds,
+			AdmissionReviewVersions: []string{"v1"},
+		}
+	}
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ValidatingWebhookConfigurationName,
+			Labels: map[string]string{
+				"app.kubernetes.io/name": "operator",
+			},
+		},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			webhook("tenants.validation.min.io", AdmissionValidateTenantEndpoint, miniov2.SchemeGroupVersion, "tenants"),
+			webhook("miniojobs.validation.min.io", AdmissionValidateMinIOJobEndpoint, v1alpha1.SchemeGroupVersion, "miniojobs"),
+			webhook("policybindings.validation.min.io", AdmissionValidatePolicyBindingEndpoint, stsv1beta1.SchemeGroupVersion, "policybindings"),
+		},
+	}
+}
+
+// newMutatingWebhookConfiguration returns the MutatingWebhookConfiguration that sends the Tenants to the defaulting
+// webhook of the Operator
+func (c *Controller) newMutatingWebhookConfiguration(caBundle []byte) *admissionregistrationv1.MutatingWebhookConfiguration {
+	// the reconcile loop applies the defaults in memory anyway, don't block the tenants while the Operator is unavailable
+	failurePolicy := admissionregistrationv1.Ignore
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	timeoutSeconds := int32(10)
+	return &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: MutatingWebhookConfigurationName,
+			Labels: map[string]string{
+				"app.kubernetes.io/name": "operator",
+			},
+		},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name:                    "tenants.defaulting.min.io",
+			ClientConfig:            admissionWebhookClientConfig(AdmissionMutateTenantEndpoint, caBundle),
+			Rules:                   admissionWebhookRules(miniov2.SchemeGroupVersion, "tenants"),
+			NamespaceSelector:       c.admissionNamespaceSelector(),
+			FailurePolicy:           &failurePolicy,
+			SideEffects:             &sideEffects,
+			TimeoutSeconds:          &timeoutSeconds,
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+}
+
+// syncWebhookConfigurations creates or updates the ValidatingWebhookConfiguration and the
+// MutatingWebhookConfiguration of the Operator once the admission webhook certificate is available
+func (c *Controller) syncWebhookConfigurations(ctx context.Context) error {
+	caBundle, err := c.admissionCABundle(ctx)
+	if err != nil {
+		return err
+	}
+	if err = c.syncValidatingWebhookConfiguration(ctx, c.newValidatingWebhookConfiguration(caBundle)); err != nil {
+		return err
+	}
+	return c.syncMutatingWebhookConfiguration(ctx, c.newMutatingWebhookConfiguration(caBundle))
+}
+
+// syncValidatingWebhookConfiguration creates or updates the ValidatingWebhookConfiguration of the Operator
+func (c *Controller) syncValidatingWebhookConfiguration(ctx context.Context, expected *admissionregistrationv1.ValidatingWebhookConfiguration) error {
+	client := c.kubeClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	current, err := client.Get(ctx, expected.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		klog.Infof("Creating ValidatingWebhookConfiguration %s", expected.Name)
+		_, err = client.Create(ctx, expected, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	klog.Infof("Updating ValidatingWebhookConfiguration %s", expected.Name)
+	current.Webhooks = expected.Webhooks
+	_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+	return err
+}
+
+// syncMutatingWebhookConfiguration creates or updates the MutatingWebhookConfiguration of the Operator
+func (c *Controller) syncMutatingWebhookConfiguration(ctx context.Context, expected *admissionregistrationv1.MutatingWebhookConfiguration) error {
+	client := c.kubeClientSet.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	current, err := client.Get(ctx, expected.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		klog.Infof("Creating MutatingWebhookConfiguration %s", expected.Name)
+		_, err = client.Create(ctx, expected, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	klog.Infof("Updating MutatingWebhookConfiguration %s", expected.Name)
+	current.Webhooks = expected.Webhooks
+	_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+	return err
+}
+
+// deleteWebhookConfigurations removes the ValidatingWebhookConfiguration and the MutatingWebhookConfiguration of the
+// Operator when the admission webhook is turned off, so the API server doesn't keep calling it
+func (c *Controller) deleteWebhookConfigurations(ctx context.Context) error {
+	err := c.kubeClientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(ctx, ValidatingWebhookConfigurationName, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	err = c.kubeClientSet.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete(ctx, MutatingWebhookConfigurationName, metav1.DeleteOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,294 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	stsv1beta1 "github.com/minio/operator/pkg/apis/sts.min.io/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newAdmissionTestTenant(servers int32) *miniov2.Tenant {
+	return &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+		Spec: miniov2.TenantSpec{
+			Configuration: &corev1.LocalObjectReference{Name: "myminio-env-configuration"},
+			Pools: []miniov2.Pool{{
+				Name:             "pool-0",
+				Servers:          servers,
+				VolumesPerServer: 4,
+				VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// review sends the objects to the admission endpoint and returns the response of the admission webhook
+func review(t *testing.T, path string, operation admissionv1.Operation, object, oldObject interface{}) *admissionv1.AdmissionResponse {
+	t.Helper()
+	request := &admissionv1.AdmissionRequest{UID: "review-uid", Operation: operation}
+	raw, err := json.Marshal(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Object = runtime.RawExtension{Raw: raw}
+	if oldObject != nil {
+		if raw, err = json.Marshal(oldObject); err != nil {
+			t.Fatal(err)
+		}
+		request.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	body, _ := json.Marshal(admissionv1.AdmissionReview{Request: request})
+	recorder := httptest.NewRecorder()
+	configureAdmissionServer().Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", recorder.Code, recorder.Body.String())
+	}
+	response := &admissionv1.AdmissionReview{}
+	if err = json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Response == nil || response.Response.UID != "review-uid" {
+		t.Fatalf("unexpected admission review %+v", response)
+	}
+	return response.Response
+}
+
+func TestTenantAdmission(t *testing.T) {
+	invalid := newAdmissionTestTenant(0)
+	noConfiguration := newAdmissionTestTenant(4)
+	noConfiguration.Spec.Configuration = nil
+	resized := newAdmissionTestTenant(8)
+	deleting := newAdmissionTestTenant(8)
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	labeled := newAdmissionTestTenant(0)
+	labeled.Labels = map[string]string{"team": "storage"}
+	badServiceName := newAdmissionTestTenant(4)
+	badServiceName.Spec.ServiceName = "myminio-hl"
+	renamed := newAdmissionTestTenant(4)
+	renamed.Spec.ServiceName = "analytics"
+
+	testCases := []struct {
+		name      string
+		operation admissionv1.Operation
+		object    *miniov2.Tenant
+		oldObject *miniov2.Tenant
+		allowed   bool
+	}{
+		{name: "valid", operation: admissionv1.Create, object: newAdmissionTestTenant(4), allowed: true},
+		{name: "no servers", operation: admissionv1.Create, object: invalid},
+		{name: "no configuration", operation: admissionv1.Create, object: noConfiguration},
+		{name: "service name of the headless service", operation: admissionv1.Create, object: badServiceName},
+		{name: "service name changed", operation: admissionv1.Update, object: renamed, oldObject: newAdmissionTestTenant(4)},
+		{name: "servers changed", operation: admissionv1.Update, object: resized, oldObject: newAdmissionTestTenant(4)},
+		{name: "being deleted", operation: admissionv1.Update, object: deleting, oldObject: newAdmissionTestTenant(4), allowed: true},
+		{name: "spec unchanged", operation: admissionv1.Update, object: labeled, oldObject: newAdmissionTestTenant(0), allowed: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var oldObject interface{}
+			if tc.oldObject != nil {
+				oldObject = tc.oldObject
+			}
+			response := review(t, AdmissionValidateTenantEndpoint, tc.operation, tc.object, oldObject)
+			if response.Allowed != tc.allowed {
+				t.Errorf("expected allowed %v, got %+v", tc.allowed, response.Result)
+			}
+		})
+	}
+}
+
+func TestMinIOJobAdmission(t *testing.T) {
+	job := func(commands ...v1alpha1.CommandSpec) *v1alpha1.MinIOJob {
+		return &v1alpha1.MinIOJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "tenant-ns"},
+			Spec: v1alpha1.MinIOJobSpec{
+				ServiceAccountName: "job-sa",
+				TenantRef:          v1alpha1.TenantRef{Name: "myminio", Namespace: "tenant-ns"},
+				Commands:           commands,
+			},
+		}
+	}
+	mb := func(name string, dependsOn ...string) v1alpha1.CommandSpec {
+		return v1alpha1.CommandSpec{Name: name, Operation: "mb", Args: map[string]string{"name": "memes"}, DependsOn: dependsOn}
+	}
+
+	if response := review(t, AdmissionValidateMinIOJobEndpoint, admissionv1.Create, job(mb("a"), mb("b", "a")), nil); !response.Allowed {
+		t.Errorf("expected the job to be allowed, got %+v", response.Result)
+	}
+	if response := review(t, AdmissionValidateMinIOJobEndpoint, admissionv1.Create, job(mb("a", "b"), mb("b", "a")), nil); response.Allowed {
+		t.Errorf("expected the job with a dependency cycle to be rejected")
+	}
+	if response := review(t, AdmissionValidateMinIOJobEndpoint, admissionv1.Create, job(mb("a", "missing")), nil); response.Allowed {
+		t.Errorf("expected the job depending on an unknown command to be rejected")
+	}
+}
+
+func TestPolicyBindingAdmission(t *testing.T) {
+	policyBinding := func(namespace, serviceAccount string, policies ...string) *stsv1beta1.PolicyBinding {
+		return &stsv1beta1.PolicyBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: "tenant-ns"},
+			Spec: stsv1beta1.PolicyBindingSpec{
+				Application: &stsv1beta1.Application{Namespace: namespace, ServiceAccount: serviceAccount},
+				Policies:    policies,
+			},
+		}
+	}
+	testCases := []struct {
+		name          string
+		policyBinding *stsv1beta1.PolicyBinding
+		allowed       bool
+	}{
+		{name: "valid", policyBinding: policyBinding("app-ns", "app-sa", "readwrite"), allowed: true},
+		{name: "no namespace", policyBinding: policyBinding("", "app-sa", "readwrite")},
+		{name: "no service account", policyBinding: policyBinding("app-ns", "", "readwrite")},
+		{name: "no policies", policyBinding: policyBinding("app-ns", "app-sa")},
+		{name: "empty policy", policyBinding: policyBinding("app-ns", "app-sa", "readwrite", "")},
+		{name: "no application", policyBinding: &stsv1beta1.PolicyBinding{Spec: stsv1beta1.PolicyBindingSpec{Policies: []string{"readwrite"}}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := review(t, AdmissionValidatePolicyBindingEndpoint, admissionv1.Create, tc.policyBinding, nil)
+			if response.Allowed != tc.allowed {
+				t.Errorf("expected allowed %v, got %+v", tc.allowed, response.Result)
+			}
+		})
+	}
+}
+
+// defaultingPatch sends the tenant to the mutating admission endpoint and returns the values its patch sets by path
+func defaultingPatch(t *testing.T, operation admissionv1.Operation, tenant, oldTenant *miniov2.Tenant) map[string]json.RawMessage {
+	t.Helper()
+	var oldObject interface{}
+	if oldTenant != nil {
+		oldObject = oldTenant
+	}
+	response := review(t, AdmissionMutateTenantEndpoint, operation, tenant, oldObject)
+	if !response.Allowed {
+		t.Fatalf("expected the tenant to be allowed, got %+v", response.Result)
+	}
+	if response.Patch == nil {
+		return nil
+	}
+	if response.PatchType == nil || *response.PatchType != admissionv1.PatchTypeJSONPatch {
+		t.Fatalf("unexpected patch type %v", response.PatchType)
+	}
+	var patch []struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(response.Patch, &patch); err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]json.RawMessage{}
+	for _, operation := range patch {
+		if operation.Op != "add" {
+			t.Fatalf("unexpected patch %s", response.Patch)
+		}
+		values[operation.Path] = operation.Value
+	}
+	return values
+}
+
+func TestTenantDefaultingAdmission(t *testing.T) {
+	tenant := newAdmissionTestTenant(4)
+	tenant.Spec.Pools[0].Name = ""
+	patch := defaultingPatch(t, admissionv1.Create, tenant, nil)
+	if _, ok := patch["/spec"]; ok {
+		t.Fatalf("expected only the defaulted fields to be patched, got %v", patch)
+	}
+	expected := map[string]string{
+		"/spec/pools/0/name":        `"` + miniov2.StatefulSetPrefix + `-0"`,
+		"/spec/mountPath":           `"` + miniov2.MinIOVolumeMountPath + `"`,
+		"/spec/serviceAccountName":  `"myminio-sa"`,
+		"/spec/podManagementPolicy": `"Parallel"`,
+	}
+	for path, value := range expected {
+		if string(patch[path]) != value {
+			t.Errorf("expected %s to be set to %s, got %s", path, value, patch[path])
+		}
+	}
+	if _, ok := patch["/spec/image"]; !ok {
+		t.Errorf("expected the image to be set")
+	}
+	certConfig := &miniov2.CertificateConfig{}
+	if err := json.Unmarshal(patch["/spec/certConfig"], certConfig); err != nil || certConfig.CommonName == "" || len(certConfig.DNSNames) != 0 {
+		t.Errorf("expected the cert config defaults without the DNS names, got %s", patch["/spec/certConfig"])
+	}
+
+	// a tenant holding its defaults already is left untouched
+	defaulted := tenant.DeepCopy()
+	defaulted.EnsureDefaults()
+	defaulted.Spec.CertConfig.DNSNames = nil
+	if patch = defaultingPatch(t, admissionv1.Update, defaulted, tenant); patch != nil {
+		t.Errorf("expected no patch for a defaulted tenant, got %v", patch)
+	}
+}
+
+func TestTenantDefaultingPoolNames(t *testing.T) {
+	pool := newAdmissionTestTenant(4).Spec.Pools[0]
+	tenant := func(names ...string) *miniov2.Tenant {
+		tenant := newAdmissionTestTenant(4)
+		tenant.Spec.Pools = nil
+		for _, name := range names {
+			pool.Name = name
+			tenant.Spec.Pools = append(tenant.Spec.Pools, pool)
+		}
+		return tenant
+	}
+
+	// an added pool is named after its index, the existing pools after the stored tenant
+	patch := defaultingPatch(t, admissionv1.Update, tenant("", "", ""), tenant("", "data"))
+	if string(patch["/spec/pools/0/name"]) != `"`+miniov2.StatefulSetPrefix+`-0"` || string(patch["/spec/pools/1/name"]) != `"data"` ||
+		string(patch["/spec/pools/2/name"]) != `"`+miniov2.StatefulSetPrefix+`-2"` {
+		t.Errorf("unexpected pool names %s, %s, %s", patch["/spec/pools/0/name"], patch["/spec/pools/1/name"], patch["/spec/pools/2/name"])
+	}
+
+	// removing the first of two unnamed pools doesn't name the remaining pool after the removed one
+	patch = defaultingPatch(t, admissionv1.Update, tenant(""), tenant("", ""))
+	if name, ok := patch["/spec/pools/0/name"]; ok {
+		t.Errorf("expected the remaining pool to be left without name, got %s", name)
+	}
+	response := review(t, AdmissionValidateTenantEndpoint, admissionv1.Update, tenant(""), tenant("", ""))
+	if response.Allowed {
+		t.Errorf("expected the removal of an unnamed pool to be rejected")
+	}
+	response = review(t, AdmissionValidateTenantEndpoint, admissionv1.Update, tenant("pool-1"), tenant("pool-0", "pool-1"))
+	if !response.Allowed {
+		t.Errorf("expected the removal of a named pool to be allowed, got %+v", response.Result)
+	}
+}
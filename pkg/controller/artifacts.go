@@ -18,14 +18,18 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/minio/pkg/env"
 
 	"k8s.io/klog/v2"
 
@@ -36,11 +40,25 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	imagespecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
+const (
+	// UpgradeSourceRootEnv Env variable to specify the directory of the Operator pod the upgrade sources of tenants are
+	// mounted under
+	UpgradeSourceRootEnv = "OPERATOR_UPGRADE_SOURCE_ROOT"
+	// DefaultUpgradeSourceRoot is the default directory the upgrade sources of tenants are mounted under
+	DefaultUpgradeSourceRoot = "/artifacts"
+)
+
+// defaultPlatform is the platform remote.Image picks from multi-platform images
+var defaultPlatform = v1.Platform{OS: "linux", Architecture: "amd64"}
+
 // minioKeychain implements Keychain to pass custom credentials
 type minioKeychain struct {
 	authn.Keychain
@@ -93,42 +111,68 @@ func (c *Controller) getKeychainForTenant(ctx context.Context, ref name.Referenc
 
 // Attempts to fetch given image and then extracts and keeps relevant files
 // (minio, minio.sha256sum & minio.minisig) at a pre-defined location (/tmp/webhook/v1/update)
-func (c *Controller) fetchArtifacts(tenant *miniov2.Tenant) (latest string, err error) {
+// The image is pulled from its registry, or read from the upgrade source of the tenant in air-gapped clusters. With an
+// image verification policy the digest the image was verified with is returned to pin the pools to it, unless the
+// image was read from a volume and its reference has no digest.
+func (c *Controller) fetchArtifacts(tenant *miniov2.Tenant) (latest string, digest string, err error) {
 	c.removeArtifacts() // remove before a fresh fetch.
 
 	basePath := updatePath
 
 	if err = os.MkdirAll(basePath, 1777); err != nil {
-		return latest, err
+		return latest, digest, err
 	}
 
 	ref, err := name.ParseReference(tenant.Spec.Image)
 	if err != nil {
-		return latest, err
+		return latest, digest, err
 	}
 
+	var img v1.Image
+	var digests []v1.Hash
+	// the keychain is only set for images pulled from a registry, where their signatures are looked up as well
 	var keychain authn.Keychain
-	keychain = authn.DefaultKeychain
-
-	// if the tenant has imagePullSecret use that for pulling the image, but if we fail to extract the secret or we
-	// can't find the expected registry in the secret we will continue with the default keychain. This is because the
-	// needed pull secret could be attached to the service-account.
-	if tenant.Spec.ImagePullSecret.Name != "" {
-		// Get the secret
-		keychain, err = c.getKeychainForTenant(context.Background(), ref, tenant)
-		if err != nil {
-			klog.Info(err)
+	source := tenant.Spec.UpgradeSource
+	local := source != nil && source.Type != "" && source.Type != miniov2.UpgradeSourceRegistry
+	var sourcePath string
+	if local {
+		if sourcePath, err = upgradeSourcePath(env.Get(UpgradeSourceRootEnv, DefaultUpgradeSourceRoot), source.Path); err != nil {
+			return latest, digest, err
 		}
 	}
-
-	img, err := remote.Image(ref, remote.WithAuthFromKeychain(keychain))
+	switch {
+	case !local:
+		keychain = c.keychainForImage(ref, tenant)
+		img, digests, err = remoteImage(ref, keychain)
+	case source.Type == miniov2.UpgradeSourceOCILayout:
+		img, digests, err = layoutImage(sourcePath, ref)
+	case source.Type == miniov2.UpgradeSourceImageTarball:
+		img, err = tarballImage(sourcePath, ref)
+	case source.Type == miniov2.UpgradeSourceBundle:
+		if _, ok := ref.(name.Digest); ok {
+			return latest, digest, fmt.Errorf("the bundle %s can't be matched with the digest of %s", source.Path, tenant.Spec.Image)
+		}
+		if latest, err = copyBundle(sourcePath, basePath); err != nil {
+			return latest, digest, err
+		}
+		if err = checkSourceRelease(tenant.Spec.Image, latest); err != nil {
+			return latest, digest, err
+		}
+		if err = c.verifyImage(context.Background(), tenant, ref, nil, latest, nil); err != nil {
+			return latest, digest, err
+		}
+		latest, err = publishArtifacts(basePath, latest)
+		return latest, digest, err
+	default:
+		err = fmt.Errorf("unsupported upgrade source %s", source.Type)
+	}
 	if err != nil {
-		return latest, err
+		return latest, digest, err
 	}
 
 	cfg, err := img.ConfigFile()
 	if err != nil {
-		return latest, err
+		return latest, digest, err
 	}
 
 	tag, ok := cfg.Config.Labels["release"]
@@ -137,12 +181,41 @@ func (c *Controller) fetchArtifacts(tenant *miniov2.Tenant) (latest string, err
 	}
 	tag = strings.TrimSpace(tag)
 	if !ok || tag == "" {
-		return latest, errors.New("missing tag")
+		return latest, digest, errors.New("missing tag")
+	}
+	if local {
+		if err = checkSourceRelease(tenant.Spec.Image, tag); err != nil {
+			return latest, digest, err
+		}
+	}
+
+	// verify the image before any of its files is served to MinIO
+	imgDigest, err := img.Digest()
+	if err != nil {
+		return latest, digest, err
+	}
+	if len(digests) == 0 || digests[len(digests)-1] != imgDigest {
+		digests = append(digests, imgDigest)
+	}
+	if d, ok := ref.(name.Digest); ok && !hasDigest(digests, d.DigestStr()) {
+		return latest, digest, fmt.Errorf("the upgrade source has no image with the digest of %s", tenant.Spec.Image)
+	}
+	if err = c.verifyImage(context.Background(), tenant, ref, digests, tag, keychain); err != nil {
+		return latest, digest, err
+	}
+	if tenant.Spec.ImageVerification != nil {
+		if d, ok := ref.(name.Digest); ok {
+			digest = d.DigestStr()
+		} else if !local {
+			// the digest the reference resolves to, the pools pull the image the binary was verified from. The digest
+			// of an image read from a volume may be of a manifest rebuilt when it was read, no registry serves it
+			digest = digests[0].String()
+		}
 	}
 
 	ls, err := img.Layers()
 	if err != nil {
-		return latest, err
+		return latest, digest, err
 	}
 
 	// Find the file with largest size among all layers.
@@ -166,24 +239,24 @@ func (c *Controller) fetchArtifacts(tenant *miniov2.Tenant) (latest string, err
 
 	f, err := os.OpenFile(basePath+"image.tar", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o777)
 	if err != nil {
-		return latest, err
+		return latest, digest, err
 	}
 
 	// Tarball writes a file called image.tar
 	// This file in turn has each container layer present inside in the form `<layer-hash>.tar.gz`
 	if err = tarball.Write(ref, img, f); err != nil {
 		f.Close()
-		return latest, err
+		return latest, digest, err
 	}
 
 	if err = f.Close(); err != nil {
-		return latest, err
+		return latest, digest, err
 	}
 
 	// Extract the <layer-hash>.tar.gz file that has minio contents from `image.tar`
 	fileNameToExtract := strings.Split(maxSizeHash.String(), ":")[1] + ".tar.gz"
 	if err = miniov2.ExtractTar([]string{fileNameToExtract}, basePath, "image.tar"); err != nil {
-		return latest, err
+		return latest, digest, err
 	}
 
 	latestAssets := []string{"opt/bin/minio", "opt/bin/minio.sha256sum", "opt/bin/minio.minisig"}
@@ -193,16 +266,211 @@ func (c *Controller) fetchArtifacts(tenant *miniov2.Tenant) (latest string, err
 	if err = miniov2.ExtractTar(latestAssets, basePath, fileNameToExtract); err != nil {
 		// attempt legacy if latest failed to extract artifacts
 		if err = miniov2.ExtractTar(legacyAssets, basePath, fileNameToExtract); err != nil {
-			return latest, err
+			return latest, digest, err
 		}
 	}
 
+	latest, err = publishArtifacts(basePath, tag)
+	return latest, digest, err
+}
+
+// keychainForImage returns the keychain to pull the image from its registry
+func (c *Controller) keychainForImage(ref name.Reference, tenant *miniov2.Tenant) authn.Keychain {
+	var err error
+	var keychain authn.Keychain
+	keychain = authn.DefaultKeychain
+
+	// if the tenant has imagePullSecret use that for pulling the image, but if we fail to extract the secret or we
+	// can't find the expected registry in the secret we will continue with the default keychain. This is because the
+	// needed pull secret could be attached to the service-account.
+	if tenant.Spec.ImagePullSecret.Name != "" {
+		// Get the secret
+		keychain, err = c.getKeychainForTenant(context.Background(), ref, tenant)
+		if err != nil {
+			klog.Info(err)
+		}
+	}
+	return keychain
+}
+
+// remoteImage pulls the image from its registry, along with the digest the reference resolves to, which is the digest
+// of the index of multi-platform images
+func remoteImage(ref name.Reference, keychain authn.Keychain) (v1.Image, []v1.Hash, error) {
+	desc, err := remote.Get(ref, remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return nil, nil, err
+	}
+	img, err := desc.Image()
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, []v1.Hash{desc.Digest}, nil
+}
+
+// layoutImage reads the image from an OCI image layout, along with the digests of the manifests leading to it. The image
+// is matched by its `org.opencontainers.image.ref.name` annotation or its digest, a layout with a single image is used
+// as is and its release is checked against the tag of the tenant image
+func layoutImage(layoutPath string, ref name.Reference) (v1.Image, []v1.Hash, error) {
+	index, err := layout.ImageIndexFromPath(layoutPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	img, digests, err := imageFromIndex(index, ref, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if img == nil {
+		return nil, nil, fmt.Errorf("image %s not found in the OCI image layout %s", ref, layoutPath)
+	}
+	return img, digests, nil
+}
+
+// imageFromIndex returns the image of the reference for the default platform of the registry source and the digests of
+// the manifests leading to it, nil if the index doesn't have it
+func imageFromIndex(index v1.ImageIndex, ref name.Reference, matchRef bool) (v1.Image, []v1.Hash, error) {
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, nil, err
+	}
+	candidates := manifest.Manifests
+	if matchRef {
+		candidates = nil
+		for _, desc := range manifest.Manifests {
+			if refName := desc.Annotations[imagespecv1.AnnotationRefName]; refName == ref.Name() || refName == ref.Identifier() || desc.Digest.String() == ref.Identifier() {
+				candidates = append(candidates, desc)
+			}
+		}
+		if len(candidates) == 0 && len(manifest.Manifests) == 1 {
+			candidates = manifest.Manifests
+		}
+	}
+	for _, desc := range candidates {
+		switch {
+		case desc.MediaType.IsImage():
+			// like remote.Image, pick the linux/amd64 image of multi-platform images
+			if desc.Platform == nil || desc.Platform.Satisfies(defaultPlatform) {
+				img, err := index.Image(desc.Digest)
+				return img, []v1.Hash{desc.Digest}, err
+			}
+		case desc.MediaType.IsIndex():
+			child, err := index.ImageIndex(desc.Digest)
+			if err != nil {
+				return nil, nil, err
+			}
+			if img, digests, err := imageFromIndex(child, ref, false); err != nil || img != nil {
+				return img, append([]v1.Hash{desc.Digest}, digests...), err
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// tarballImage reads the image from a tarball, the image is matched by its tag, a tarball with a single image is used
+// as is and its release is checked against the tag of the tenant image
+func tarballImage(tarballPath string, ref name.Reference) (v1.Image, error) {
+	if tag, ok := ref.(name.Tag); ok {
+		if img, err := tarball.ImageFromPath(tarballPath, &tag); err == nil {
+			return img, nil
+		}
+	}
+	return tarball.ImageFromPath(tarballPath, nil)
+}
+
+// upgradeSourcePath resolves the path of an upgrade source, which must be under the upgrade source root once its
+// symbolic links are followed so a tenant can't have the Operator read files from anywhere else in its pod
+func upgradeSourcePath(root, sourcePath string) (string, error) {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the upgrade source root %s: %w", root, err)
+	}
+	resolved, err := filepath.EvalSymlinks(sourcePath)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the upgrade source %s: %w", sourcePath, err)
+	}
+	rel, err := filepath.Rel(resolvedRoot, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("the upgrade source %s is not under %s", sourcePath, root)
+	}
+	return resolved, nil
+}
+
+// imageTag returns the tag of the image, empty if it has none
+func imageTag(image string) string {
+	image = strings.Split(image, "@")[0]
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// checkSourceRelease checks the release of the image or bundle read from an upgrade source is the tag of the tenant
+// image, nothing else ties the files of the source to the image the pools run
+func checkSourceRelease(image, release string) error {
+	if tag := imageTag(image); tag != release {
+		return fmt.Errorf("the upgrade source has release %s, the image %s must be tagged with it", release, image)
+	}
+	return nil
+}
+
+// hasDigest returns whether the digest is one of the digests
+func hasDigest(digests []v1.Hash, digest string) bool {
+	for _, d := range digests {
+		if d.String() == digest {
+			return true
+		}
+	}
+	return false
+}
+
+// copyBundle copies the minio, minio.sha256sum and minio.minisig files of the bundle to the base path and returns the
+// release found in minio.sha256sum
+func copyBundle(bundlePath, basePath string) (string, error) {
+	for _, file := range []string{"minio", "minio.sha256sum", "minio.minisig"} {
+		if err := copyFile(filepath.Join(bundlePath, file), filepath.Join(basePath, file)); err != nil {
+			return "", fmt.Errorf("unable to read the bundle %s: %w", bundlePath, err)
+		}
+	}
+	checksum, err := os.ReadFile(filepath.Join(basePath, "minio.sha256sum"))
+	if err != nil {
+		return "", err
+	}
+	// the checksum of a release names the binary after its release: `<sha256> minio.RELEASE.2024-03-15T01-07-19Z`
+	fields := strings.Fields(string(checksum))
+	if len(fields) < 2 || !strings.HasPrefix(fields[1], "minio.") {
+		return "", fmt.Errorf("unable to find the release of the bundle %s in minio.sha256sum", bundlePath)
+	}
+	return strings.TrimPrefix(fields[1], "minio."), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o777)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// publishArtifacts verifies the minio binary against its checksum and names the files after the release so the
+// operator serves them to the MinIO updater, MinIO checks the minisign signature itself
+func publishArtifacts(basePath, tag string) (string, error) {
 	srcBinary := "minio"
 	srcShaSum := "minio.sha256sum"
 	srcSig := "minio.minisig"
 
-	if _, err = miniov2.ReleaseTagToReleaseTime(tag); err != nil {
-		return latest, err
+	if _, err := miniov2.ReleaseTagToReleaseTime(tag); err != nil {
+		return "", err
+	}
+	if err := verifyChecksum(filepath.Join(basePath, srcBinary), filepath.Join(basePath, srcShaSum)); err != nil {
+		return "", err
 	}
 
 	destBinary := "minio." + tag
@@ -213,13 +481,38 @@ func (c *Controller) fetchArtifacts(tenant *miniov2.Tenant) (latest string, err
 	// rename all files to add tag specific values in the name.
 	// this is because minio updater looks for files in this name format.
 	for s, d := range filesToRename {
-		if err = os.Rename(filepath.Join(basePath, s), filepath.Join(basePath, d)); err != nil {
+		if err := os.Rename(filepath.Join(basePath, s), filepath.Join(basePath, d)); err != nil {
 			return tag, err
 		}
 	}
 	return tag, nil
 }
 
+// verifyChecksum checks the file matches the sha256 checksum of the checksum file
+func verifyChecksum(file, checksumFile string) error {
+	checksum, err := os.ReadFile(checksumFile)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(checksum))
+	if len(fields) == 0 {
+		return fmt.Errorf("%s is empty", filepath.Base(checksumFile))
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, fields[0]) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", filepath.Base(file), fields[0], sum)
+	}
+	return nil
+}
+
 // Remove all the files created during upload process
 func (c *Controller) removeArtifacts() error {
 	return os.RemoveAll(updatePath)
@@ -0,0 +1,326 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	imagespecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLayoutImage(t *testing.T) {
+	ref, err := name.ParseReference("quay.io/minio/minio:RELEASE.2024-03-15T01-07-19Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := img.Digest()
+
+	dir := t.TempDir()
+	path, err := layout.Write(dir, empty.Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = path.AppendImage(other, layout.WithAnnotations(map[string]string{imagespecv1.AnnotationRefName: "RELEASE.2024-02-26T09-33-48Z"})); err != nil {
+		t.Fatal(err)
+	}
+	if err = path.AppendImage(img, layout.WithAnnotations(map[string]string{imagespecv1.AnnotationRefName: "RELEASE.2024-03-15T01-07-19Z"})); err != nil {
+		t.Fatal(err)
+	}
+	found, digests, err := layoutImage(dir, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := found.Digest(); got != want {
+		t.Errorf("expected image %s, got %s", want, got)
+	}
+	if len(digests) != 1 || digests[0] != want {
+		t.Errorf("expected digests [%s], got %v", want, digests)
+	}
+
+	byDigest, err := name.ParseReference("quay.io/minio/minio@" + want.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found, _, err = layoutImage(dir, byDigest); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := found.Digest(); got != want {
+		t.Errorf("expected image %s by digest, got %s", want, got)
+	}
+
+	missing, err := name.ParseReference("quay.io/minio/minio:RELEASE.2023-01-01T00-00-00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = layoutImage(dir, missing); err == nil {
+		t.Error("expected an error for an image missing from the layout")
+	}
+}
+
+func TestTarballImage(t *testing.T) {
+	tag, err := name.NewTag("quay.io/minio/minio:RELEASE.2024-03-15T01-07-19Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := img.Digest()
+	path := filepath.Join(t.TempDir(), "minio.tar")
+	if err = tarball.WriteToFile(path, tag, img); err != nil {
+		t.Fatal(err)
+	}
+	// a tarball with a single image is used whatever its tag
+	for _, image := range []string{tag.String(), "registry.local/minio:RELEASE.2024-03-15T01-07-19Z"} {
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err := tarballImage(path, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := found.Digest(); got != want {
+			t.Errorf("%s: expected image %s, got %s", image, want, got)
+		}
+	}
+}
+
+func TestBundleArtifacts(t *testing.T) {
+	binary := []byte("minio binary")
+	sum := sha256.Sum256(binary)
+	writeBundle := func(t *testing.T, checksum string) string {
+		t.Helper()
+		dir := t.TempDir()
+		files := map[string]string{"minio": string(binary), "minio.sha256sum": checksum, "minio.minisig": "signature"}
+		for file, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+
+	t.Run("Valid bundle", func(t *testing.T) {
+		bundle := writeBundle(t, hex.EncodeToString(sum[:])+" minio.RELEASE.2024-03-15T01-07-19Z\n")
+		basePath := t.TempDir()
+		tag, err := copyBundle(bundle, basePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tag != "RELEASE.2024-03-15T01-07-19Z" {
+			t.Fatalf("expected the release of the bundle, got %s", tag)
+		}
+		if _, err = publishArtifacts(basePath, tag); err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range []string{"minio." + tag, "minio." + tag + ".sha256sum", "minio." + tag + ".minisig"} {
+			if _, err = os.Stat(filepath.Join(basePath, file)); err != nil {
+				t.Errorf("expected %s to be served: %v", file, err)
+			}
+		}
+	})
+
+	t.Run("Checksum mismatch", func(t *testing.T) {
+		other := sha256.Sum256([]byte("another binary"))
+		bundle := writeBundle(t, hex.EncodeToString(other[:])+" minio.RELEASE.2024-03-15T01-07-19Z\n")
+		basePath := t.TempDir()
+		tag, err := copyBundle(bundle, basePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = publishArtifacts(basePath, tag); err == nil {
+			t.Fatal("expected the checksum mismatch to be detected")
+		}
+	})
+
+	t.Run("Missing release", func(t *testing.T) {
+		bundle := writeBundle(t, hex.EncodeToString(sum[:])+"\n")
+		if _, err := copyBundle(bundle, t.TempDir()); err == nil {
+			t.Fatal("expected an error for a checksum without the release")
+		}
+	})
+}
+
+func TestUpgradeSourcePath(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "minio"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := upgradeSourcePath(root, filepath.Join(root, "minio")); err != nil {
+		t.Errorf("expected a source under the root to be read: %v", err)
+	}
+	for _, sourcePath := range []string{outside, filepath.Join(root, "link"), filepath.Join(root, "..", filepath.Base(outside))} {
+		if _, err := upgradeSourcePath(root, sourcePath); err == nil {
+			t.Errorf("expected %s to be rejected", sourcePath)
+		}
+	}
+}
+
+func TestCheckSourceRelease(t *testing.T) {
+	release := "RELEASE.2024-03-15T01-07-19Z"
+	testCases := []struct {
+		image   string
+		wantErr bool
+	}{
+		{image: "quay.io/minio/minio:" + release},
+		{image: "registry.local:5000/minio:" + release + "@sha256:4f9d0b0a4cd6a4a8b0e3c3b3f6a5e8f7d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7"},
+		{image: "quay.io/minio/minio:RELEASE.2024-02-26T09-33-48Z", wantErr: true},
+		{image: "quay.io/minio/minio:latest", wantErr: true},
+		{image: "registry.local:5000/minio", wantErr: true},
+	}
+	for _, tc := range testCases {
+		if err := checkSourceRelease(tc.image, release); (err != nil) != tc.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tc.image, tc.wantErr, err)
+		}
+	}
+}
+
+// minioImage builds an image of the release with the minio, minio.sha256sum and minio.minisig files laid out like the
+// MinIO images
+func minioImage(t *testing.T, release string) v1.Image {
+	t.Helper()
+	binary := []byte("minio binary")
+	sum := sha256.Sum256(binary)
+	files := map[string][]byte{
+		"opt/bin/minio":           binary,
+		"opt/bin/minio.sha256sum": []byte(hex.EncodeToString(sum[:]) + " minio." + release + "\n"),
+		"opt/bin/minio.minisig":   []byte("signature"),
+	}
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for file, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: file, Typeflag: tar.TypeReg, Mode: 0o755, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err = mutate.Config(img, v1.Config{Labels: map[string]string{"release": release}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestFetchArtifactsFromTarball(t *testing.T) {
+	release := "RELEASE.2024-03-15T01-07-19Z"
+	tag, err := name.NewTag("registry.local/minio/minio:" + release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	t.Setenv(UpgradeSourceRootEnv, root)
+	tarballPath := filepath.Join(root, "minio.tar")
+	if err = tarball.WriteToFile(tarballPath, tag, minioImage(t, release)); err != nil {
+		t.Fatal(err)
+	}
+	// the digest of the image as read from the tarball
+	img, err := tarballImage(tarballPath, tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imgDigest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+		Spec: miniov2.TenantSpec{
+			Image:             tag.String(),
+			UpgradeSource:     &miniov2.UpgradeSource{Type: miniov2.UpgradeSourceImageTarball, Path: tarballPath},
+			ImageVerification: &miniov2.ImageVerification{AllowedDigests: []string{imgDigest.String()}, RequireMatchingRelease: true},
+		},
+	}
+	c := &Controller{}
+	defer c.removeArtifacts()
+	latest, digest, err := c.fetchArtifacts(tenant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != release {
+		t.Errorf("expected release %s, got %s", release, latest)
+	}
+	if _, err = os.Stat(filepath.Join(updatePath, "minio."+release)); err != nil {
+		t.Errorf("expected the binary to be served: %v", err)
+	}
+	// no registry is guaranteed to hold the digest of an image read from a tarball, the pools aren't pinned to it
+	if digest != "" {
+		t.Errorf("expected no digest to pin the pools to, got %s", digest)
+	}
+	tenant.Status.UpgradeHistory = []miniov2.UpgradeRecord{{ToImage: tenant.Spec.Image, ToVersion: latest, Digest: digest, Result: miniov2.UpgradeSucceeded}}
+	if image := pinnedImage(tenant); image != tenant.Spec.Image {
+		t.Errorf("expected the pools to run %s, got %s", tenant.Spec.Image, image)
+	}
+
+	// a digest in the image of the tenant is kept, once checked against the tarball
+	tenant.Spec.Image = tag.String() + "@" + imgDigest.String()
+	if _, digest, err = c.fetchArtifacts(tenant); err != nil {
+		t.Fatal(err)
+	}
+	if digest != imgDigest.String() {
+		t.Errorf("expected digest %s, got %s", imgDigest, digest)
+	}
+	tenant.Spec.Image = tag.String() + "@sha256:" + hex.EncodeToString(make([]byte, 32))
+	if _, _, err = c.fetchArtifacts(tenant); err == nil {
+		t.Error("expected an error for a digest the tarball doesn't have")
+	}
+}
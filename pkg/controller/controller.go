@@ -74,23 +74,8 @@ func init() {
 	flag.BoolVar(&checkVersion, "version", false, "print version")
 }
 
-// StartOperator starts the MinIO Operator controller
-func StartOperator(kubeconfig string) {
-	_ = v2.AddToScheme(scheme.Scheme)
-	_ = v1alpha1.AddToScheme(scheme.Scheme)
-	_ = stsv1beta1.AddToScheme(scheme.Scheme)
-	_ = stsv1alpha1.AddToScheme(scheme.Scheme)
-	klog.Info("Starting MinIO Operator")
-	// set up signals, so we handle the first shutdown signal gracefully
-	stopCh := setupSignalHandler()
-
-	flag.Parse()
-
-	if checkVersion {
-		fmt.Println(pkg.Version)
-		return
-	}
-
+// buildRestConfig returns the config to reach the Kubernetes API from the cluster, or from the kubeconfig if set
+func buildRestConfig(kubeconfig string) (*rest.Config, error) {
 	var cfg *rest.Config
 	var err error
 
@@ -110,6 +95,27 @@ func StartOperator(kubeconfig string) {
 	if kubeconfig != "" {
 		cfg, err = clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
 	}
+	return cfg, err
+}
+
+// StartOperator starts the MinIO Operator controller
+func StartOperator(kubeconfig string) {
+	_ = v2.AddToScheme(scheme.Scheme)
+	_ = v1alpha1.AddToScheme(scheme.Scheme)
+	_ = stsv1beta1.AddToScheme(scheme.Scheme)
+	_ = stsv1alpha1.AddToScheme(scheme.Scheme)
+	klog.Info("Starting MinIO Operator")
+	// set up signals, so we handle the first shutdown signal gracefully
+	stopCh := setupSignalHandler()
+
+	flag.Parse()
+
+	if checkVersion {
+		fmt.Println(pkg.Version)
+		return
+	}
+
+	cfg, err := buildRestConfig(kubeconfig)
 	if err != nil {
 		klog.Fatalf("Error building kubeconfig: %s", err.Error())
 	}
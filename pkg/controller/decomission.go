@@ -18,7 +18,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/minio/madmin-go/v3"
 	"github.com/minio/minio-go/v7/pkg/set"
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 	corev1 "k8s.io/api/core/v1"
@@ -27,6 +30,9 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// decommissionCheckInterval is how often the progress MinIO reports for the decommission of a pool is checked
+const decommissionCheckInterval = 30 * time.Second
+
 // checkForPoolDecommission validates the spec of the tenant and it's status to detect a pool being removed
 func (c *Controller) checkForPoolDecommission(ctx context.Context, key string, tenant *miniov2.Tenant, tenantConfiguration map[string][]byte) (*miniov2.Tenant, error) {
 	var err error
@@ -49,8 +55,39 @@ func (c *Controller) checkForPoolDecommission(ctx context.Context, key string, t
 		}
 	}
 
-	// if the number of pools in the spec is less that what we know in the status, a decomission is taking place
-	if len(tenant.Status.Pools) > len(tenant.Spec.Pools) {
+	// a pool being replaced was removed from the spec, decommission it like any other removed pool
+	var replacementDropped bool
+	for i := range tenant.Status.Pools {
+		pstatus := &tenant.Status.Pools[i]
+		if pstatus.Replacement == nil || pstatus.State == miniov2.PoolDecommissioned {
+			continue
+		}
+		if _, ok := specPoolForStatus(tenant, miniov2.PoolStatus{SSName: pstatus.SSName}); !ok {
+			klog.Infof("%s pool %s was removed while being replaced", key, pstatus.SSName)
+			pstatus.Replacement = nil
+			replacementDropped = true
+		}
+	}
+	if replacementDropped {
+		if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
+			return nil, err
+		}
+	}
+
+	// a pool being decommissioned was added back to the spec, cancel the decommission
+	if tenant, err = c.checkForPoolDecommissionCancel(ctx, key, tenant, tenantConfiguration); err != nil {
+		return nil, err
+	}
+
+	// if the number of pools in the spec is less that what we know in the status, a decomission is taking place.
+	// Pools being replaced are decommissioned by checkForPoolReplacement.
+	var deployedPools int
+	for _, pstatus := range tenant.Status.Pools {
+		if pstatus.Replacement == nil {
+			deployedPools++
+		}
+	}
+	if deployedPools > len(tenant.Spec.Pools) {
 		err := c.DeletePDB(ctx, tenant)
 		if err != nil {
 			return nil, err
@@ -87,26 +124,41 @@ func (c *Controller) checkForPoolDecommission(ctx context.Context, key string, t
 		}
 
 		klog.Infof("%s Detected we are removing a pool", key)
-		// This means we are attempting to remove a "pool", perhaps after a decommission event.
+		// This means we are attempting to remove a "pool", MinIO has to move its data out of it first.
 		var poolNamesRemoved []string
+		for _, pstatus := range tenant.Status.Pools {
+			if pstatus.Replacement != nil {
+				continue
+			}
+			if _, ok := specPoolForStatus(tenant, pstatus); !ok {
+				poolNamesRemoved = append(poolNamesRemoved, pstatus.SSName)
+			}
+		}
+
+		// Keep the removed pools around until MinIO reports their decommission is complete
+		var decommissioned bool
+		if tenant, decommissioned, err = c.decommissionPools(ctx, key, tenant, poolNamesRemoved, tenantConfiguration); err != nil {
+			return nil, err
+		}
+		if !decommissioned {
+			return nil, ErrPoolDecommissioning
+		}
+
 		var initializedPool miniov2.Pool
 		var poolStatus []miniov2.PoolStatus
 		for _, pstatus := range tenant.Status.Pools {
-			var found bool
-			for _, pool := range tenant.Spec.Pools {
-				if pstatus.SSName == tenant.PoolStatefulsetName(&pool) {
-					found = true
-					if pstatus.State == miniov2.PoolInitialized {
-						initializedPool = pool
-					}
-					break
-				}
+			if pstatus.Replacement != nil {
+				poolStatus = append(poolStatus, *pstatus.DeepCopy())
+				continue
 			}
+			pool, found := specPoolForStatus(tenant, pstatus)
 			if !found {
-				poolNamesRemoved = append(poolNamesRemoved, pstatus.SSName)
-			} else {
-				poolStatus = append(poolStatus, *pstatus.DeepCopy())
+				continue
+			}
+			if pstatus.State == miniov2.PoolInitialized {
+				initializedPool = pool
 			}
+			poolStatus = append(poolStatus, *pstatus.DeepCopy())
 		}
 		tenant.Status.Pools = poolStatus
 
@@ -146,3 +198,203 @@ func (c *Controller) checkForPoolDecommission(ctx context.Context, key string, t
 	}
 	return tenant, err
 }
+
+// decommissionPools starts the decommission of the removed pools and tracks their progress in the pool status,
+// it returns true once MinIO finished moving the data out of all of them.
+func (c *Controller) decommissionPools(ctx context.Context, key string, tenant *miniov2.Tenant, ssNames []string, tenantConfiguration map[string][]byte) (*miniov2.Tenant, bool, error) {
+	removed := set.CreateStringSet(ssNames...)
+	pending := false
+	for _, pstatus := range tenant.Status.Pools {
+		if removed.Contains(pstatus.SSName) && pstatus.State != miniov2.PoolDecommissioned {
+			pending = true
+			break
+		}
+	}
+	if !pending {
+		return tenant, true, nil
+	}
+
+	adminClnt, err := tenant.NewMinIOAdmin(tenantConfiguration, c.getTransport())
+	if err != nil {
+		return nil, false, err
+	}
+	minioPools, err := adminClnt.ListPoolsStatus(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	completed := true
+	failed := false
+	for i := range tenant.Status.Pools {
+		pstatus := &tenant.Status.Pools[i]
+		if !removed.Contains(pstatus.SSName) || pstatus.State == miniov2.PoolDecommissioned {
+			continue
+		}
+		minioPool, ok := minioPoolForStatefulSet(minioPools, pstatus.SSName)
+		if !ok {
+			// MinIO is no longer running this pool, there is nothing left to move
+			klog.Infof("%s pool %s is not part of the MinIO deployment anymore", key, pstatus.SSName)
+			pstatus.State = miniov2.PoolDecommissioned
+			continue
+		}
+		info := minioPool.Decommission
+		// start the decommission unless it was started outside the operator
+		if info == nil || info.Canceled {
+			klog.Infof("%s Starting decommission of pool %s", key, pstatus.SSName)
+			if err = adminClnt.DecommissionPool(ctx, minioPool.CmdLine); err != nil {
+				return nil, false, err
+			}
+			c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolDecommissionStarted", fmt.Sprintf("Tenant pool %s decommission started", pstatus.SSName))
+			if minioPool, err = adminClnt.StatusPool(ctx, minioPool.CmdLine); err != nil {
+				return nil, false, err
+			}
+			info = minioPool.Decommission
+		}
+		if pstatus.Decommission == nil {
+			pstatus.Decommission = &miniov2.PoolDecommissionStatus{}
+		}
+		wasFailed := pstatus.Decommission.Failed
+		pstatus.State = miniov2.PoolDecommissioning
+		pstatus.Decommission.CmdLine = minioPool.CmdLine
+		updatePoolDecommissionStatus(pstatus.Decommission, info, now)
+
+		switch {
+		case pstatus.Decommission.Complete:
+			c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolDecommissioned", fmt.Sprintf("Tenant pool %s decommission completed", pstatus.SSName))
+			pstatus.State = miniov2.PoolDecommissioned
+			// do not remove the pool in this sync, let MinIO pick up the arguments without it first
+			completed = false
+		case pstatus.Decommission.Failed:
+			if !wasFailed {
+				c.recorder.Event(tenant, corev1.EventTypeWarning, "PoolDecommissionFailed", fmt.Sprintf("Tenant pool %s decommission failed", pstatus.SSName))
+			}
+			failed = true
+			completed = false
+		default:
+			completed = false
+		}
+	}
+
+	tenant.Status.CurrentState = StatusDecommissioningPool
+	if failed {
+		tenant.Status.CurrentState = StatusDecommissioningFailed
+	}
+	if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
+		return nil, false, err
+	}
+	return tenant, completed, nil
+}
+
+// checkForPoolDecommissionCancel cancels the decommission of any pool that was added back to the spec
+func (c *Controller) checkForPoolDecommissionCancel(ctx context.Context, key string, tenant *miniov2.Tenant, tenantConfiguration map[string][]byte) (*miniov2.Tenant, error) {
+	var canceled bool
+	var adminClnt *madmin.AdminClient
+	var err error
+	for i := range tenant.Status.Pools {
+		pstatus := &tenant.Status.Pools[i]
+		if pstatus.State != miniov2.PoolDecommissioning && pstatus.State != miniov2.PoolDecommissioned {
+			continue
+		}
+		if _, ok := specPoolForStatus(tenant, *pstatus); !ok {
+			continue
+		}
+		if pstatus.State == miniov2.PoolDecommissioned {
+			klog.Warningf("%s pool %s was already decommissioned and can't be added back", key, pstatus.SSName)
+			c.recorder.Event(tenant, corev1.EventTypeWarning, "PoolDecommissionCancelFailed", fmt.Sprintf("Tenant pool %s was already decommissioned and can't be added back", pstatus.SSName))
+			return nil, fmt.Errorf("pool %s was already decommissioned", pstatus.SSName)
+		}
+		if adminClnt == nil {
+			if adminClnt, err = tenant.NewMinIOAdmin(tenantConfiguration, c.getTransport()); err != nil {
+				return nil, err
+			}
+		}
+		if pstatus.Decommission != nil && pstatus.Decommission.CmdLine != "" {
+			klog.Infof("%s Canceling decommission of pool %s", key, pstatus.SSName)
+			if err = adminClnt.CancelDecommissionPool(ctx, pstatus.Decommission.CmdLine); err != nil {
+				return nil, err
+			}
+		}
+		c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolDecommissionCanceled", fmt.Sprintf("Tenant pool %s decommission canceled", pstatus.SSName))
+		pstatus.State = miniov2.PoolInitialized
+		pstatus.Decommission = nil
+		canceled = true
+	}
+	if !canceled {
+		return tenant, nil
+	}
+	return c.updatePoolStatus(ctx, tenant)
+}
+
+// specPoolForStatus returns the pool in the tenant spec the pool status belongs to, a pool being replaced
+// doesn't belong to the spec anymore
+func specPoolForStatus(tenant *miniov2.Tenant, pstatus miniov2.PoolStatus) (miniov2.Pool, bool) {
+	if pstatus.Replacement != nil {
+		return miniov2.Pool{}, false
+	}
+	for _, pool := range tenant.Spec.Pools {
+		if tenant.IsPoolStatefulset(&pool, pstatus.SSName) {
+			return pool, true
+		}
+	}
+	return miniov2.Pool{}, false
+}
+
+// minioPoolForStatefulSet finds the pool served by the given statefulset among the pools reported by MinIO
+func minioPoolForStatefulSet(pools []madmin.PoolStatus, ssName string) (madmin.PoolStatus, bool) {
+	for _, pool := range pools {
+		// pool arguments look like https://<ssName>-{0...3}.<hl-service>... or https://<ssName>-0.<hl-service>...,
+		// the replacements of a pool are named <ssName>-r<n>
+		if _, host, ok := strings.Cut(pool.CmdLine, "://"+ssName+"-"); ok && isServerOrdinals(host) {
+			return pool, true
+		}
+	}
+	return madmin.PoolStatus{}, false
+}
+
+// isServerOrdinals returns whether the host starts with the ordinals of the servers of a pool followed by the domain,
+// either `{0...3}.` or `0.`, so a pool isn't mistaken for another one whose name it prefixes, like pool-1 and pool-1-0
+func isServerOrdinals(host string) bool {
+	digits := func(s string) bool {
+		return s != "" && strings.Trim(s, "0123456789") == ""
+	}
+	if ellipsis, found := strings.CutPrefix(host, "{"); found {
+		ordinals, domain, ok := strings.Cut(ellipsis, "}")
+		first, last, isRange := strings.Cut(ordinals, "...")
+		return ok && isRange && digits(first) && digits(last) && strings.HasPrefix(domain, ".")
+	}
+	ordinal, _, ok := strings.Cut(host, ".")
+	return ok && digits(ordinal)
+}
+
+// updatePoolDecommissionStatus copies the decommission progress reported by MinIO and estimates its completion time
+func updatePoolDecommissionStatus(status *miniov2.PoolDecommissionStatus, info *madmin.PoolDecommissionInfo, now time.Time) {
+	lastUpdate := metav1.NewTime(now)
+	status.LastUpdate = &lastUpdate
+	if info == nil {
+		return
+	}
+	if !info.StartTime.IsZero() {
+		startTime := metav1.NewTime(info.StartTime)
+		status.StartTime = &startTime
+	}
+	status.TotalSize = info.TotalSize
+	status.CurrentSize = info.CurrentSize
+	status.BytesDone = info.BytesDone
+	status.BytesFailed = info.BytesFailed
+	status.ObjectsDone = info.ObjectsDecommissioned
+	status.ObjectsFailed = info.ObjectsDecommissionFailed
+	status.Complete = info.Complete
+	status.Failed = info.Failed
+	status.ETA = nil
+
+	// sizes are free space, the pool is drained once the free space matches its capacity
+	moved := info.CurrentSize - info.StartSize
+	remaining := info.TotalSize - info.CurrentSize
+	elapsed := now.Sub(info.StartTime)
+	if info.Complete || info.Failed || info.StartTime.IsZero() || moved <= 0 || remaining < 0 || elapsed <= 0 {
+		return
+	}
+	eta := metav1.NewTime(now.Add(time.Duration(float64(elapsed) * float64(remaining) / float64(moved))))
+	status.ETA = &eta
+}
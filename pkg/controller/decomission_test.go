@@ -0,0 +1,130 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+)
+
+func Test_minioPoolForStatefulSet(t *testing.T) {
+	pools := []madmin.PoolStatus{
+		{ID: 0, CmdLine: "https://myminio-pool-0-{0...3}.myminio-hl.ns.svc.cluster.local/export{0...3}"},
+		{ID: 1, CmdLine: "https://myminio-pool-10-{0...3}.myminio-hl.ns.svc.cluster.local/export{0...3}"},
+		{ID: 2, CmdLine: "https://myminio-pool-1-{0...3}.myminio-hl.ns.svc.cluster.local/export{0...3}"},
+		{ID: 3, CmdLine: "https://myminio-pool-0-r1-{0...7}.myminio-hl.ns.svc.cluster.local/export{0...3}"},
+		{ID: 4, CmdLine: "https://myminio-pool-3-0.myminio-hl.ns.svc.cluster.local/export{0...3}"},
+		{ID: 5, CmdLine: "https://myminio-pool-5-0-{0...3}.myminio-hl.ns.svc.cluster.local/export{0...3}"},
+		{ID: 6, CmdLine: "https://myminio-pool-6-1-0.myminio-hl.ns.svc.cluster.local/export{0...3}"},
+	}
+	tests := []struct {
+		name   string
+		ssName string
+		wantID int
+		wantOk bool
+	}{
+		{
+			name:   "first pool",
+			ssName: "myminio-pool-0",
+			wantID: 0,
+			wantOk: true,
+		},
+		{
+			name:   "prefix of another pool name",
+			ssName: "myminio-pool-1",
+			wantID: 2,
+			wantOk: true,
+		},
+		{
+			name:   "replacement of a pool",
+			ssName: "myminio-pool-0-r1",
+			wantID: 3,
+			wantOk: true,
+		},
+		{
+			name:   "single server pool",
+			ssName: "myminio-pool-3",
+			wantID: 4,
+			wantOk: true,
+		},
+		{
+			name:   "prefix of a pool name ending with a number",
+			ssName: "myminio-pool-5",
+			wantOk: false,
+		},
+		{
+			name:   "pool name ending with a number",
+			ssName: "myminio-pool-5-0",
+			wantID: 5,
+			wantOk: true,
+		},
+		{
+			name:   "prefix of a single server pool name ending with a number",
+			ssName: "myminio-pool-6",
+			wantOk: false,
+		},
+		{
+			name:   "unknown pool",
+			ssName: "myminio-pool-2",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := minioPoolForStatefulSet(pools, tt.ssName)
+			if ok != tt.wantOk {
+				t.Fatalf("minioPoolForStatefulSet() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && got.ID != tt.wantID {
+				t.Errorf("minioPoolForStatefulSet() got pool %d, want %d", got.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_updatePoolDecommissionStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	info := &madmin.PoolDecommissionInfo{
+		StartTime:                 now.Add(-time.Hour),
+		StartSize:                 100,
+		TotalSize:                 1000,
+		CurrentSize:               550,
+		BytesDone:                 450,
+		ObjectsDecommissioned:     45,
+		ObjectsDecommissionFailed: 1,
+	}
+
+	status := &miniov2.PoolDecommissionStatus{}
+	updatePoolDecommissionStatus(status, info, now)
+	if status.BytesDone != 450 || status.ObjectsDone != 45 || status.ObjectsFailed != 1 {
+		t.Errorf("updatePoolDecommissionStatus() progress not copied: %+v", status)
+	}
+	if status.ETA == nil {
+		t.Fatalf("updatePoolDecommissionStatus() expected an ETA")
+	}
+	// 450 bytes moved in an hour, 450 bytes remaining
+	if want := now.Add(time.Hour); !status.ETA.Time.Equal(want) {
+		t.Errorf("updatePoolDecommissionStatus() ETA = %v, want %v", status.ETA.Time, want)
+	}
+
+	info.Complete = true
+	updatePoolDecommissionStatus(status, info, now)
+	if !status.Complete || status.ETA != nil {
+		t.Errorf("updatePoolDecommissionStatus() expected complete without ETA: %+v", status)
+	}
+}
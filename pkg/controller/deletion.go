@@ -0,0 +1,320 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	clientset "github.com/minio/operator/pkg/client/clientset/versioned"
+	"github.com/minio/operator/pkg/controller/certificates"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// tenantCleanup tells what was deleted and what was retained of a deleted tenant
+type tenantCleanup struct {
+	deleted  []string
+	retained []string
+}
+
+func (t *tenantCleanup) String() string {
+	var parts []string
+	if len(t.deleted) > 0 {
+		parts = append(parts, "deleted "+strings.Join(t.deleted, ", "))
+	}
+	if len(t.retained) > 0 {
+		parts = append(parts, "retained "+strings.Join(t.retained, ", "))
+	}
+	if len(parts) == 0 {
+		return "nothing to clean up"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (t *tenantCleanup) add(deleted bool, what string) {
+	if deleted {
+		t.deleted = append(t.deleted, what)
+	} else {
+		t.retained = append(t.retained, what)
+	}
+}
+
+// tenantDeletionPolicy returns the deletion policy of the tenant, `Retain` by default
+func tenantDeletionPolicy(tenant *miniov2.Tenant) miniov2.DeletionPolicy {
+	if tenant.Spec.DeletionPolicy == "" {
+		return miniov2.DeletionPolicyRetain
+	}
+	return tenant.Spec.DeletionPolicy
+}
+
+// ensureTenantFinalizer sets the finalizer on the tenant, so it's cleaned up according to its deletion policy
+func (c *Controller) ensureTenantFinalizer(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	if controllerutil.ContainsFinalizer(tenant, miniov2.TenantFinalizer) {
+		return tenant, nil
+	}
+	return patchTenantFinalizers(ctx, c.minioClientSet, tenant, append(tenant.Finalizers, miniov2.TenantFinalizer))
+}
+
+// withoutTenantFinalizer returns the finalizers but the one of the Operator
+func withoutTenantFinalizer(finalizers []string) []string {
+	var kept []string
+	for _, finalizer := range finalizers {
+		if finalizer != miniov2.TenantFinalizer {
+			kept = append(kept, finalizer)
+		}
+	}
+	return kept
+}
+
+// patchTenantFinalizers sets the finalizers of the tenant, leaving its spec untouched as it may hold defaults by now
+func patchTenantFinalizers(ctx context.Context, minioClientSet clientset.Interface, tenant *miniov2.Tenant, finalizers []string) (*miniov2.Tenant, error) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": tenant.ResourceVersion,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return minioClientSet.MinioV2().Tenants(tenant.Namespace).Patch(ctx, tenant.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+// syncTenantDeletion cleans up after a deleted tenant according to its deletion policy, then removes the finalizer so
+// the tenant and the resources it owns are garbage collected
+func (c *Controller) syncTenantDeletion(ctx context.Context, tenant *miniov2.Tenant) (Result, error) {
+	if !controllerutil.ContainsFinalizer(tenant, miniov2.TenantFinalizer) {
+		return WrapResult(Result{}, nil)
+	}
+	policy := tenantDeletionPolicy(tenant)
+	klog.Infof("Tenant '%s/%s' deleted, cleaning up with deletion policy %s", tenant.Namespace, tenant.Name, policy)
+	tenant, err := c.updateTenantStatus(ctx, tenant, StatusDeletingTenant, tenant.Status.AvailableReplicas)
+	if err != nil {
+		return WrapResult(Result{}, err)
+	}
+	cleanup := &tenantCleanup{}
+	if err = c.cleanupTenant(ctx, tenant, policy, cleanup); err != nil {
+		c.recorder.Eventf(tenant, corev1.EventTypeWarning, "CleanupFailed", "Tenant cleanup failed, %s so far: %v", cleanup, err)
+		return WrapResult(Result{}, err)
+	}
+	c.recorder.Eventf(tenant, corev1.EventTypeNormal, "CleanedUp", "Tenant cleaned up with deletion policy %s: %s", policy, cleanup)
+	if _, err = patchTenantFinalizers(ctx, c.minioClientSet, tenant, withoutTenantFinalizer(tenant.Finalizers)); err != nil && !k8serrors.IsNotFound(err) {
+		return WrapResult(Result{}, err)
+	}
+	return WrapResult(Result{}, nil)
+}
+
+// RemoveTenantFinalizers stops the Operator and removes its finalizer from all the tenants, so they can still be
+// deleted once the Operator is uninstalled. The tenants keep running, they are only no longer cleaned up on deletion
+func RemoveTenantFinalizers(kubeconfig string) error {
+	cfg, err := buildRestConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("unable to build the kubeconfig: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	minioClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	return removeTenantFinalizers(context.Background(), kubeClient, minioClient, miniov2.GetNSFromFile(), 2*time.Minute)
+}
+
+func removeTenantFinalizers(ctx context.Context, kubeClient kubernetes.Interface, minioClient clientset.Interface, namespace string, timeout time.Duration) error {
+	// a running Operator would set the finalizer again as soon as it's removed
+	deployments := kubeClient.AppsV1().Deployments(namespace)
+	name := getOperatorDeploymentName()
+	if _, err := deployments.Patch(ctx, name, types.MergePatchType, []byte(`{"spec":{"replicas":0}}`), metav1.PatchOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("unable to stop the Operator: %w", err)
+	}
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		deployment, err := deployments.Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return deployment.Status.Replicas == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the Operator didn't stop: %w", err)
+	}
+
+	tenants, err := minioClient.MinioV2().Tenants("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range tenants.Items {
+		tenant := &tenants.Items[i]
+		if !controllerutil.ContainsFinalizer(tenant, miniov2.TenantFinalizer) {
+			continue
+		}
+		if _, err = patchTenantFinalizers(ctx, minioClient, tenant, withoutTenantFinalizer(tenant.Finalizers)); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("unable to remove the finalizer of tenant %s/%s: %w", tenant.Namespace, tenant.Name, err)
+		}
+		klog.Infof("Removed the finalizer of tenant '%s/%s'", tenant.Namespace, tenant.Name)
+	}
+	return nil
+}
+
+// cleanupTenant deletes or retains the resources of the tenant the deletion policy governs
+func (c *Controller) cleanupTenant(ctx context.Context, tenant *miniov2.Tenant, policy miniov2.DeletionPolicy, cleanup *tenantCleanup) error {
+	if err := c.cleanupTenantPVCs(ctx, tenant, policy, cleanup); err != nil {
+		return fmt.Errorf("unable to clean up the PVCs: %w", err)
+	}
+	if err := c.cleanupTenantTLSSecrets(ctx, tenant, policy, cleanup); err != nil {
+		return fmt.Errorf("unable to clean up the TLS secrets: %w", err)
+	}
+	if err := c.deleteTenantCSRs(ctx, tenant, cleanup); err != nil {
+		return fmt.Errorf("unable to delete the certificate signing requests: %w", err)
+	}
+	if tenant.Spec.PrometheusOperator {
+		if err := c.deletePrometheusAddlConfig(ctx, tenant); err != nil {
+			return fmt.Errorf("unable to delete the Prometheus scrape config: %w", err)
+		}
+		cleanup.deleted = append(cleanup.deleted, "the Prometheus scrape config")
+	}
+	if available := c.GetPDBAvailable(); available.Available() {
+		if err := c.DeletePDB(ctx, tenant); err != nil {
+			return fmt.Errorf("unable to delete the PodDisruptionBudgets: %w", err)
+		}
+		cleanup.deleted = append(cleanup.deleted, "the PodDisruptionBudgets")
+	}
+	return nil
+}
+
+// cleanupTenantPVCs deletes the PVCs of the tenant, or keeps them and labels them with the name of the tenant
+func (c *Controller) cleanupTenantPVCs(ctx context.Context, tenant *miniov2.Tenant, policy miniov2.DeletionPolicy, cleanup *tenantCleanup) error {
+	pvcs, err := c.kubeClientSet.CoreV1().PersistentVolumeClaims(tenant.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", miniov2.TenantLabel, tenant.Name),
+	})
+	if err != nil {
+		return err
+	}
+	count := 0
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		switch policy {
+		case miniov2.DeletionPolicyDelete:
+			err = c.kubeClientSet.CoreV1().PersistentVolumeClaims(tenant.Namespace).Delete(ctx, pvc.Name, metav1.DeleteOptions{})
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+		case miniov2.DeletionPolicyRetainAndLabel:
+			if pvc.Labels[miniov2.RetainedFromLabel] == tenant.Name {
+				break
+			}
+			pvc.Labels[miniov2.RetainedFromLabel] = tenant.Name
+			_, err = c.kubeClientSet.CoreV1().PersistentVolumeClaims(tenant.Namespace).Update(ctx, pvc, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if count > 0 {
+		cleanup.add(policy == miniov2.DeletionPolicyDelete, fmt.Sprintf("%d PVCs", count))
+	}
+	return nil
+}
+
+// cleanupTenantTLSSecrets deletes the TLS certificate secrets the Operator generated for the tenant, or keeps them by
+// releasing them from the tenant, so they are not garbage collected along with it
+func (c *Controller) cleanupTenantTLSSecrets(ctx context.Context, tenant *miniov2.Tenant, policy miniov2.DeletionPolicy, cleanup *tenantCleanup) error {
+	count := 0
+	for _, secretName := range []string{tenant.MinIOTLSSecretName(), tenant.MinIOClientTLSSecretName(), tenant.KESTLSSecretName()} {
+		secret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		// only the secrets generated by the Operator are owned by the tenant
+		if !metav1.IsControlledBy(secret, tenant) {
+			continue
+		}
+		if policy == miniov2.DeletionPolicyDelete {
+			err = c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+		} else {
+			var ownerReferences []metav1.OwnerReference
+			for _, ownerReference := range secret.OwnerReferences {
+				if ownerReference.UID != tenant.UID {
+					ownerReferences = append(ownerReferences, ownerReference)
+				}
+			}
+			secret.OwnerReferences = ownerReferences
+			if policy == miniov2.DeletionPolicyRetainAndLabel {
+				if secret.Labels == nil {
+					secret.Labels = map[string]string{}
+				}
+				secret.Labels[miniov2.RetainedFromLabel] = tenant.Name
+			}
+			_, err = c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if count > 0 {
+		cleanup.add(policy == miniov2.DeletionPolicyDelete, fmt.Sprintf("%d TLS secrets", count))
+	}
+	return nil
+}
+
+// deleteTenantCSRs deletes the certificate signing requests of the tenant, they are cluster scoped so they are not
+// garbage collected along with it
+func (c *Controller) deleteTenantCSRs(ctx context.Context, tenant *miniov2.Tenant, cleanup *tenantCleanup) error {
+	count := 0
+	for _, csrName := range []string{tenant.MinIOCSRName(), tenant.MinIOClientCSRName(), tenant.KESCSRName()} {
+		var err error
+		if certificates.GetCertificatesAPIVersion(c.kubeClientSet) == certificates.CSRV1 {
+			err = c.kubeClientSet.CertificatesV1().CertificateSigningRequests().Delete(ctx, csrName, metav1.DeleteOptions{})
+		} else {
+			err = c.kubeClientSet.CertificatesV1beta1().CertificateSigningRequests().Delete(ctx, csrName, metav1.DeleteOptions{})
+		}
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if count > 0 {
+		cleanup.add(true, fmt.Sprintf("%d CSRs", count))
+	}
+	return nil
+}
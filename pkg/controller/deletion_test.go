@@ -0,0 +1,153 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	miniofake "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	appsv1 "k8s.io/api/apps/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSyncTenantDeletion(t *testing.T) {
+	ctx := context.Background()
+	for _, policy := range []miniov2.DeletionPolicy{"", miniov2.DeletionPolicyRetainAndLabel, miniov2.DeletionPolicyDelete} {
+		name := string(policy)
+		if name == "" {
+			name = "default"
+		}
+		t.Run(name, func(t *testing.T) {
+			now := metav1.Now()
+			tenant := &miniov2.Tenant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "myminio",
+					Namespace:         "tenant-ns",
+					UID:               "tenant-uid",
+					Finalizers:        []string{miniov2.TenantFinalizer},
+					DeletionTimestamp: &now,
+				},
+				Spec: miniov2.TenantSpec{DeletionPolicy: policy},
+			}
+			ownerRef := *metav1.NewControllerRef(tenant, schema.GroupVersionKind{
+				Group:   miniov2.SchemeGroupVersion.Group,
+				Version: miniov2.SchemeGroupVersion.Version,
+				Kind:    miniov2.MinIOCRDResourceKind,
+			})
+			kubeClientSet := fake.NewSimpleClientset(
+				&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data0-myminio-pool-0-0", Namespace: "tenant-ns", Labels: map[string]string{miniov2.TenantLabel: "myminio"}}},
+				&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data0-other-pool-0-0", Namespace: "tenant-ns", Labels: map[string]string{miniov2.TenantLabel: "other"}}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: tenant.MinIOTLSSecretName(), Namespace: "tenant-ns", OwnerReferences: []metav1.OwnerReference{ownerRef}}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: tenant.KESTLSSecretName(), Namespace: "tenant-ns"}},
+				&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: tenant.MinIOCSRName()}},
+			)
+			c := &Controller{
+				kubeClientSet:  kubeClientSet,
+				minioClientSet: miniofake.NewSimpleClientset(tenant),
+				recorder:       record.NewFakeRecorder(10),
+			}
+
+			if _, err := c.syncTenantDeletion(ctx, tenant); err != nil {
+				t.Fatal(err)
+			}
+
+			updated, err := c.minioClientSet.MinioV2().Tenants("tenant-ns").Get(ctx, "myminio", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(updated.Finalizers) != 0 {
+				t.Errorf("expected the finalizer to be removed, got %v", updated.Finalizers)
+			}
+			if _, err = kubeClientSet.CertificatesV1().CertificateSigningRequests().Get(ctx, tenant.MinIOCSRName(), metav1.GetOptions{}); !k8serrors.IsNotFound(err) {
+				t.Errorf("expected the CSR to be deleted, got %v", err)
+			}
+			if _, err = kubeClientSet.CoreV1().PersistentVolumeClaims("tenant-ns").Get(ctx, "data0-other-pool-0-0", metav1.GetOptions{}); err != nil {
+				t.Errorf("the PVC of another tenant must be left alone: %v", err)
+			}
+			if _, err = kubeClientSet.CoreV1().Secrets("tenant-ns").Get(ctx, tenant.KESTLSSecretName(), metav1.GetOptions{}); err != nil {
+				t.Errorf("a secret not generated by the operator must be left alone: %v", err)
+			}
+
+			pvc, pvcErr := kubeClientSet.CoreV1().PersistentVolumeClaims("tenant-ns").Get(ctx, "data0-myminio-pool-0-0", metav1.GetOptions{})
+			secret, secretErr := kubeClientSet.CoreV1().Secrets("tenant-ns").Get(ctx, tenant.MinIOTLSSecretName(), metav1.GetOptions{})
+			if policy == miniov2.DeletionPolicyDelete {
+				if !k8serrors.IsNotFound(pvcErr) || !k8serrors.IsNotFound(secretErr) {
+					t.Errorf("expected the PVC and the TLS secret to be deleted, got %v, %v", pvcErr, secretErr)
+				}
+				return
+			}
+			if pvcErr != nil || secretErr != nil {
+				t.Fatalf("expected the PVC and the TLS secret to be retained, got %v, %v", pvcErr, secretErr)
+			}
+			if len(secret.OwnerReferences) != 0 {
+				t.Errorf("expected the retained TLS secret to be released from the tenant")
+			}
+			labeled := policy == miniov2.DeletionPolicyRetainAndLabel
+			if (pvc.Labels[miniov2.RetainedFromLabel] == "myminio") != labeled || (secret.Labels[miniov2.RetainedFromLabel] == "myminio") != labeled {
+				t.Errorf("unexpected labels %v, %v", pvc.Labels, secret.Labels)
+			}
+		})
+	}
+}
+
+func TestRemoveTenantFinalizers(t *testing.T) {
+	ctx := context.Background()
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultDeploymentName, Namespace: "minio-operator"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	tenants := []*miniov2.Tenant{
+		{ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns", Finalizers: []string{miniov2.TenantFinalizer}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other-ns", Finalizers: []string{"example.com/keep", miniov2.TenantFinalizer}}},
+	}
+	kubeClient := fake.NewSimpleClientset(deployment)
+	minioClient := miniofake.NewSimpleClientset(tenants[0], tenants[1])
+
+	if err := removeTenantFinalizers(ctx, kubeClient, minioClient, "minio-operator", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	stopped, err := kubeClient.AppsV1().Deployments("minio-operator").Get(ctx, DefaultDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *stopped.Spec.Replicas != 0 {
+		t.Errorf("expected the Operator to be scaled down, got %d replicas", *stopped.Spec.Replicas)
+	}
+	for _, tenant := range tenants {
+		updated, err := minioClient.MinioV2().Tenants(tenant.Namespace).Get(ctx, tenant.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if slices.Contains(updated.Finalizers, miniov2.TenantFinalizer) {
+			t.Errorf("expected the finalizer of %s to be removed, got %v", tenant.Name, updated.Finalizers)
+		}
+		if len(updated.Finalizers) != len(tenant.Finalizers)-1 {
+			t.Errorf("expected the other finalizers of %s to be kept, got %v", tenant.Name, updated.Finalizers)
+		}
+	}
+}
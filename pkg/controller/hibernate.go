@@ -0,0 +1,113 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// syncHibernatedTenant scales the statefulsets of the tenant to zero, remembering their replicas to restore them on resume
+func (c *Controller) syncHibernatedTenant(ctx context.Context, tenant *miniov2.Tenant) (Result, error) {
+	for _, ssName := range tenantStatefulSets(tenant) {
+		ss, err := c.statefulSetLister.StatefulSets(tenant.Namespace).Get(ssName)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return WrapResult(Result{}, err)
+		}
+		if ss.Spec.Replicas != nil && *ss.Spec.Replicas == 0 {
+			continue
+		}
+		replicas := int32(1)
+		if ss.Spec.Replicas != nil {
+			replicas = *ss.Spec.Replicas
+		}
+		ss = ss.DeepCopy()
+		if ss.Annotations == nil {
+			ss.Annotations = map[string]string{}
+		}
+		ss.Annotations[miniov2.HibernateReplicasAnnotation] = strconv.Itoa(int(replicas))
+		zero := int32(0)
+		ss.Spec.Replicas = &zero
+		klog.Infof("'%s/%s' scaling statefulset %s from %d replicas to zero", tenant.Namespace, tenant.Name, ssName, replicas)
+		if _, err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Update(ctx, ss, metav1.UpdateOptions{}); err != nil {
+			return WrapResult(Result{}, err)
+		}
+	}
+	if tenant.Status.CurrentState != StatusHibernated {
+		c.recorder.Event(tenant, corev1.EventTypeNormal, "Hibernated", "Tenant hibernated, all its pods were scaled to zero")
+	}
+	if _, err := c.updateTenantStatus(ctx, tenant, StatusHibernated, 0); err != nil {
+		return WrapResult(Result{}, err)
+	}
+	return WrapResult(Result{}, nil)
+}
+
+// resumeHibernatedTenant restores the replicas of the statefulsets scaled to zero by the hibernation of the tenant
+func (c *Controller) resumeHibernatedTenant(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	resumed := tenant.Status.CurrentState == StatusHibernated
+	for _, ssName := range tenantStatefulSets(tenant) {
+		ss, err := c.statefulSetLister.StatefulSets(tenant.Namespace).Get(ssName)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		value, ok := ss.Annotations[miniov2.HibernateReplicasAnnotation]
+		if !ok {
+			continue
+		}
+		replicas, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("statefulset %s has invalid %s annotation '%s': %w", ssName, miniov2.HibernateReplicasAnnotation, value, err)
+		}
+		ss = ss.DeepCopy()
+		delete(ss.Annotations, miniov2.HibernateReplicasAnnotation)
+		restored := int32(replicas)
+		ss.Spec.Replicas = &restored
+		klog.Infof("'%s/%s' restoring %d replicas of statefulset %s", tenant.Namespace, tenant.Name, restored, ssName)
+		if _, err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Update(ctx, ss, metav1.UpdateOptions{}); err != nil {
+			return nil, err
+		}
+		resumed = true
+	}
+	if !resumed {
+		return tenant, nil
+	}
+	c.recorder.Event(tenant, corev1.EventTypeNormal, "Resumed", "Tenant resumed from hibernation")
+	return c.updateTenantStatus(ctx, tenant, StatusResumingFromHibernation, 0)
+}
+
+// tenantStatefulSets returns the names of the statefulsets deployed for the tenant, its pools and KES
+func tenantStatefulSets(tenant *miniov2.Tenant) []string {
+	var names []string
+	for _, pool := range tenant.Status.Pools {
+		names = append(names, pool.SSName)
+	}
+	if tenant.HasKESEnabled() {
+		names = append(names, tenant.KESStatefulSetName())
+	}
+	return names
+}
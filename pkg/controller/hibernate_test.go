@@ -0,0 +1,100 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	miniofake "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestHibernateTenant(t *testing.T) {
+	ctx := context.Background()
+	replicas := int32(4)
+	ss := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio-pool-0", Namespace: "tenant-ns"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+		Spec:       miniov2.TenantSpec{Hibernate: true},
+		Status: miniov2.TenantStatus{
+			CurrentState: StatusInitialized,
+			Pools:        []miniov2.PoolStatus{{SSName: "myminio-pool-0", State: miniov2.PoolInitialized}},
+		},
+	}
+	kubeClientSet := fake.NewSimpleClientset(ss)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	c := &Controller{
+		kubeClientSet:     kubeClientSet,
+		minioClientSet:    miniofake.NewSimpleClientset(tenant),
+		statefulSetLister: appslisters.NewStatefulSetLister(indexer),
+		recorder:          record.NewFakeRecorder(10),
+	}
+	syncLister := func() *appsv1.StatefulSet {
+		t.Helper()
+		current, err := kubeClientSet.AppsV1().StatefulSets("tenant-ns").Get(ctx, "myminio-pool-0", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = indexer.Update(current); err != nil {
+			t.Fatal(err)
+		}
+		return current
+	}
+	syncLister()
+
+	if _, err := c.syncHibernatedTenant(ctx, tenant); err != nil {
+		t.Fatal(err)
+	}
+	hibernated := syncLister()
+	if *hibernated.Spec.Replicas != 0 || hibernated.Annotations[miniov2.HibernateReplicasAnnotation] != "4" {
+		t.Fatalf("expected the statefulset to be scaled to zero, got %d replicas and annotations %v", *hibernated.Spec.Replicas, hibernated.Annotations)
+	}
+	// hibernating again leaves the statefulset as it is
+	if _, err := c.syncHibernatedTenant(ctx, tenant); err != nil {
+		t.Fatal(err)
+	}
+	if again := syncLister(); again.Annotations[miniov2.HibernateReplicasAnnotation] != "4" {
+		t.Fatalf("expected the replicas to restore to be kept, got annotations %v", again.Annotations)
+	}
+
+	tenant.Spec.Hibernate = false
+	tenant.Status.CurrentState = StatusHibernated
+	resumedTenant, err := c.resumeHibernatedTenant(ctx, tenant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed := syncLister()
+	if *resumed.Spec.Replicas != 4 {
+		t.Errorf("expected the replicas to be restored, got %d", *resumed.Spec.Replicas)
+	}
+	if _, ok := resumed.Annotations[miniov2.HibernateReplicasAnnotation]; ok {
+		t.Errorf("expected the annotation to be removed, got %v", resumed.Annotations)
+	}
+	if resumedTenant.Status.CurrentState != StatusResumingFromHibernation {
+		t.Errorf("expected the tenant to be resuming, got %s", resumedTenant.Status.CurrentState)
+	}
+}
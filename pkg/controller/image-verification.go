@@ -0,0 +1,262 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// cosignSignatureAnnotation is the annotation of the layers of a cosign signature image holding the signature of
+// the layer payload
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+// cosignPayload is the simple signing payload signed by cosign
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// verifyImage runs the checks of the image verification policy of the tenant on the image of an upgrade. The digests
+// are the digests the image is known by, the keychain is only set for images pulled from a registry.
+func (c *Controller) verifyImage(ctx context.Context, tenant *miniov2.Tenant, ref name.Reference, digests []v1.Hash, release string, keychain authn.Keychain) error {
+	policy := tenant.Spec.ImageVerification
+	if policy == nil {
+		return nil
+	}
+	if err := checkAllowedDigests(policy.AllowedDigests, digests); err != nil {
+		return fmt.Errorf("%w: %v", ErrImageVerificationFailed, err)
+	}
+	if err := checkRelease(policy, tenant.Spec.Image, release); err != nil {
+		return fmt.Errorf("%w: %v", ErrImageVerificationFailed, err)
+	}
+	if policy.CosignPublicKey == nil {
+		return nil
+	}
+	if keychain == nil {
+		return fmt.Errorf("%w: cosign signatures can only be verified for images pulled from a registry", ErrImageVerificationFailed)
+	}
+	secret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, policy.CosignPublicKey.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	key, ok := secret.Data[policy.CosignPublicKey.Key]
+	if !ok {
+		return fmt.Errorf("%w: key %s not found in secret %s", ErrImageVerificationFailed, policy.CosignPublicKey.Key, policy.CosignPublicKey.Name)
+	}
+	publicKey, err := parsePublicKey(key)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrImageVerificationFailed, err)
+	}
+	for _, digest := range digests {
+		signatures, err := cosignSignatures(ref, digest, keychain)
+		if err != nil {
+			return err
+		}
+		if verifyCosignSignatures(publicKey, signatures, digests) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no valid cosign signature found for %s", ErrImageVerificationFailed, ref)
+}
+
+// imageVerificationHash identifies what the image of an upgrade is verified with: the verification policy, the upgrade
+// source and the cosign public key
+func (c *Controller) imageVerificationHash(ctx context.Context, tenant *miniov2.Tenant) (string, error) {
+	spec, err := json.Marshal([]interface{}{tenant.Spec.ImageVerification, tenant.Spec.UpgradeSource})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	hash.Write(spec)
+	if policy := tenant.Spec.ImageVerification; policy != nil && policy.CosignPublicKey != nil {
+		secret, err := c.kubeClientSet.CoreV1().Secrets(tenant.Namespace).Get(ctx, policy.CosignPublicKey.Name, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return "", err
+		}
+		if err == nil {
+			hash.Write(secret.Data[policy.CosignPublicKey.Key])
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// checkAllowedDigests returns an error if none of the digests is allowed, any digest is allowed by an empty list
+func checkAllowedDigests(allowed []string, digests []v1.Hash) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	if len(digests) == 0 {
+		return errors.New("the upgrade source has no image digest to check against the allowed digests")
+	}
+	var found []string
+	for _, digest := range digests {
+		for _, a := range allowed {
+			if strings.TrimSpace(a) == digest.String() {
+				return nil
+			}
+		}
+		found = append(found, digest.String())
+	}
+	return fmt.Errorf("digest %s is not allowed", strings.Join(found, ", "))
+}
+
+// checkRelease returns an error if the release of the image doesn't match its tag or is older than the minimum
+// release of the policy, the tag is read from the image as a reference with a digest drops it
+func checkRelease(policy *miniov2.ImageVerification, image string, release string) error {
+	releaseTime, err := miniov2.ReleaseTagToReleaseTime(release)
+	if err != nil {
+		return fmt.Errorf("invalid release label %s: %v", release, err)
+	}
+	if tag := imageTag(image); policy.RequireMatchingRelease && tag != release {
+		return fmt.Errorf("release label %s doesn't match the image tag %s", release, tag)
+	}
+	if policy.MinimumRelease != "" {
+		minimumTime, err := miniov2.ReleaseTagToReleaseTime(policy.MinimumRelease)
+		if err != nil {
+			return fmt.Errorf("invalid minimum release %s: %v", policy.MinimumRelease, err)
+		}
+		if releaseTime.Before(minimumTime) {
+			return fmt.Errorf("release %s is older than the minimum release %s", release, policy.MinimumRelease)
+		}
+	}
+	return nil
+}
+
+// cosignSignature is a payload signed by cosign along with its signature
+type cosignSignature struct {
+	payload   []byte
+	signature []byte
+}
+
+// cosignSignatures returns the cosign signatures of the digest, stored in the repository of the image under the
+// `sha256-<hex>.sig` tag, none if the digest isn't signed
+func cosignSignatures(ref name.Reference, digest v1.Hash, keychain authn.Keychain) ([]cosignSignature, error) {
+	sigRef := ref.Context().Tag(fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex))
+	img, err := remote.Image(sigRef, remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	var signatures []cosignSignature
+	for _, desc := range manifest.Layers {
+		encoded, ok := desc.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+		layer, err := img.LayerByDigest(desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, cosignSignature{payload: payload, signature: signature})
+	}
+	return signatures, nil
+}
+
+// verifyCosignSignatures returns true if one of the signatures was made with the key over a payload for one of the
+// digests
+func verifyCosignSignatures(publicKey crypto.PublicKey, signatures []cosignSignature, digests []v1.Hash) bool {
+	for _, s := range signatures {
+		if verifySignature(publicKey, s.payload, s.signature) != nil {
+			continue
+		}
+		var payload cosignPayload
+		if err := json.Unmarshal(s.payload, &payload); err != nil {
+			continue
+		}
+		for _, digest := range digests {
+			if payload.Critical.Image.DockerManifestDigest == digest.String() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parsePublicKey parses a PEM encoded public key
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("the cosign public key isn't PEM encoded")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// verifySignature verifies the signature of the payload like cosign does for the type of the key
+func verifySignature(publicKey crypto.PublicKey, payload, signature []byte) error {
+	digest := sha256.Sum256(payload)
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, signature) {
+			return errors.New("invalid Ed25519 signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
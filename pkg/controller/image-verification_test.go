@@ -0,0 +1,163 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_checkAllowedDigests(t *testing.T) {
+	digest := v1.Hash{Algorithm: "sha256", Hex: "4f9dd81c4f0e1e5d0c2d7b6b1f3b2f5e6d8c9a0b1c2d3e4f5a6b7c8d9e0f1a2b"}
+	index := v1.Hash{Algorithm: "sha256", Hex: "0b1c2d3e4f5a6b7c8d9e0f1a2b4f9dd81c4f0e1e5d0c2d7b6b1f3b2f5e6d8c9a"}
+	tests := []struct {
+		name    string
+		allowed []string
+		digests []v1.Hash
+		wantErr bool
+	}{
+		{name: "No allow-list", digests: []v1.Hash{digest}},
+		{name: "Image digest allowed", allowed: []string{digest.String()}, digests: []v1.Hash{index, digest}},
+		{name: "Index digest allowed", allowed: []string{index.String()}, digests: []v1.Hash{index, digest}},
+		{name: "Digest not allowed", allowed: []string{index.String()}, digests: []v1.Hash{digest}, wantErr: true},
+		{name: "No digest", allowed: []string{index.String()}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkAllowedDigests(tt.allowed, tt.digests); (err != nil) != tt.wantErr {
+				t.Errorf("checkAllowedDigests() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkRelease(t *testing.T) {
+	image := "quay.io/minio/minio:RELEASE.2024-03-15T01-07-19Z"
+	tests := []struct {
+		name    string
+		policy  miniov2.ImageVerification
+		image   string
+		release string
+		wantErr bool
+	}{
+		{name: "Matching release", policy: miniov2.ImageVerification{RequireMatchingRelease: true}, release: "RELEASE.2024-03-15T01-07-19Z"},
+		{name: "Matching release of a tag with a digest", policy: miniov2.ImageVerification{RequireMatchingRelease: true}, image: image + "@sha256:4f9d0b0a4cd6a4a8b0e3c3b3f6a5e8f7d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7", release: "RELEASE.2024-03-15T01-07-19Z"},
+		{name: "Image with a digest only", policy: miniov2.ImageVerification{RequireMatchingRelease: true}, image: "quay.io/minio/minio@sha256:4f9d0b0a4cd6a4a8b0e3c3b3f6a5e8f7d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7", release: "RELEASE.2024-03-15T01-07-19Z", wantErr: true},
+		{name: "Release not matching the tag", policy: miniov2.ImageVerification{RequireMatchingRelease: true}, release: "RELEASE.2024-02-26T09-33-48Z", wantErr: true},
+		{name: "Release after the minimum", policy: miniov2.ImageVerification{MinimumRelease: "RELEASE.2024-01-01T00-00-00Z"}, release: "RELEASE.2024-03-15T01-07-19Z"},
+		{name: "Release before the minimum", policy: miniov2.ImageVerification{MinimumRelease: "RELEASE.2024-03-15T01-07-19Z"}, release: "RELEASE.2024-02-26T09-33-48Z", wantErr: true},
+		{name: "Invalid release label", release: "latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.image == "" {
+				tt.image = image
+			}
+			if err := checkRelease(&tt.policy, tt.image, tt.release); (err != nil) != tt.wantErr {
+				t.Errorf("checkRelease() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_verifyCosignSignatures(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := parsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := v1.Hash{Algorithm: "sha256", Hex: "4f9dd81c4f0e1e5d0c2d7b6b1f3b2f5e6d8c9a0b1c2d3e4f5a6b7c8d9e0f1a2b"}
+	other := v1.Hash{Algorithm: "sha256", Hex: "0b1c2d3e4f5a6b7c8d9e0f1a2b4f9dd81c4f0e1e5d0c2d7b6b1f3b2f5e6d8c9a"}
+	sign := func(signer *ecdsa.PrivateKey, digest v1.Hash) cosignSignature {
+		payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"quay.io/minio/minio"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, digest))
+		sum := sha256.Sum256(payload)
+		signature, err := ecdsa.SignASN1(rand.Reader, signer, sum[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cosignSignature{payload: payload, signature: signature}
+	}
+	tests := []struct {
+		name       string
+		signatures []cosignSignature
+		want       bool
+	}{
+		{name: "Signed", signatures: []cosignSignature{sign(key, digest)}, want: true},
+		{name: "Signed among others", signatures: []cosignSignature{sign(otherKey, digest), sign(key, digest)}, want: true},
+		{name: "Signed by another key", signatures: []cosignSignature{sign(otherKey, digest)}},
+		{name: "Signature of another image", signatures: []cosignSignature{sign(key, other)}},
+		{name: "Not signed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyCosignSignatures(publicKey, tt.signatures, []v1.Hash{digest}); got != tt.want {
+				t.Errorf("verifyCosignSignatures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyImage(t *testing.T) {
+	ref, err := name.ParseReference("quay.io/minio/minio:RELEASE.2024-03-15T01-07-19Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cosign", Namespace: "tenant-ns"},
+		Data:       map[string][]byte{"cosign.pub": []byte("not a key")},
+	}
+	c := &Controller{kubeClientSet: fake.NewSimpleClientset(secret)}
+	tenant := &miniov2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"}}
+
+	// without a policy any image is served
+	if err = c.verifyImage(context.Background(), tenant, ref, nil, "RELEASE.2024-03-15T01-07-19Z", nil); err != nil {
+		t.Fatal(err)
+	}
+	// signatures are only looked up in registries
+	tenant.Spec.ImageVerification = &miniov2.ImageVerification{
+		CosignPublicKey: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "cosign"}, Key: "cosign.pub"},
+	}
+	if err = c.verifyImage(context.Background(), tenant, ref, nil, "RELEASE.2024-03-15T01-07-19Z", nil); !errors.Is(err, ErrImageVerificationFailed) {
+		t.Fatalf("expected the verification to fail without a registry, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/runtime"
+	"github.com/minio/operator/pkg/utils/miniojob"
+	iampolicy "github.com/minio/pkg/iam/policy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ensureAccessKey creates the access key the jobs of the MinIOJob use in access key mode, scoped to the policies bound
+// to its service account, unless the job secret already holds one
+func (c *JobController) ensureAccessKey(ctx context.Context, jobCR *v1alpha1.MinIOJob, tenant *miniov2.Tenant, policies []string) error {
+	secret := &corev1.Secret{}
+	err := c.k8sClient.Get(ctx, client.ObjectKey{Namespace: jobCR.Namespace, Name: miniojob.JobSecretName(jobCR.Name)}, secret)
+	if err == nil && len(secret.Data[miniojob.AccessKeySecretKey]) > 0 {
+		return nil
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	// the finalizer goes first, an access key is never left behind if the MinIOJob is deleted meanwhile
+	if controllerutil.AddFinalizer(jobCR, miniojob.AccessKeyFinalizer) {
+		if err = c.k8sClient.Update(ctx, jobCR); err != nil {
+			return err
+		}
+	}
+	adminClient, err := c.tenantAdminClient(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	policy, err := mergePolicies(ctx, adminClient, policies)
+	if err != nil {
+		return err
+	}
+	credentials, err := adminClient.AddServiceAccount(ctx, madmin.AddServiceAccountReq{
+		Policy:      policy,
+		Description: fmt.Sprintf("MinIOJob %s/%s", jobCR.Namespace, jobCR.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create the access key: %w", err)
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      miniojob.JobSecretName(jobCR.Name),
+			Namespace: jobCR.Namespace,
+		},
+	}
+	_, err = runtime.NewObjectSyncer(ctx, c.k8sClient, jobCR, func() error {
+		secret.StringData = nil
+		secret.Data = miniojob.AccessKeySecretData(tenant, credentials.AccessKey, credentials.SecretKey)
+		return nil
+	}, secret, runtime.SyncTypeCreateOrUpdate).Sync(ctx)
+	if err != nil {
+		// the access key is useless if the jobs can't read it
+		if derr := adminClient.DeleteServiceAccount(ctx, credentials.AccessKey); derr != nil {
+			klog.Errorf("Unable to delete the access key %s of MinIOJob %s/%s: %v", credentials.AccessKey, jobCR.Namespace, jobCR.Name, derr)
+		}
+		return err
+	}
+	c.recorder.Eventf(jobCR, corev1.EventTypeNormal, "AccessKeyCreated", "Access key %s created for the jobs", credentials.AccessKey)
+	return nil
+}
+
+// revokeAccessKey deletes the access key of the MinIOJob and the secret holding it, then removes the finalizer
+// nothing is done for a MinIOJob without access key to revoke
+func (c *JobController) revokeAccessKey(ctx context.Context, jobCR *v1alpha1.MinIOJob) error {
+	if !controllerutil.ContainsFinalizer(jobCR, miniojob.AccessKeyFinalizer) {
+		return nil
+	}
+	secret := &corev1.Secret{}
+	err := c.k8sClient.Get(ctx, client.ObjectKey{Namespace: jobCR.Namespace, Name: miniojob.JobSecretName(jobCR.Name)}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if accessKey := string(secret.Data[miniojob.AccessKeySecretKey]); err == nil && accessKey != "" {
+		if err = c.deleteAccessKey(ctx, jobCR, accessKey); err != nil {
+			return err
+		}
+		if err = c.k8sClient.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		c.recorder.Eventf(jobCR, corev1.EventTypeNormal, "AccessKeyRevoked", "Access key %s revoked", accessKey)
+	}
+	controllerutil.RemoveFinalizer(jobCR, miniojob.AccessKeyFinalizer)
+	return c.k8sClient.Update(ctx, jobCR)
+}
+
+// deleteAccessKey deletes the access key from the tenant, an access key that is already gone, along with its tenant or
+// not, is not an error
+func (c *JobController) deleteAccessKey(ctx context.Context, jobCR *v1alpha1.MinIOJob, accessKey string) error {
+	tenant := &miniov2.Tenant{}
+	err := c.k8sClient.Get(ctx, client.ObjectKey{Namespace: jobCR.Spec.TenantRef.Namespace, Name: jobCR.Spec.TenantRef.Name}, tenant)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	adminClient, err := c.tenantAdminClient(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	err = adminClient.DeleteServiceAccount(ctx, accessKey)
+	if err != nil && madmin.ToErrorResponse(err).Code != "XMinioAdminServiceAccountNotFound" {
+		return fmt.Errorf("unable to revoke the access key %s: %w", accessKey, err)
+	}
+	return nil
+}
+
+// mergePolicies merges the tenant policies into a single policy document
+func mergePolicies(ctx context.Context, adminClient *madmin.AdminClient, policies []string) (json.RawMessage, error) {
+	var merged iampolicy.Policy
+	for _, policyName := range policies {
+		policy, err := GetPolicy(ctx, adminClient, policyName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get policy %s: %w", policyName, err)
+		}
+		parsedPolicy, err := iampolicy.ParseConfig(bytes.NewReader(policy.Policy))
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy %s: %w", policyName, err)
+		}
+		merged = merged.Merge(*parsedPolicy)
+	}
+	if len(merged.Statements) == 0 {
+		return nil, fmt.Errorf("no policy bound to the service account")
+	}
+	return json.Marshal(merged)
+}
@@ -0,0 +1,204 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	"github.com/minio/operator/pkg/utils/miniojob"
+	batchjobv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncJobActions applies the cancel and rerun actions requested through the annotations of the MinIOJob, then
+// removes the annotations. A cancel is applied before a rerun, both together restart the MinIOJob.
+func (c *JobController) syncJobActions(ctx context.Context, jobCR *v1alpha1.MinIOJob) error {
+	_, cancel := jobCR.Annotations[miniojob.MinioJobCancel]
+	rerun, doRerun := jobCR.Annotations[miniojob.MinioJobRerun]
+	if !cancel && !doRerun {
+		return nil
+	}
+	now := time.Now().UTC()
+	if cancel {
+		if err := c.cancelJob(ctx, jobCR, now); err != nil {
+			return err
+		}
+	}
+	if doRerun {
+		if err := c.rerunJob(ctx, jobCR, rerun == miniojob.MinioJobRerunAll, now); err != nil {
+			return err
+		}
+	}
+	if err := c.updateJobStatus(ctx, jobCR); err != nil {
+		return err
+	}
+	delete(jobCR.Annotations, miniojob.MinioJobCancel)
+	delete(jobCR.Annotations, miniojob.MinioJobRerun)
+	return c.k8sClient.Update(ctx, jobCR)
+}
+
+// cancelJob deletes the Kubernetes Jobs of the commands that did not finish yet, and reports them as cancelled
+func (c *JobController) cancelJob(ctx context.Context, jobCR *v1alpha1.MinIOJob, now time.Time) error {
+	if miniojob.FinishedPhase(jobCR.Status.Phase) {
+		return nil
+	}
+	intervalJob, err := checkMinIOJob(jobCR)
+	if err != nil {
+		// an invalid job has nothing running
+		return nil
+	}
+	intervalJob.StartRun(jobCR.Status.Run)
+	intervalJob.LoadState(jobCR.Status.CommandsStatus, nil)
+	if intervalJob.RunningBatchJobs() {
+		c.cancelBatchJobs(ctx, jobCR, intervalJob)
+	}
+	intervalJob.Cancel()
+	err = c.deleteJobs(ctx, jobCR, func(job *batchjobv1.Job) bool {
+		return job.Labels[miniojob.MinioJobRun] == jobCR.Status.Run && miniojob.JobCompletionTime(job) == nil
+	})
+	if err != nil {
+		return err
+	}
+	status := intervalJob.GetMinioJobStatus(ctx)
+	jobCR.Status.Phase = status.Phase
+	jobCR.Status.Message = status.Message
+	jobCR.Status.CommandsStatus = status.CommandsStatus
+	if jobCR.Spec.Schedule != "" && jobCR.Status.Run != "" {
+		c.recordRun(ctx, jobCR, jobCR.Status.Run, status, now)
+	} else if jobCR.Status.CompletionTime == nil {
+		completionTime := metav1.NewTime(now)
+		jobCR.Status.CompletionTime = &completionTime
+	}
+	c.recorder.Eventf(jobCR, corev1.EventTypeNormal, "Cancelled", "Commands cancelled")
+	return nil
+}
+
+// rerunJob starts a new run of a finished MinIOJob, with all its commands or only the ones that did not succeed
+func (c *JobController) rerunJob(ctx context.Context, jobCR *v1alpha1.MinIOJob, all bool, now time.Time) error {
+	if !miniojob.FinishedPhase(jobCR.Status.Phase) {
+		c.recorder.Eventf(jobCR, corev1.EventTypeWarning, "RerunIgnored", "Rerun ignored, the commands are still running, cancel them first")
+		return nil
+	}
+	commandsStatus := miniojob.RerunCommandsStatus(jobCR.Status.CommandsStatus, all)
+	// the jobs of a scheduled run are kept along with its record in the history
+	if jobCR.Spec.Schedule == "" {
+		kept := map[string]bool{}
+		for _, commandStatus := range commandsStatus {
+			kept[commandStatus.Name] = true
+		}
+		err := c.deleteJobs(ctx, jobCR, func(job *batchjobv1.Job) bool {
+			return job.Labels[miniojob.MinioJobRun] == jobCR.Status.Run && !kept[job.Labels[miniojob.MinioJobName]]
+		})
+		if err != nil {
+			return err
+		}
+	}
+	run := miniojob.RerunName(now)
+	jobCR.Status.Run = run
+	jobCR.Status.Phase = miniojob.MinioJobPhaseRunning
+	jobCR.Status.Message = ""
+	jobCR.Status.CommandsStatus = commandsStatus
+	jobCR.Status.CompletionTime = nil
+	klog.Infof("MinIOJob '%s/%s' running again as run %s", jobCR.Namespace, jobCR.Name, run)
+	c.recorder.Eventf(jobCR, corev1.EventTypeNormal, "Rerun", "Running %d commands again as run %s", len(jobCR.Spec.Commands)-len(commandsStatus), run)
+	return nil
+}
+
+// deleteJobs deletes the Kubernetes Jobs owned by the MinIOJob that match the filter
+func (c *JobController) deleteJobs(ctx context.Context, jobCR *v1alpha1.MinIOJob, filter func(job *batchjobv1.Job) bool) error {
+	jobs := &batchjobv1.JobList{}
+	err := c.k8sClient.List(ctx, jobs, client.InNamespace(jobCR.Namespace), client.MatchingLabels{
+		miniojob.MinioJobCRName: jobCR.Name,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if !metav1.IsControlledBy(job, jobCR) || !filter(job) {
+			continue
+		}
+		err = c.k8sClient.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncFinishedJob revokes the access key of a finished MinIOJob without schedule, and deletes it once its TTL is over
+func (c *JobController) syncFinishedJob(ctx context.Context, jobCR *v1alpha1.MinIOJob) (Result, error) {
+	if err := c.revokeAccessKey(ctx, jobCR); err != nil {
+		return WrapResult(Result{}, err)
+	}
+	if jobCR.Status.CompletionTime == nil {
+		// finished before the completion time was tracked
+		completionTime := metav1.Now()
+		jobCR.Status.CompletionTime = &completionTime
+		if err := c.updateJobStatus(ctx, jobCR); err != nil {
+			return WrapResult(Result{}, err)
+		}
+	}
+	if jobCR.Spec.TTLAfterFinished == nil {
+		return WrapResult(Result{}, nil)
+	}
+	expireAt := jobCR.Status.CompletionTime.Add(time.Duration(*jobCR.Spec.TTLAfterFinished) * time.Second)
+	if now := time.Now(); now.Before(expireAt) {
+		return WrapResult(Result{RequeueAfter: expireAt.Sub(now) + time.Second}, nil)
+	}
+	klog.Infof("MinIOJob '%s/%s' finished %s ago, deleting it", jobCR.Namespace, jobCR.Name, time.Since(jobCR.Status.CompletionTime.Time).Round(time.Second))
+	err := c.k8sClient.Delete(ctx, jobCR, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		return WrapResult(Result{}, fmt.Errorf("unable to delete the expired MinIOJob: %w", err))
+	}
+	return WrapResult(Result{}, nil)
+}
+
+// expireRunJobs deletes the Kubernetes Jobs of the finished scheduled runs whose TTL is over, and gets how long to
+// wait before the next run expires, zero if none will
+func (c *JobController) expireRunJobs(ctx context.Context, jobCR *v1alpha1.MinIOJob, now time.Time) (time.Duration, error) {
+	if jobCR.Spec.TTLAfterFinished == nil {
+		return 0, nil
+	}
+	ttl := time.Duration(*jobCR.Spec.TTLAfterFinished) * time.Second
+	var next time.Duration
+	expired := map[string]bool{}
+	for _, record := range jobCR.Status.History {
+		if record.CompletionTime == nil {
+			continue
+		}
+		expireAt := record.CompletionTime.Add(ttl)
+		if !now.Before(expireAt) {
+			expired[record.Name] = true
+		} else if wait := expireAt.Sub(now) + time.Second; next == 0 || wait < next {
+			next = wait
+		}
+	}
+	if len(expired) == 0 {
+		return next, nil
+	}
+	return next, c.deleteJobs(ctx, jobCR, func(job *batchjobv1.Job) bool {
+		return expired[job.Labels[miniojob.MinioJobRun]]
+	})
+}
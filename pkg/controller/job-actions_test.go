@@ -0,0 +1,206 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/utils/miniojob"
+	batchjobv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newFakeJobController returns a job controller working on a fake cluster holding the objects
+func newFakeJobController(objs ...client.Object) *JobController {
+	scheme := k8sruntime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = miniov2.AddToScheme(scheme)
+	return &JobController{
+		k8sClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(&v1alpha1.MinIOJob{}).Build(),
+		recorder:  record.NewFakeRecorder(100),
+	}
+}
+
+func newTestMinIOJob() *v1alpha1.MinIOJob {
+	return &v1alpha1.MinIOJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "job.min.io/v1alpha1", Kind: "MinIOJob"},
+		ObjectMeta: metav1.ObjectMeta{Name: "setup", Namespace: "ns", UID: "setup-uid"},
+		Spec: v1alpha1.MinIOJobSpec{
+			ServiceAccountName: "mc-job-sa",
+			TenantRef:          v1alpha1.TenantRef{Name: "myminio", Namespace: "ns"},
+			Commands: []v1alpha1.CommandSpec{
+				{Name: "a", Command: []string{"mc", "ls", "myminio"}},
+				{Name: "b", Command: []string{"mc", "ls", "myminio"}},
+			},
+		},
+	}
+}
+
+func newTestCommandJob(jobCR *v1alpha1.MinIOJob, command string, finished bool) *batchjobv1.Job {
+	job := &batchjobv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            jobCR.Name + "-" + command,
+			Namespace:       jobCR.Namespace,
+			Labels:          map[string]string{miniojob.MinioJobCRName: jobCR.Name, miniojob.MinioJobName: command, miniojob.MinioJobAttempt: "1"},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(jobCR, v1alpha1.SchemeGroupVersion.WithKind("MinIOJob"))},
+		},
+	}
+	if finished {
+		job.Status.Conditions = []batchjobv1.JobCondition{{Type: batchjobv1.JobComplete, Status: corev1.ConditionTrue}}
+	}
+	return job
+}
+
+func jobExists(t *testing.T, c *JobController, job *batchjobv1.Job) bool {
+	err := c.k8sClient.Get(context.Background(), client.ObjectKeyFromObject(job), &batchjobv1.Job{})
+	if err != nil && !errors.IsNotFound(err) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func TestSyncJobActions(t *testing.T) {
+	ctx := context.Background()
+	jobCR := newTestMinIOJob()
+	jobCR.Annotations = map[string]string{miniojob.MinioJobCancel: ""}
+	jobCR.Status = v1alpha1.MinIOJobStatus{
+		Phase: miniojob.MinioJobPhaseRunning,
+		CommandsStatus: []v1alpha1.CommandStatus{
+			{Name: "a", Result: miniojob.MinioJobCommandSuccess},
+			{Name: "b", Result: miniojob.MinioJobCommandRunning},
+		},
+	}
+	jobA, jobB := newTestCommandJob(jobCR, "a", true), newTestCommandJob(jobCR, "b", false)
+	c := newFakeJobController(jobCR, jobA, jobB)
+
+	if err := c.syncJobActions(ctx, jobCR); err != nil {
+		t.Fatal(err)
+	}
+	if !jobExists(t, c, jobA) || jobExists(t, c, jobB) {
+		t.Errorf("expected only the running job to be deleted")
+	}
+	updated := &v1alpha1.MinIOJob{}
+	if err := c.k8sClient.Get(ctx, client.ObjectKeyFromObject(jobCR), updated); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := updated.Annotations[miniojob.MinioJobCancel]; found {
+		t.Errorf("expected the cancel annotation to be removed")
+	}
+	if updated.Status.Phase != miniojob.MinioJobPhaseCancelled || updated.Status.CommandsStatus[1].Result != miniojob.MinioJobCommandCancelled {
+		t.Errorf("unexpected status after cancel: %+v", updated.Status)
+	}
+	if updated.Status.CompletionTime == nil {
+		t.Errorf("expected the cancelled job to be finished")
+	}
+
+	// run the cancelled command again
+	updated.Annotations = map[string]string{miniojob.MinioJobRerun: "true"}
+	if err := c.syncJobActions(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.k8sClient.Get(ctx, client.ObjectKeyFromObject(jobCR), updated); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(updated.Status.Run, "rerun-") || updated.Status.Phase != miniojob.MinioJobPhaseRunning || updated.Status.CompletionTime != nil {
+		t.Errorf("unexpected status after rerun: %+v", updated.Status)
+	}
+	if len(updated.Status.CommandsStatus) != 1 || updated.Status.CommandsStatus[0].Name != "a" {
+		t.Errorf("expected the succeeded command to be kept, got %+v", updated.Status.CommandsStatus)
+	}
+	if !jobExists(t, c, jobA) {
+		t.Errorf("expected the job of the succeeded command to be kept")
+	}
+	if len(updated.Annotations) != 0 {
+		t.Errorf("expected the rerun annotation to be removed, got %v", updated.Annotations)
+	}
+
+	// a running job cr is not run again
+	updated.Annotations = map[string]string{miniojob.MinioJobRerun: miniojob.MinioJobRerunAll}
+	run := updated.Status.Run
+	if err := c.syncJobActions(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.Run != run {
+		t.Errorf("expected the rerun to be ignored while running")
+	}
+}
+
+func TestSyncFinishedJobTTL(t *testing.T) {
+	ctx := context.Background()
+	ttl := int32(60)
+	jobCR := newTestMinIOJob()
+	jobCR.Spec.TTLAfterFinished = &ttl
+	completionTime := metav1.NewTime(time.Now().Add(-30 * time.Second))
+	jobCR.Status = v1alpha1.MinIOJobStatus{Phase: miniojob.MinioJobPhaseSuccess, CompletionTime: &completionTime}
+	c := newFakeJobController(jobCR)
+
+	result, err := c.syncFinishedJob(ctx, jobCR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > 31*time.Second {
+		t.Errorf("expected to wait for the end of the TTL, got %v", result.RequeueAfter)
+	}
+	completionTime = metav1.NewTime(time.Now().Add(-time.Minute))
+	if _, err = c.syncFinishedJob(ctx, jobCR); err != nil {
+		t.Fatal(err)
+	}
+	err = c.k8sClient.Get(ctx, client.ObjectKeyFromObject(jobCR), &v1alpha1.MinIOJob{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the expired job cr to be deleted, got %v", err)
+	}
+}
+
+func TestExpireRunJobs(t *testing.T) {
+	ttl := int32(3600)
+	now := time.Now()
+	jobCR := newTestMinIOJob()
+	jobCR.Spec.TTLAfterFinished = &ttl
+	oldRun, newRun := metav1.NewTime(now.Add(-2*time.Hour)), metav1.NewTime(now.Add(-30*time.Minute))
+	jobCR.Status.History = []v1alpha1.MinIOJobRun{
+		{Name: "new", CompletionTime: &newRun, Phase: miniojob.MinioJobPhaseSuccess},
+		{Name: "old", CompletionTime: &oldRun, Phase: miniojob.MinioJobPhaseSuccess},
+	}
+	oldJob, newJob := newTestCommandJob(jobCR, "a", true), newTestCommandJob(jobCR, "a", true)
+	oldJob.Name, oldJob.Labels[miniojob.MinioJobRun] = "setup-a-old", "old"
+	newJob.Name, newJob.Labels[miniojob.MinioJobRun] = "setup-a-new", "new"
+	c := newFakeJobController(jobCR, oldJob, newJob)
+
+	next, err := c.expireRunJobs(context.Background(), jobCR, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jobExists(t, c, oldJob) || !jobExists(t, c, newJob) {
+		t.Errorf("expected only the jobs of the expired run to be deleted")
+	}
+	if next < 29*time.Minute || next > 31*time.Minute {
+		t.Errorf("expected the next run to expire in 30m, got %v", next)
+	}
+}
@@ -0,0 +1,125 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/utils/miniojob"
+	iampolicy "github.com/minio/pkg/iam/policy"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// batchAccessKeyExpiration is how long the temporary access key submitting a batch job lives
+const batchAccessKeyExpiration = time.Hour
+
+// scopedBatchJobClient submits the batch jobs of a MinIOJob with a temporary access key limited to the policies bound
+// to its service account, the progress of the batch jobs is read and they are cancelled with the root credentials
+type scopedBatchJobClient struct {
+	*madmin.AdminClient
+	controller *JobController
+	tenant     *miniov2.Tenant
+	jobCR      *v1alpha1.MinIOJob
+	policies   []string
+}
+
+// StartBatchJob checks the bound policies allow the batch job on the buckets it works on, then submits it with a
+// temporary access key scoped to these policies
+func (b *scopedBatchJobClient) StartBatchJob(ctx context.Context, job string) (madmin.BatchJobResult, error) {
+	policy, err := mergePolicies(ctx, b.AdminClient, b.policies)
+	if err != nil {
+		return madmin.BatchJobResult{}, err
+	}
+	parsedPolicy, err := iampolicy.ParseConfig(bytes.NewReader(policy))
+	if err != nil {
+		return madmin.BatchJobResult{}, err
+	}
+	if err = miniojob.CheckBatchPolicy(*parsedPolicy, job); err != nil {
+		return madmin.BatchJobResult{}, err
+	}
+	expiration := time.Now().Add(batchAccessKeyExpiration)
+	credentials, err := b.AdminClient.AddServiceAccount(ctx, madmin.AddServiceAccountReq{
+		Policy:      policy,
+		Description: fmt.Sprintf("MinIOJob %s/%s batch job", b.jobCR.Namespace, b.jobCR.Name),
+		Expiration:  &expiration,
+	})
+	if err != nil {
+		return madmin.BatchJobResult{}, fmt.Errorf("unable to create the access key of the batch job: %w", err)
+	}
+	scopedClient, err := b.controller.tenantUserAdminClient(b.tenant, credentials.AccessKey, credentials.SecretKey)
+	if err != nil {
+		return madmin.BatchJobResult{}, err
+	}
+	return scopedClient.StartBatchJob(ctx, job)
+}
+
+// syncBatchJobs reads the progress of the batch jobs the MinIOJob submitted to the tenant, the batch client is kept
+// to submit the batch jobs of the commands ready to run with the policies bound to the service account of the job
+func (c *JobController) syncBatchJobs(ctx context.Context, tenant *miniov2.Tenant, jobCR *v1alpha1.MinIOJob, intervalJob *miniojob.MinIOIntervalJob, policies []string) error {
+	adminClient, err := c.tenantAdminClient(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	intervalJob.BatchClient = &scopedBatchJobClient{
+		AdminClient: adminClient,
+		controller:  c,
+		tenant:      tenant,
+		jobCR:       jobCR,
+		policies:    policies,
+	}
+	return intervalJob.SyncBatchJobs(ctx, time.Now())
+}
+
+// cancelBatchJobs cancels the running batch jobs of the MinIOJob, the commands are reported as cancelled even if the
+// tenant can't be reached
+func (c *JobController) cancelBatchJobs(ctx context.Context, jobCR *v1alpha1.MinIOJob, intervalJob *miniojob.MinIOIntervalJob) {
+	if intervalJob.BatchClient == nil {
+		tenant := &miniov2.Tenant{}
+		err := c.k8sClient.Get(ctx, client.ObjectKey{Namespace: jobCR.Spec.TenantRef.Namespace, Name: jobCR.Spec.TenantRef.Name}, tenant)
+		if err == nil {
+			adminClient, aerr := c.tenantAdminClient(ctx, tenant)
+			if aerr == nil {
+				intervalJob.BatchClient = adminClient
+			}
+			err = aerr
+		}
+		if err != nil {
+			c.recorder.Eventf(jobCR, corev1.EventTypeWarning, "BatchCancelFailed", "Unable to cancel the batch jobs: %v", err)
+			return
+		}
+	}
+	if err := intervalJob.CancelBatchJobs(ctx); err != nil {
+		c.recorder.Eventf(jobCR, corev1.EventTypeWarning, "BatchCancelFailed", "Unable to cancel the batch jobs: %v", err)
+	}
+}
+
+// commandsRequeueAfter gets how long to wait before the commands need to be synced again, to retry a failed command
+// or to poll the progress of the running batch jobs, zero if none is waiting
+func commandsRequeueAfter(intervalJob *miniojob.MinIOIntervalJob, now time.Time) time.Duration {
+	requeueAfter := retryRequeueAfter(intervalJob, now)
+	if intervalJob.RunningBatchJobs() && (requeueAfter == 0 || requeueAfter > miniojob.BatchJobPollInterval) {
+		return miniojob.BatchJobPollInterval
+	}
+	return requeueAfter
+}
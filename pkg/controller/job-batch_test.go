@@ -0,0 +1,78 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestScopedBatchJobClient(t *testing.T) {
+	adminServer := &fakeAdminServer{}
+	server := httptest.NewServer(adminServer)
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	tenant := &miniov2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "minio-tenant"}}
+	jobCR := &v1alpha1.MinIOJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "expire", Namespace: "minio-tenant"},
+		Spec:       v1alpha1.MinIOJobSpec{TenantRef: v1alpha1.TenantRef{Name: "myminio", Namespace: "minio-tenant"}},
+	}
+	c := newFakeJobController(tenant, jobCR)
+	c.tenantUserAdminClient = func(_ *miniov2.Tenant, accessKey, secretKey string) (*madmin.AdminClient, error) {
+		return madmin.New(serverURL.Host, accessKey, secretKey, false)
+	}
+	rootClient, err := madmin.New(serverURL.Host, "root", "root-secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batchClient := &scopedBatchJobClient{AdminClient: rootClient, controller: c, tenant: tenant, jobCR: jobCR, policies: []string{"memes-access"}}
+	ctx := context.Background()
+
+	// a bucket outside of the bound policies can't be reached
+	_, err = batchClient.StartBatchJob(ctx, "expire:\n  apiVersion: v1\n  bucket: secrets\n")
+	if err == nil || !strings.Contains(err.Error(), "secrets") {
+		t.Fatalf("expected the batch job to be denied, got %v", err)
+	}
+	if len(adminServer.added) != 0 || len(adminServer.batchJobs) != 0 {
+		t.Fatalf("no batch job may be submitted outside of the policies")
+	}
+
+	// a bucket of the policies is reached with a temporary access key scoped to them
+	result, err := batchClient.StartBatchJob(ctx, "expire:\n  apiVersion: v1\n  bucket: memes\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ID != "batch-1" {
+		t.Errorf("unexpected batch job %+v", result)
+	}
+	if len(adminServer.added) != 1 || adminServer.added[0].Expiration == nil ||
+		!strings.Contains(string(adminServer.added[0].Policy), "arn:aws:s3:::memes/*") {
+		t.Fatalf("expected a temporary access key scoped to the policies, got %+v", adminServer.added)
+	}
+	if len(adminServer.batchJobs) != 1 || adminServer.batchJobs[0] != "jobkey" {
+		t.Errorf("expected the batch job to be submitted with the temporary access key, got %v", adminServer.batchJobs)
+	}
+}
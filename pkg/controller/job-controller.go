@@ -19,16 +19,16 @@ package controller
 import (
 	"context"
 	"fmt"
-	"reflect"
-	"sync"
 	"time"
 
+	"github.com/minio/madmin-go/v3"
 	"github.com/minio/minio-go/v7/pkg/set"
 	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 	stsv1beta1 "github.com/minio/operator/pkg/apis/sts.min.io/v1beta1"
 	jobinformers "github.com/minio/operator/pkg/client/informers/externalversions/job.min.io/v1alpha1"
 	joblisters "github.com/minio/operator/pkg/client/listers/job.min.io/v1alpha1"
+	"github.com/minio/operator/pkg/utils/cron"
 	"github.com/minio/operator/pkg/utils/miniojob"
 	batchjobv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -56,6 +56,11 @@ type JobController struct {
 	recorder          record.EventRecorder
 	workqueue         workqueue.RateLimitingInterface
 	k8sClient         client.Client
+	kubeClientSet     kubernetes.Interface
+	// tenantAdminClient gets an admin client for a tenant, with its root credentials
+	tenantAdminClient func(ctx context.Context, tenant *miniov2.Tenant) (*madmin.AdminClient, error)
+	// tenantUserAdminClient gets an admin client for a tenant, with the credentials of one of its users
+	tenantUserAdminClient func(tenant *miniov2.Tenant, accessKey, secretKey string) (*madmin.AdminClient, error)
 }
 
 // runWorker is a long-running function that will continually call the
@@ -93,20 +98,25 @@ func NewJobController(
 	minioJobInformer jobinformers.MinIOJobInformer,
 	jobInformer batchv1.JobInformer,
 	namespacesToWatch set.StringSet,
-	_ kubernetes.Interface,
+	kubeClientSet kubernetes.Interface,
 	recorder record.EventRecorder,
 	workqueue workqueue.RateLimitingInterface,
 	k8sClient client.Client,
+	tenantAdminClient func(ctx context.Context, tenant *miniov2.Tenant) (*madmin.AdminClient, error),
+	tenantUserAdminClient func(tenant *miniov2.Tenant, accessKey, secretKey string) (*madmin.AdminClient, error),
 ) *JobController {
 	controller := &JobController{
-		namespacesToWatch: namespacesToWatch,
-		minioJobLister:    minioJobInformer.Lister(),
-		minioJobHasSynced: minioJobInformer.Informer().HasSynced,
-		jobLister:         jobInformer.Lister(),
-		jobHasSynced:      jobInformer.Informer().HasSynced,
-		recorder:          recorder,
-		workqueue:         workqueue,
-		k8sClient:         k8sClient,
+		namespacesToWatch:     namespacesToWatch,
+		minioJobLister:        minioJobInformer.Lister(),
+		minioJobHasSynced:     minioJobInformer.Informer().HasSynced,
+		jobLister:             jobInformer.Lister(),
+		jobHasSynced:          jobInformer.Informer().HasSynced,
+		recorder:              recorder,
+		workqueue:             workqueue,
+		k8sClient:             k8sClient,
+		kubeClientSet:         kubeClientSet,
+		tenantAdminClient:     tenantAdminClient,
+		tenantUserAdminClient: tenantUserAdminClient,
 	}
 
 	// Set up an event handler for when resources change
@@ -126,31 +136,10 @@ func NewJobController(
 	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		UpdateFunc: func(_, new interface{}) {
 			newJob := new.(*batchjobv1.Job)
-			jobName, ok := newJob.Labels[miniojob.MinioJobName]
-			if !ok {
+			// the state of the commands is rebuilt from the jobs on every sync of the MinIOJob that owns them
+			if _, ok := newJob.Labels[miniojob.MinioJobCRName]; !ok {
 				return
 			}
-			jobCRName, ok := newJob.Labels[miniojob.MinioJobCRName]
-			if !ok {
-				return
-			}
-			val, ok := globalIntervalJobStatus.Load(fmt.Sprintf("%s/%s", newJob.GetNamespace(), jobCRName))
-			if ok {
-				intervalJob := val.(*miniojob.MinIOIntervalJob)
-				command, ok := intervalJob.CommandMap[jobName]
-				if ok {
-					if newJob.Status.Succeeded > 0 {
-						command.SetStatus(true, "")
-					} else {
-						for _, condition := range newJob.Status.Conditions {
-							if condition.Type == batchjobv1.JobFailed {
-								command.SetStatus(false, condition.Message)
-								break
-							}
-						}
-					}
-				}
-			}
 			controller.HandleObject(newJob)
 		},
 	})
@@ -202,23 +191,40 @@ func (c *JobController) SyncHandler(key string) (Result, error) {
 	err := c.k8sClient.Get(ctx, client.ObjectKeyFromObject(&jobCR), &jobCR)
 	if err != nil {
 		// job cr have gone
-		globalIntervalJobStatus.Delete(fmt.Sprintf("%s/%s", jobCR.Namespace, jobCR.Name))
 		if errors.IsNotFound(err) {
 			return WrapResult(Result{}, nil)
 		}
 		return WrapResult(Result{}, err)
 	}
 
-	if !IsSTSEnabled() {
-		c.recorder.Eventf(&jobCR, corev1.EventTypeWarning, "STSDisabled", "JobCR cannot work with STS disabled")
-		return WrapResult(Result{}, nil)
+	// the access key of a deleted job cr is revoked before it goes
+	if !jobCR.DeletionTimestamp.IsZero() {
+		return WrapResult(Result{}, c.revokeAccessKey(ctx, &jobCR))
 	}
 
-	// if job cr is Success, do nothing
-	if jobCR.Status.Phase == miniojob.MinioJobPhaseSuccess {
-		// delete the job status
-		globalIntervalJobStatus.Delete(fmt.Sprintf("%s/%s", jobCR.Namespace, jobCR.Name))
-		return WrapResult(Result{}, nil)
+	// a dry run only renders the commands, it needs neither the tenant nor STS
+	if jobCR.Spec.DryRun {
+		return c.syncDryRun(ctx, &jobCR)
+	}
+
+	if err = c.syncJobActions(ctx, &jobCR); err != nil {
+		return WrapResult(Result{}, err)
+	}
+
+	if !miniojob.UseAccessKey(&jobCR) {
+		// the job cr switched back to STS
+		if err = c.revokeAccessKey(ctx, &jobCR); err != nil {
+			return WrapResult(Result{}, err)
+		}
+		if !IsSTSEnabled() {
+			c.recorder.Eventf(&jobCR, corev1.EventTypeWarning, "STSDisabled", "JobCR cannot work with STS disabled, use the accessKey credential mode")
+			return WrapResult(Result{}, nil)
+		}
+	}
+
+	// if job cr is finished, only clean it up, unless it runs again on schedule
+	if miniojob.FinishedPhase(jobCR.Status.Phase) && jobCR.Spec.Schedule == "" {
+		return c.syncFinishedJob(ctx, &jobCR)
 	}
 	// get tenant
 	tenant := &miniov2.Tenant{
@@ -246,10 +252,18 @@ func (c *JobController) SyncHandler(key string) (Result, error) {
 	if len(pbs.Items) == 0 {
 		return WrapResult(Result{}, fmt.Errorf("no policybinding found"))
 	}
+	// the policybindings without tenant only apply while the tenant is alone in its namespace
+	tenants := &miniov2.TenantList{}
+	if err = c.k8sClient.List(ctx, tenants, client.InNamespace(tenant.Namespace)); err != nil {
+		return WrapResult(Result{}, err)
+	}
 	saFound := false
+	policies := []string{}
 	for _, pb := range pbs.Items {
-		if pb.Spec.Application.Namespace == namespace && pb.Spec.Application.ServiceAccount == jobCR.Spec.ServiceAccountName {
+		if pb.Spec.Application.Namespace == namespace && pb.Spec.Application.ServiceAccount == jobCR.Spec.ServiceAccountName &&
+			policyBindingAppliesToTenant(&pb, tenant.Name, len(tenants.Items)) {
 			saFound = true
+			policies = append(policies, pb.Spec.Policies...)
 		}
 	}
 	if !saFound {
@@ -257,8 +271,33 @@ func (c *JobController) SyncHandler(key string) (Result, error) {
 	}
 	intervalJob, err := checkMinIOJob(&jobCR)
 	if err != nil {
+		// an invalid job won't get fixed by reprocessing, report it until the spec is updated
+		c.recorder.Eventf(&jobCR, corev1.EventTypeWarning, "InvalidJob", "Invalid job: %v", err)
+		jobCR.Status.Phase = miniojob.MinioJobPhaseError
+		jobCR.Status.Message = fmt.Sprintf("Invalid job:%v", err)
+		err = c.updateJobStatus(ctx, &jobCR)
 		return WrapResult(Result{}, err)
 	}
+	intervalJob.Tenant = tenant
+	if err = c.loadJobState(ctx, &jobCR, intervalJob); err != nil {
+		return WrapResult(Result{}, err)
+	}
+	// the access key lives as long as the job cr has commands to run
+	if miniojob.UseAccessKey(&jobCR) {
+		if err = c.ensureAccessKey(ctx, &jobCR, tenant, policies); err != nil {
+			c.recorder.Eventf(&jobCR, corev1.EventTypeWarning, "AccessKeyFailed", "Unable to create the access key: %v", err)
+			return WrapResult(Result{}, err)
+		}
+	}
+	// batch commands run in the tenant itself, their progress decides which commands run next
+	if intervalJob.HasBatchCommands() {
+		if err = c.syncBatchJobs(ctx, tenant, &jobCR, intervalJob, policies); err != nil {
+			return WrapResult(Result{}, err)
+		}
+	}
+	if jobCR.Spec.Schedule != "" {
+		return c.syncScheduledJob(ctx, &jobCR, intervalJob)
+	}
 	err = intervalJob.CreateCommandJob(ctx, c.k8sClient, STSDefaultPort)
 	if err != nil {
 		jobCR.Status.Phase = miniojob.MinioJobPhaseError
@@ -267,29 +306,64 @@ func (c *JobController) SyncHandler(key string) (Result, error) {
 		return WrapResult(Result{}, err)
 	}
 	// update status
-	jobCR.Status = intervalJob.GetMinioJobStatus(ctx)
-	err = c.updateJobStatus(ctx, &jobCR)
-	return WrapResult(Result{}, err)
+	status := intervalJob.GetMinioJobStatus(ctx)
+	status.Run = jobCR.Status.Run
+	if miniojob.FinishedPhase(status.Phase) {
+		completionTime := metav1.Now()
+		status.CompletionTime = &completionTime
+	}
+	jobCR.Status = status
+	if err = c.updateJobStatus(ctx, &jobCR); err != nil {
+		return WrapResult(Result{}, err)
+	}
+	if miniojob.FinishedPhase(jobCR.Status.Phase) {
+		return c.syncFinishedJob(ctx, &jobCR)
+	}
+	return WrapResult(Result{RequeueAfter: commandsRequeueAfter(intervalJob, time.Now())}, nil)
+}
+
+// retryRequeueAfter gets how long to wait before retrying the next failed command, zero if none is waiting
+func retryRequeueAfter(intervalJob *miniojob.MinIOIntervalJob, now time.Time) time.Duration {
+	next := intervalJob.NextRetryTime()
+	if next.IsZero() {
+		return 0
+	}
+	if next.Before(now) {
+		return time.Second
+	}
+	return next.Sub(now) + time.Second
 }
 
 func (c *JobController) updateJobStatus(ctx context.Context, job *v1alpha1.MinIOJob) error {
 	return c.k8sClient.Status().Update(ctx, job)
 }
 
-func checkMinIOJob(jobCR *v1alpha1.MinIOJob) (intervalJob *miniojob.MinIOIntervalJob, err error) {
-	defer func() {
-		if err != nil {
-			globalIntervalJobStatus.Delete(fmt.Sprintf("%s/%s", jobCR.Namespace, jobCR.Name))
-		}
-	}()
-	val, found := globalIntervalJobStatus.Load(fmt.Sprintf("%s/%s", jobCR.Namespace, jobCR.Name))
-	if found {
-		intervalJob = val.(*miniojob.MinIOIntervalJob)
-		if reflect.DeepEqual(intervalJob.JobCR.Spec, jobCR.Spec) {
-			intervalJob.JobCR.UID = jobCR.UID
-			return intervalJob, nil
+// loadJobState rebuilds the state of the commands of the run being tracked from the status of the MinIOJob and
+// the Kubernetes Jobs it owns, so no state is kept in memory across syncs
+func (c *JobController) loadJobState(ctx context.Context, jobCR *v1alpha1.MinIOJob, intervalJob *miniojob.MinIOIntervalJob) error {
+	// a scheduled run, or a rerun
+	if jobCR.Status.Run != "" {
+		intervalJob.StartRun(jobCR.Status.Run)
+	}
+	jobs := &batchjobv1.JobList{}
+	err := c.k8sClient.List(ctx, jobs, client.InNamespace(jobCR.Namespace), client.MatchingLabels{
+		miniojob.MinioJobCRName: jobCR.Name,
+	})
+	if err != nil {
+		return err
+	}
+	ownedJobs := []batchjobv1.Job{}
+	for _, job := range jobs.Items {
+		// skip the jobs left behind by a previous MinIOJob with the same name, and the cancelled jobs
+		if metav1.IsControlledBy(&job, jobCR) && job.DeletionTimestamp.IsZero() {
+			ownedJobs = append(ownedJobs, job)
 		}
 	}
+	intervalJob.LoadState(jobCR.Status.CommandsStatus, ownedJobs)
+	return c.collectCommandOutput(ctx, jobCR, intervalJob, ownedJobs)
+}
+
+func checkMinIOJob(jobCR *v1alpha1.MinIOJob) (intervalJob *miniojob.MinIOIntervalJob, err error) {
 	intervalJob = &miniojob.MinIOIntervalJob{
 		JobCR:      jobCR.DeepCopy(),
 		Command:    []*miniojob.MinIOIntervalJobCommand{},
@@ -304,25 +378,23 @@ func checkMinIOJob(jobCR *v1alpha1.MinIOJob) (intervalJob *miniojob.MinIOInterva
 	if jobCR.Spec.ServiceAccountName == "" {
 		return intervalJob, fmt.Errorf("serviceaccount name is empty")
 	}
+	if jobCR.Spec.Schedule != "" {
+		if _, err = cron.ParseSchedule(jobCR.Spec.Schedule); err != nil {
+			return intervalJob, err
+		}
+	}
 	for index, val := range jobCR.Spec.Commands {
 		jobCommand, err := miniojob.GenerateMinIOIntervalJobCommand(val, index)
 		if err != nil {
 			return intervalJob, err
 		}
+		jobCommand.Policy = miniojob.MergePolicy(jobCR.Spec.CommandDefaults, val.CommandPolicy)
 		intervalJob.Command = append(intervalJob.Command, jobCommand)
 		intervalJob.CommandMap[jobCommand.JobName] = jobCommand
 	}
-	// check all dependon
-	for _, command := range intervalJob.Command {
-		for _, dep := range command.CommandSpec.DependsOn {
-			_, found := intervalJob.CommandMap[dep]
-			if !found {
-				return intervalJob, fmt.Errorf("dependent job %s not found", dep)
-			}
-		}
+	// check all dependon, and that they can be scheduled
+	if err = miniojob.ValidateCommands(jobCR.Spec.Execution, intervalJob.Command); err != nil {
+		return intervalJob, err
 	}
-	globalIntervalJobStatus.Store(fmt.Sprintf("%s/%s", jobCR.Namespace, jobCR.Name), intervalJob)
 	return intervalJob, nil
 }
-
-var globalIntervalJobStatus = sync.Map{}
@@ -0,0 +1,143 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	"github.com/minio/operator/pkg/runtime"
+	"github.com/minio/operator/pkg/utils/miniojob"
+	batchjobv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// podJobNameLabel is set on the pods of a Job by every supported Kubernetes version
+	podJobNameLabel = "job-name"
+	// maxPersistedOutputBytes is the size of the output of all the commands kept in the output Secret, under
+	// the 1MiB limit of a Secret
+	maxPersistedOutputBytes = 768 * 1024
+	// outputTruncated is appended to the persisted output that didn't fit in the Secret
+	outputTruncated = "\n[output truncated]"
+)
+
+// collectCommandOutput records how the commands of the tracked run executed from the pods of their jobs. The output
+// of a finished command is read once, then kept in the status of the MinIOJob after the pods are gone.
+func (c *JobController) collectCommandOutput(ctx context.Context, jobCR *v1alpha1.MinIOJob, intervalJob *miniojob.MinIOIntervalJob, jobs []batchjobv1.Job) error {
+	for i := range jobs {
+		job := &jobs[i]
+		if job.Labels[miniojob.MinioJobRun] != intervalJob.Run {
+			continue
+		}
+		command, found := intervalJob.CommandMap[job.Labels[miniojob.MinioJobName]]
+		if !found {
+			continue
+		}
+		attempt := miniojob.JobAttempt(job)
+		// only the latest attempt of the command is reported
+		if attempt < command.Attempts || outputCollected(jobCR, command.JobName, attempt) {
+			continue
+		}
+		pods := &corev1.PodList{}
+		err := c.k8sClient.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{
+			podJobNameLabel: job.Name,
+		})
+		if err != nil {
+			return err
+		}
+		command.LoadPodState(pods.Items)
+		pod := miniojob.LatestPod(pods.Items)
+		// the output of a command printing credentials is never read
+		if pod == nil || miniojob.JobCompletionTime(job) == nil || command.OutputHasCredentials() {
+			continue
+		}
+		// the termination message collected with the pod state is enough if the logs can't be read
+		tailLines := int64(miniojob.CommandOutputTailLines)
+		tail, err := c.podLogs(ctx, pod, &corev1.PodLogOptions{
+			Container: miniojob.CommandContainer,
+			TailLines: &tailLines,
+		})
+		if err != nil {
+			klog.Warningf("MinIOJob '%s/%s' failed to read the output of command %s: %v", jobCR.Namespace, jobCR.Name, command.JobName, err)
+			continue
+		}
+		command.SetOutput(tail)
+		if !jobCR.Spec.PersistOutput {
+			continue
+		}
+		limit := int64(maxPersistedOutputBytes / len(intervalJob.Command))
+		output, err := c.podLogs(ctx, pod, &corev1.PodLogOptions{
+			Container:  miniojob.CommandContainer,
+			LimitBytes: &limit,
+		})
+		if err != nil {
+			klog.Warningf("MinIOJob '%s/%s' failed to read the output of command %s: %v", jobCR.Namespace, jobCR.Name, command.JobName, err)
+			continue
+		}
+		if int64(len(output)) >= limit {
+			output += outputTruncated
+		}
+		if err = c.persistCommandOutput(ctx, jobCR, command.JobName, miniojob.RedactOutput(output)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outputCollected checks if the status of the MinIOJob already has the output of the finished attempt of the command
+func outputCollected(jobCR *v1alpha1.MinIOJob, command string, attempt int32) bool {
+	for _, commandStatus := range jobCR.Status.CommandsStatus {
+		if commandStatus.Name == command {
+			return commandStatus.Attempts >= attempt && commandStatus.CompletionTime != nil
+		}
+	}
+	return false
+}
+
+func (c *JobController) podLogs(ctx context.Context, pod *corev1.Pod, opts *corev1.PodLogOptions) (string, error) {
+	logs, err := c.kubeClientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(logs), nil
+}
+
+// persistCommandOutput stores the output of the command in the output Secret of the MinIOJob, the output of a
+// previous run of the command is replaced
+func (c *JobController) persistCommandOutput(ctx context.Context, jobCR *v1alpha1.MinIOJob, command, output string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      miniojob.OutputSecretName(jobCR.Name),
+			Namespace: jobCR.Namespace,
+			Labels: map[string]string{
+				miniojob.MinioJobCRName: jobCR.Name,
+			},
+		},
+	}
+	_, err := runtime.NewObjectSyncer(ctx, c.k8sClient, jobCR, func() error {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[command] = []byte(output)
+		return nil
+	}, secret, runtime.SyncTypeCreateOrUpdate).Sync(ctx)
+	return err
+}
@@ -0,0 +1,55 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	"github.com/minio/operator/pkg/utils/miniojob"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// PlanMinIOJob validates the MinIOJob and renders the commands it would run, in their execution order, without
+// creating anything
+func PlanMinIOJob(jobCR *v1alpha1.MinIOJob) (v1alpha1.MinIOJobStatus, error) {
+	intervalJob, err := checkMinIOJob(jobCR)
+	if err != nil {
+		return v1alpha1.MinIOJobStatus{}, err
+	}
+	return intervalJob.GetPlanStatus(), nil
+}
+
+// syncDryRun reports the plan of a MinIOJob in dry run mode in its status, no Kubernetes Job is created
+func (c *JobController) syncDryRun(ctx context.Context, jobCR *v1alpha1.MinIOJob) (Result, error) {
+	status, err := PlanMinIOJob(jobCR)
+	if err != nil {
+		c.recorder.Eventf(jobCR, corev1.EventTypeWarning, "InvalidJob", "Invalid job: %v", err)
+		status.Phase = miniojob.MinioJobPhaseError
+		status.Message = fmt.Sprintf("Invalid job:%v", err)
+	}
+	// keep the record of the scheduled runs done before the dry run
+	status.LastScheduleTime = jobCR.Status.LastScheduleTime
+	status.History = jobCR.Status.History
+	if equality.Semantic.DeepEqual(status, jobCR.Status) {
+		return WrapResult(Result{}, nil)
+	}
+	jobCR.Status = status
+	return WrapResult(Result{}, c.updateJobStatus(ctx, jobCR))
+}
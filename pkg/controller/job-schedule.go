@@ -0,0 +1,219 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	"github.com/minio/operator/pkg/utils/cron"
+	"github.com/minio/operator/pkg/utils/miniojob"
+	batchjobv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultSuccessfulRunsHistoryLimit is the number of successful scheduled runs kept by default
+	defaultSuccessfulRunsHistoryLimit = 3
+	// defaultFailedRunsHistoryLimit is the number of failed scheduled runs kept by default
+	defaultFailedRunsHistoryLimit = 1
+)
+
+// syncScheduledJob starts a new run of the MinIOJob commands on every tick of its schedule, and keeps the history
+// of the finished runs
+func (c *JobController) syncScheduledJob(ctx context.Context, jobCR *v1alpha1.MinIOJob, intervalJob *miniojob.MinIOIntervalJob) (Result, error) {
+	schedule, err := cron.ParseSchedule(jobCR.Spec.Schedule)
+	if err != nil {
+		return WrapResult(Result{}, err)
+	}
+	now := time.Now().UTC()
+
+	running := intervalJob.Run != "" && !miniojob.FinishedPhase(jobCR.Status.Phase)
+
+	lastScheduleTime := jobCR.CreationTimestamp.Time
+	if jobCR.Status.LastScheduleTime != nil {
+		lastScheduleTime = jobCR.Status.LastScheduleTime.Time
+	}
+	if tick := schedule.LastTick(lastScheduleTime, now); !tick.IsZero() {
+		startRun := true
+		if running {
+			if status := intervalJob.GetMinioJobStatus(ctx); status.Phase == miniojob.MinioJobPhaseRunning {
+				switch jobCR.Spec.ConcurrencyPolicy {
+				case v1alpha1.ReplaceConcurrent:
+					c.cancelBatchJobs(ctx, jobCR, intervalJob)
+					if err = c.deleteRunJobs(ctx, jobCR, intervalJob.Run); err != nil {
+						return WrapResult(Result{}, err)
+					}
+					status.Phase = miniojob.MinioJobPhaseFailed
+					status.Message = fmt.Sprintf("Replaced by run %s", miniojob.RunName(tick))
+					c.recordRun(ctx, jobCR, intervalJob.Run, status, now)
+				default:
+					startRun = false
+					c.recorder.Eventf(jobCR, corev1.EventTypeWarning, "RunSkipped", "Run %s skipped, run %s is still running", miniojob.RunName(tick), intervalJob.Run)
+				}
+			}
+		}
+		scheduleTime := metav1.NewTime(tick)
+		jobCR.Status.LastScheduleTime = &scheduleTime
+		if startRun {
+			run := miniojob.RunName(tick)
+			intervalJob.StartRun(run)
+			jobCR.Status.Run = run
+			running = true
+			klog.Infof("MinIOJob '%s/%s' starting scheduled run %s", jobCR.Namespace, jobCR.Name, run)
+			c.recorder.Eventf(jobCR, corev1.EventTypeNormal, "RunStarted", "Scheduled run %s started", run)
+		}
+	}
+
+	if running {
+		if err = intervalJob.CreateCommandJob(ctx, c.k8sClient, STSDefaultPort); err != nil {
+			jobCR.Status.Phase = miniojob.MinioJobPhaseError
+			jobCR.Status.Message = fmt.Sprintf("Create job error:%v", err)
+			return WrapResult(Result{}, c.updateJobStatus(ctx, jobCR))
+		}
+		status := intervalJob.GetMinioJobStatus(ctx)
+		jobCR.Status.Phase = status.Phase
+		jobCR.Status.CommandsStatus = status.CommandsStatus
+		jobCR.Status.Message = status.Message
+		if status.Phase != miniojob.MinioJobPhaseRunning {
+			c.recordRun(ctx, jobCR, intervalJob.Run, status, now)
+		}
+	}
+
+	var requeueAfter time.Duration
+	jobCR.Status.NextScheduleTime = nil
+	if next := schedule.Next(now); !next.IsZero() {
+		nextScheduleTime := metav1.NewTime(next)
+		jobCR.Status.NextScheduleTime = &nextScheduleTime
+		// wake up right after the next tick
+		requeueAfter = next.Sub(now) + time.Second
+	}
+	if retryAfter := commandsRequeueAfter(intervalJob, now); retryAfter > 0 && (requeueAfter == 0 || retryAfter < requeueAfter) {
+		requeueAfter = retryAfter
+	}
+	expireAfter, err := c.expireRunJobs(ctx, jobCR, now)
+	if err != nil {
+		klog.Errorf("MinIOJob '%s/%s' failed to delete the jobs of expired runs: %v", jobCR.Namespace, jobCR.Name, err)
+	}
+	if expireAfter > 0 && (requeueAfter == 0 || expireAfter < requeueAfter) {
+		requeueAfter = expireAfter
+	}
+	if err = c.updateJobStatus(ctx, jobCR); err != nil {
+		return WrapResult(Result{}, err)
+	}
+	return WrapResult(Result{RequeueAfter: requeueAfter}, nil)
+}
+
+// runRecorded checks if the run is already in the history of the MinIOJob
+func runRecorded(jobCR *v1alpha1.MinIOJob, run string) bool {
+	for _, record := range jobCR.Status.History {
+		if record.Name == run {
+			return true
+		}
+	}
+	return false
+}
+
+// recordRun adds a finished run to the history of the MinIOJob, pruning the runs over the history limits
+func (c *JobController) recordRun(ctx context.Context, jobCR *v1alpha1.MinIOJob, run string, status v1alpha1.MinIOJobStatus, now time.Time) {
+	if runRecorded(jobCR, run) {
+		return
+	}
+	completionTime := metav1.NewTime(now)
+	record := v1alpha1.MinIOJobRun{
+		Name:           run,
+		CompletionTime: &completionTime,
+		Phase:          status.Phase,
+		Message:        status.Message,
+		CommandsStatus: status.CommandsStatus,
+	}
+	if scheduleTime, err := miniojob.RunScheduleTime(run); err == nil {
+		record.ScheduleTime = &metav1.Time{Time: scheduleTime}
+	}
+	switch status.Phase {
+	case miniojob.MinioJobPhaseSuccess:
+		c.recorder.Eventf(jobCR, corev1.EventTypeNormal, "RunSucceeded", "Scheduled run %s succeeded", run)
+	case miniojob.MinioJobPhaseCancelled:
+		c.recorder.Eventf(jobCR, corev1.EventTypeNormal, "RunCancelled", "Scheduled run %s cancelled", run)
+	default:
+		c.recorder.Eventf(jobCR, corev1.EventTypeWarning, "RunFailed", "Scheduled run %s failed: %s", run, status.Message)
+	}
+	history, pruned := pruneRunHistory(append([]v1alpha1.MinIOJobRun{record}, jobCR.Status.History...),
+		historyLimit(jobCR.Spec.SuccessfulRunsHistoryLimit, defaultSuccessfulRunsHistoryLimit),
+		historyLimit(jobCR.Spec.FailedRunsHistoryLimit, defaultFailedRunsHistoryLimit))
+	jobCR.Status.History = history
+	for _, prunedRun := range pruned {
+		if err := c.deleteRunJobs(ctx, jobCR, prunedRun.Name); err != nil {
+			klog.Errorf("MinIOJob '%s/%s' failed to delete jobs of run %s: %v", jobCR.Namespace, jobCR.Name, prunedRun.Name, err)
+		}
+	}
+}
+
+func historyLimit(limit *int32, defaultLimit int) int {
+	if limit == nil {
+		return defaultLimit
+	}
+	return int(*limit)
+}
+
+// pruneRunHistory keeps the most recent successful and failed runs within their limits, the history is ordered
+// from the most recent to the oldest run
+func pruneRunHistory(history []v1alpha1.MinIOJobRun, successfulLimit, failedLimit int) (kept, pruned []v1alpha1.MinIOJobRun) {
+	successful, failed := 0, 0
+	for _, record := range history {
+		if record.Phase == miniojob.MinioJobPhaseSuccess {
+			successful++
+			if successful > successfulLimit {
+				pruned = append(pruned, record)
+				continue
+			}
+		} else {
+			failed++
+			if failed > failedLimit {
+				pruned = append(pruned, record)
+				continue
+			}
+		}
+		kept = append(kept, record)
+	}
+	return kept, pruned
+}
+
+// deleteRunJobs deletes the Kubernetes Jobs created for a scheduled run
+func (c *JobController) deleteRunJobs(ctx context.Context, jobCR *v1alpha1.MinIOJob, run string) error {
+	jobs := &batchjobv1.JobList{}
+	err := c.k8sClient.List(ctx, jobs, client.InNamespace(jobCR.Namespace), client.MatchingLabels{
+		miniojob.MinioJobCRName: jobCR.Name,
+		miniojob.MinioJobRun:    run,
+	})
+	if err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		err = c.k8sClient.Delete(ctx, &jobs.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	"github.com/minio/operator/pkg/utils/miniojob"
+)
+
+func Test_pruneRunHistory(t *testing.T) {
+	history := []v1alpha1.MinIOJobRun{
+		{Name: "6", Phase: miniojob.MinioJobPhaseSuccess},
+		{Name: "5", Phase: miniojob.MinioJobPhaseFailed},
+		{Name: "4", Phase: miniojob.MinioJobPhaseSuccess},
+		{Name: "3", Phase: miniojob.MinioJobPhaseFailed},
+		{Name: "2", Phase: miniojob.MinioJobPhaseSuccess},
+		{Name: "1", Phase: miniojob.MinioJobPhaseSuccess},
+	}
+	names := func(runs []v1alpha1.MinIOJobRun) []string {
+		out := []string{}
+		for _, run := range runs {
+			out = append(out, run.Name)
+		}
+		return out
+	}
+	kept, pruned := pruneRunHistory(history, 2, 1)
+	if got := names(kept); !reflect.DeepEqual(got, []string{"6", "5", "4"}) {
+		t.Errorf("pruneRunHistory() kept %v", got)
+	}
+	if got := names(pruned); !reflect.DeepEqual(got, []string{"3", "2", "1"}) {
+		t.Errorf("pruneRunHistory() pruned %v", got)
+	}
+	kept, pruned = pruneRunHistory(history, 0, 0)
+	if len(kept) != 0 || len(pruned) != len(history) {
+		t.Errorf("pruneRunHistory() expected everything pruned, kept %v", names(kept))
+	}
+}
@@ -112,11 +112,25 @@ const (
 	StatusUpdatingMinIOVersion       = "Updating MinIO Version"
 	StatusUpdatingKES                = "Updating KES"
 	StatusNotOwned                   = "Statefulset not controlled by operator"
-	StatusFailedAlreadyExists        = "Another MinIO Tenant already exists in the namespace"
+	StatusFailedAlreadyExists        = "Another MinIO Tenant in the namespace already uses the service name"
 	StatusTenantCredentialsNotSet    = "Tenant credentials are not set properly"
 	StatusInconsistentMinIOVersions  = "Different versions across MinIO Pools"
+	StatusUpgradePreflightFailed     = "MinIO Upgrade Pre-flight Checks Failed"
+	StatusMinIOUpgradeRolledBack     = "MinIO Upgrade Rolled Back"
+	StatusVerifyingMinIOUpgrade      = "Verifying MinIO Upgrade"
+	StatusRollingBackMinIOUpgrade    = "Rolling Back MinIO Upgrade"
+	StatusImageVerificationFailed    = "MinIO Image Verification Failed"
+	StatusWaitingMaintenanceWindow   = "Waiting for Maintenance Window"
 	StatusRestartingMinIO            = "Restarting MinIO"
 	StatusDecommissioningNotAllowed  = "Pool Decommissioning Not Allowed"
+	StatusDecommissioningPool        = "Decommissioning Pool"
+	StatusDecommissioningFailed      = "Pool Decommissioning Failed"
+	StatusReplacingPool              = "Replacing Pool"
+	StatusPoolReplacementNotAllowed  = "Pool Replacement Not Allowed"
+	StatusDeletingTenant             = "Deleting Tenant"
+	StatusPaused                     = "Reconciliation Paused"
+	StatusHibernated                 = "Hibernated"
+	StatusResumingFromHibernation    = "Resuming from Hibernation"
 )
 
 // ErrMinIONotReady is the error returned when MinIO is not Ready
@@ -125,6 +139,13 @@ var ErrMinIONotReady = fmt.Errorf("MinIO is not ready")
 // ErrMinIORestarting is the error returned when MinIO is restarting
 var ErrMinIORestarting = fmt.Errorf("MinIO is restarting")
 
+// ErrPoolDecommissioning is returned while a removed pool is being decommissioned, the tenant is synced again once
+// the decommission progressed rather than reported as failing
+var ErrPoolDecommissioning = fmt.Errorf("MinIO is decommissioning a pool")
+
+// ErrImageVerificationFailed is the error returned when the image of an upgrade fails the verification policy
+var ErrImageVerificationFailed = fmt.Errorf("MinIO image verification failed")
+
 // Controller struct watches the Kubernetes API for changes to Tenant resources
 type Controller struct {
 	// podName is the identifier of this instance
@@ -186,6 +207,9 @@ type Controller struct {
 	// STS API server instance
 	sts *http.Server
 
+	// Admission webhook server instance
+	admission *http.Server
+
 	// Client transport
 	transport *http.Transport
 
@@ -215,6 +239,7 @@ type EventType int
 // Possible values of EventType
 const (
 	STSServerNotification EventType = iota
+	AdmissionServerNotification
 )
 
 // EventNotification - structure to send messages through a channel regarding a error event to be handled
@@ -289,17 +314,19 @@ func NewController(
 		hostsTemplate:             hostsTemplate,
 		operatorVersion:           operatorVersion,
 		policyBindingListerSynced: policyBindingInformer.Informer().HasSynced,
-		controllers: []*JobController{
-			NewJobController(
-				minioJobInformer,
-				jobInformer,
-				namespacesToWatch,
-				kubeClientSet,
-				recorder,
-				queue.NewRateLimitingQueueWithConfig(MinIOControllerRateLimiter(), queue.RateLimitingQueueConfig{Name: "MinioJobs"}),
-				k8sClient,
-			),
-		},
+	}
+	controller.controllers = []*JobController{
+		NewJobController(
+			minioJobInformer,
+			jobInformer,
+			namespacesToWatch,
+			kubeClientSet,
+			recorder,
+			queue.NewRateLimitingQueueWithConfig(MinIOControllerRateLimiter(), queue.RateLimitingQueueConfig{Name: "MinioJobs"}),
+			k8sClient,
+			controller.getTenantAdminClient,
+			controller.getTenantUserAdminClient,
+		),
 	}
 
 	// Initialize operator HTTP upgrade server handlers
@@ -308,6 +335,9 @@ func NewController(
 	// Initialize STS API server handlers
 	controller.sts = configureSTSServer(controller)
 
+	// Initialize admission webhook server handlers
+	controller.admission = configureAdmissionServer()
+
 	klog.Info("Setting up event handlers")
 	// Set up an event handler for when Tenant resources change
 	tenantInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -488,10 +518,33 @@ func leaderRun(ctx context.Context, c *Controller, threadiness int, stopCh <-cha
 		}()
 	}
 
+	// 3) we need to make sure we have admission webhook certificates (if enabled) before registering the webhook
+	if IsAdmissionWebhookEnabled() {
+		go func() {
+			if IsAdmissionWebhookAutoTLSEnabled() {
+				klog.Infof("Admission webhook Autocert is enabled, starting certificate setup.")
+				c.generateAdmissionTLSCert()
+			} else {
+				c.waitAdmissionTLSCert()
+			}
+			if err := c.syncWebhookConfigurations(ctx); err != nil {
+				klog.Errorf("Unable to register the admission webhook: %v", err)
+			}
+		}()
+	} else if err := c.deleteWebhookConfigurations(ctx); err != nil {
+		klog.Errorf("Unable to remove the admission webhook: %v", err)
+	}
+
 	for {
 		select {
 		case oerr := <-notificationChannel:
-			if !errors.Is(oerr.Err, http.ErrServerClosed) {
+			if errors.Is(oerr.Err, http.ErrServerClosed) {
+				break
+			}
+			if oerr.Type == AdmissionServerNotification {
+				klog.Errorf("Admission webhook Server stopped: %v, going to restart", oerr.Err)
+				go c.startAdmissionServer(ctx, notificationChannel)
+			} else {
 				klog.Errorf("STS API Server stopped: %v, going to restart", oerr.Err)
 				go c.startSTSAPIServer(ctx, notificationChannel)
 			}
@@ -564,6 +617,14 @@ func (c *Controller) Start(threadiness int, stopCh <-chan struct{}) error {
 		klog.Info("STS Api server is not enabled, not starting")
 	}
 
+	if IsAdmissionWebhookEnabled() {
+		// the admission webhook is served even if the pod is not the leader
+		klog.Info("Waiting for admission webhook to start")
+		go c.startAdmissionServer(ctx, notificationChannel)
+	} else {
+		klog.Info("Admission webhook is not enabled, not starting")
+	}
+
 	// start the leader election code loop
 	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
 		Lock: lock,
@@ -634,6 +695,7 @@ func (c *Controller) Stop() {
 	tctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	_ = c.us.Shutdown(tctx)
 	_ = c.sts.Shutdown(tctx)
+	_ = c.admission.Shutdown(tctx)
 	cancel()
 
 	klog.Info("Stopping the minio controller")
@@ -677,15 +739,12 @@ func (c *Controller) updateServer(
 	totalAvailableReplicas int32,
 	adminClnt *madmin.AdminClient,
 	updateURL string,
-) error {
+) (string, error) {
 	result, err := adminClnt.ServerUpdateV2(ctx, madmin.ServerUpdateOpts{UpdateURL: updateURL})
 	if err != nil {
 		if madmin.ToErrorResponse(err).Code != "MethodNotAllowed" {
-			if _, terr := c.updateTenantStatus(ctx, tenant, err.Error(), totalAvailableReplicas); terr != nil {
-				return terr
-			}
 			// Update failed, nothing needs to be changed in the container
-			return err
+			return "", err
 		}
 		c.recorder.Event(
 			tenant,
@@ -693,7 +752,7 @@ func (c *Controller) updateServer(
 			"Inplace update is disabled, falling back to performing only statefulset update.",
 			fmt.Sprintf("Tenant %s", tenant.Name),
 		)
-		return nil
+		return "", nil
 	}
 
 	reduceErrors := func(results []madmin.ServerPeerUpdateStatus) (err error) {
@@ -725,14 +784,12 @@ func (c *Controller) updateServer(
 	}
 
 	if err := reduceErrors(result.Results); err != nil {
-		if _, terr := c.updateTenantStatus(ctx, tenant, err.Error(), totalAvailableReplicas); terr != nil {
-			return terr
-		}
 		// Update failed, nothing needs to be changed in the container
-		return err
+		return "", err
 	}
 
-	if updated, currentVersion, updatedVersion := isUpdated(result.Results); !updated {
+	updated, currentVersion, updatedVersion := isUpdated(result.Results)
+	if !updated {
 		// In case the upgrade is from an older version to RELEASE.2021-07-27T02-40-15Z (which introduced
 		// MinIO server integrated with Console), we need to delete the old console deployment and service.
 		// We do this only when MinIO server is successfully updated.
@@ -740,12 +797,12 @@ func (c *Controller) updateServer(
 		newVer, err := miniov2.ReleaseTagToReleaseTime(updatedVersion)
 		if err != nil {
 			klog.Errorf("Unsupported release tag on new image, server updated but might leave dangling console deployment %v", err)
-			return err
+			return currentVersion, err
 		}
 		consoleDeployment, err := c.deploymentLister.Deployments(tenant.Namespace).Get(tenant.ConsoleDeploymentName())
 		if unifiedConsoleReleaseTime.Before(newVer) && consoleDeployment != nil && err == nil {
 			if err := c.deleteOldConsoleDeployment(ctx, tenant, consoleDeployment.Name); err != nil {
-				return err
+				return currentVersion, err
 			}
 		}
 		klog.Infof("Tenant '%s' MinIO updated successfully from: %s, to: %s successfully",
@@ -758,10 +815,10 @@ func (c *Controller) updateServer(
 		)
 		klog.Info(msg)
 		if _, terr := c.updateTenantStatus(ctx, tenant, msg, totalAvailableReplicas); terr != nil {
-			return terr
+			return currentVersion, terr
 		}
 	}
-	return nil
+	return currentVersion, nil
 }
 
 // syncHandler compares the actual state with the desired, and attempts to
@@ -804,6 +861,34 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 		return WrapResult(Result{RequeueAfter: time.Second * 5}, nil)
 	}
 
+	// a deleted tenant is only cleaned up according to its deletion policy
+	if !tenant.DeletionTimestamp.IsZero() {
+		return c.syncTenantDeletion(ctx, tenant)
+	}
+	// a paused tenant is left as it is until it's resumed
+	if tenant.Spec.Paused {
+		return c.syncPausedTenant(ctx, tenant)
+	}
+	if tenant.Status.CurrentState == StatusPaused {
+		klog.Infof("'%s' reconciliation resumed", key)
+		c.recorder.Event(tenant, corev1.EventTypeNormal, "ReconciliationResumed", "Tenant reconciliation resumed")
+	}
+	if tenant, err = c.ensureTenantFinalizer(ctx, tenant); err != nil {
+		return WrapResult(Result{}, err)
+	}
+
+	// a hibernated tenant keeps its volumes but runs no pods
+	if tenant.Spec.Hibernate {
+		return c.syncHibernatedTenant(ctx, tenant)
+	}
+	if tenant, err = c.resumeHibernatedTenant(ctx, tenant); err != nil {
+		return WrapResult(Result{}, err)
+	}
+	if tenant.Status.CurrentState == StatusResumingFromHibernation && !tenant.MinIOHealthCheck(c.getTransport()) {
+		klog.Infof("'%s' waiting for MinIO to resume", key)
+		return WrapResult(Result{RequeueAfter: time.Second * 5}, nil)
+	}
+
 	// Check the Sync Version to see if the tenant needs upgrade
 	if tenant, err = c.checkForUpgrades(ctx, tenant); err != nil {
 		return WrapResult(Result{}, err)
@@ -832,6 +917,10 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 
 	// Check if we are decommissioning a pool before we ensure defaults, as that would populate a defaulted pool name
 	tenant, err = c.checkForPoolDecommission(ctx, key, tenant, tenantConfiguration)
+	if errors.Is(err, ErrPoolDecommissioning) {
+		// nothing failed, MinIO is still moving the data out of the removed pools
+		return WrapResult(Result{RequeueAfter: decommissionCheckInterval}, nil)
+	}
 	if err != nil {
 		return WrapResult(Result{}, err)
 	}
@@ -900,36 +989,24 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 	// validate services
 	// Check MinIO S3 Endpoint Service
 	err = c.checkMinIOSvc(ctx, tenant, nsName)
-	if err != nil {
-		klog.V(2).Infof("error consolidating minio service: %s", err.Error())
-		return WrapResult(Result{}, err)
+	if err == nil {
+		// Check MinIO Headless Service used for internode communication
+		err = c.checkMinIOHLSvc(ctx, tenant, nsName)
 	}
-
-	// Check MinIO Headless Service used for internode communication
-	err = c.checkMinIOHLSvc(ctx, tenant, nsName)
-	if err != nil {
-		klog.V(2).Infof("error consolidating headless service: %s", err.Error())
-		return WrapResult(Result{}, err)
+	if errors.Is(err, ErrServiceOwnedByAnotherTenant) {
+		// Several tenants live in the namespace, they must not share their services
+		c.recorder.Eventf(tenant, corev1.EventTypeWarning, "ServiceConflict", "Service %s", err)
+		if _, err = c.updateTenantStatus(ctx, tenant, StatusFailedAlreadyExists, 0); err != nil {
+			return WrapResult(Result{}, err)
+		}
+		// return nil so we don't re-queue this work item
+		return WrapResult(Result{}, nil)
 	}
-
-	// List all MinIO Tenants in this namespace.
-	li, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).List(context.Background(), metav1.ListOptions{})
 	if err != nil {
+		klog.V(2).Infof("error consolidating minio services: %s", err.Error())
 		return WrapResult(Result{}, err)
 	}
 
-	// Only 1 minio tenant per namespace allowed.
-	if len(li.Items) > 1 {
-		for _, t := range li.Items {
-			if t.Status.CurrentState != StatusInitialized {
-				if _, err = c.updateTenantStatus(ctx, &t, StatusFailedAlreadyExists, 0); err != nil {
-					return WrapResult(Result{}, err)
-				}
-				// return nil so we don't re-queue this work item
-				return WrapResult(Result{}, err)
-			}
-		}
-	}
 	// Create Tenant Services Accoutns for Tenant
 	err = c.checkAndCreateServiceAccount(ctx, tenant)
 	if err != nil {
@@ -978,6 +1055,25 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 		klog.Info("Detected we are updating a legacy tenant deployment")
 	}
 
+	// Replace the pools whose geometry changed, one at a time
+	tenant, err = c.checkForPoolReplacement(ctx, key, tenant, tenantConfiguration)
+	if errors.Is(err, ErrPoolDecommissioning) {
+		return WrapResult(Result{RequeueAfter: decommissionCheckInterval}, nil)
+	}
+	if err != nil {
+		return WrapResult(Result{}, err)
+	}
+
+	// Disruptive changes are deferred until a maintenance window opens
+	windowOpen, nextWindow, err := maintenanceWindowOpen(tenant.Spec.MaintenanceWindows, time.Now())
+	if err != nil {
+		if _, terr := c.updateTenantStatus(ctx, tenant, err.Error(), totalAvailableReplicas); terr != nil {
+			return WrapResult(Result{}, terr)
+		}
+		return WrapResult(Result{}, err)
+	}
+	var pendingChanges []string
+
 	// Check if this is fresh setup not an expansion.
 	// addingNewPool := len(tenant.Spec.Pools) == len(tenant.Status.Pools)
 	addingNewPool := false
@@ -994,25 +1090,18 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 
 	// Check if we need to create any of the pools. It's important not to update the statefulsets
 	// in this loop because we need all the pools "as they are" for the hot-update below
-	for i, pool := range tenant.Spec.Pools {
-		// Get the StatefulSet with the name specified in Tenant.status.pools[i].SSName
-
-		// if this index is in the status of pools use it, else capture the desired name in the status and store it
-		ssName := tenant.PoolStatefulsetName(&pool)
+	for _, pool := range tenant.Spec.Pools {
+		// Get the StatefulSet serving the pool, if it's in the status of pools use it, else capture
+		// the desired name in the status and store it
+		ssName := tenant.StatefulsetNameForPool(&pool)
 
-		poolInStatus := false
-		for _, poolStatus := range tenant.Status.Pools {
-			if poolStatus.SSName == ssName {
-				poolInStatus = true
-				break
-			}
-		}
-
-		if !poolInStatus {
+		pi := poolStatusIndex(tenant, ssName)
+		if pi < 0 {
 			tenant.Status.Pools = append(tenant.Status.Pools, miniov2.PoolStatus{
 				SSName: ssName,
 				State:  miniov2.PoolNotCreated,
 			})
+			pi = len(tenant.Status.Pools) - 1
 			// push updates to status
 			if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
 				return WrapResult(Result{}, err)
@@ -1028,10 +1117,11 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 				Tenant:          tenant,
 				SkipEnvVars:     skipEnvVars,
 				Pool:            &pool,
-				PoolStatus:      &tenant.Status.Pools[i],
+				PoolStatus:      &tenant.Status.Pools[pi],
 				ServiceName:     tenant.MinIOHLServiceName(),
 				HostsTemplate:   c.hostsTemplate,
 				OperatorVersion: c.operatorVersion,
+				Image:           pinnedImage(tenant),
 			})
 			ss, err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Create(ctx, ss, cOpts)
 			if err != nil {
@@ -1039,7 +1129,7 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 			}
 			c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolCreated", fmt.Sprintf("Tenant pool %s created", pool.Name))
 			// Report the pool is properly created
-			tenant.Status.Pools[i].State = miniov2.PoolCreated
+			tenant.Status.Pools[pi].State = miniov2.PoolCreated
 			// mark we are adding a new pool to the next block can act accordingly
 			addingNewPool = true
 			// push updates to status
@@ -1053,9 +1143,14 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 		images = append(images, ss.Spec.Template.Spec.Containers[0].Image)
 	}
 
-	var initializedPool miniov2.Pool
+	// a pool being replaced keeps serving until its replacement is initialized, restart it to pick the new pool
+	initializedPool := initializedReplacedPool(tenant)
 	// validate each pool if it's initialized, and mark it if it is.
-	for pi, pool := range tenant.Spec.Pools {
+	for _, pool := range tenant.Spec.Pools {
+		pi := poolStatusIndex(tenant, tenant.StatefulsetNameForPool(&pool))
+		if pi < 0 {
+			return WrapResult(Result{}, fmt.Errorf("pool %s is not in the status", pool.Name))
+		}
 		// get a pod for the established statefulset
 		if tenant.Status.Pools[pi].State == miniov2.PoolInitialized {
 			initializedPool = pool
@@ -1066,6 +1161,10 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 		// Only restart if there is an existing initialized pool, if there are no initialized
 		// pools no need to restart.
 		if initializedPool.Name != "" && addingNewPool {
+			if !windowOpen {
+				pendingChanges = append(pendingChanges, fmt.Sprintf("restart of MinIO to add pool %s", pool.Name))
+				return WrapResult(c.waitForMaintenanceWindow(ctx, tenant, pendingChanges, nextWindow, totalAvailableReplicas))
+			}
 			// Restart services to get new args since we are expanding the deployment here.
 			if err := c.restartInitializedPool(ctx, tenant, initializedPool, tenantConfiguration); err != nil {
 				klog.Infof("'%s' restart call failed", key)
@@ -1074,6 +1173,13 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 			metaNowTime := metav1.Now()
 			tenant.Status.WaitingOnReady = &metaNowTime
 			tenant.Status.CurrentState = StatusRestartingMinIO
+			// once all pools are initialized, move the existing data onto the new pool as well, a pool replacement
+			// moves the data of the replaced pool with its decommission instead
+			if rebalanceEnabled(tenant) && !poolReplacementInProgress(tenant) && (tenant.Status.Rebalance == nil || tenant.Status.Rebalance.State != miniov2.PoolRebalanceRunning) {
+				tenant.Status.Rebalance = &miniov2.PoolRebalanceStatus{
+					State: miniov2.PoolRebalancePending,
+				}
+			}
 			if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
 				klog.Infof("'%s' Can't update tenant status: %v", key, err)
 				return WrapResult(Result{}, err)
@@ -1139,8 +1245,9 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 
 	// In loop above we compared all the versions in all pools.
 	// So comparing tenant.Spec.Image (version to update to) against one value from images slice is fine.
-	ssImages := strings.Split(images[0], ":")
-	specImages := strings.Split(tenant.Spec.Image, ":")
+	// the pools may run the image pinned to the digest it was verified with
+	ssImages := strings.Split(strings.Split(images[0], "@")[0], ":")
+	specImages := strings.Split(strings.Split(tenant.Spec.Image, "@")[0], ":")
 	var ssImage string
 	var specImage string
 	if len(specImages) > 1 {
@@ -1149,11 +1256,44 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 	if len(ssImages) > 1 {
 		ssImage = ssImages[1]
 	}
-	if specImage != ssImage && tenant.Status.CurrentState != StatusUpdatingMinIOVersion {
+	// the state reported once the tenant is reconciled, and when to check the upgrade again
+	finalState := StatusInitialized
+	var upgradeResult Result
+	// the pools keep the image they run while the upgrade is verified, rolled back or rejected
+	poolImage := pinnedImage(tenant)
+	upgrading := specImage != ssImage && tenant.Status.CurrentState != StatusUpdatingMinIOVersion
+	step := upgradeStart
+	if upgrading {
+		if tenant, step, upgradeResult, err = c.syncUpgradeProgress(ctx, tenant, adminClnt, totalAvailableReplicas); err != nil {
+			return WrapResult(upgradeResult, err)
+		}
+		if step == upgradeHold {
+			poolImage, finalState = images[0], tenant.Status.CurrentState
+		}
+	}
+	if upgrading && step == upgradeStart {
+		if !windowOpen {
+			// the pools would roll to the new image as well, wait for the window before touching them
+			pendingChanges = append(pendingChanges, fmt.Sprintf("upgrade of MinIO to %s", tenant.Spec.Image))
+			return WrapResult(c.waitForMaintenanceWindow(ctx, tenant, pendingChanges, nextWindow, totalAvailableReplicas))
+		}
 		if !tenant.MinIOHealthCheck(c.getTransport()) {
 			klog.Infof("%s is not running can't update image online", key)
 			return WrapResult(Result{}, ErrMinIONotReady)
 		}
+		if strategy := tenant.Spec.UpgradeStrategy; strategy != nil && strategy.PreflightChecks {
+			if err = c.upgradePreflightChecks(ctx, tenant, adminClnt); err != nil {
+				klog.Infof("%s postponing the MinIO upgrade to %s: %v", key, tenant.Spec.Image, err)
+				if tenant.Status.CurrentState != StatusUpgradePreflightFailed {
+					c.recorder.Event(tenant, corev1.EventTypeWarning, "UpgradePreflightFailed",
+						fmt.Sprintf("MinIO upgrade to %s postponed: %v", tenant.Spec.Image, err))
+				}
+				if _, err = c.updateTenantStatus(ctx, tenant, StatusUpgradePreflightFailed, totalAvailableReplicas); err != nil {
+					return WrapResult(Result{}, err)
+				}
+				return WrapResult(Result{RequeueAfter: time.Minute}, nil)
+			}
+		}
 
 		// Images different with the newer state change, continue to verify
 		// if upgrade is possible
@@ -1165,7 +1305,17 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 		klog.V(4).Infof("Collecting artifacts for Tenant '%s' to update MinIO from: %s, to: %s",
 			tenantName, images[0], tenant.Spec.Image)
 
-		latest, err := c.fetchArtifacts(tenant)
+		latest, digest, err := c.fetchArtifacts(tenant)
+		if errors.Is(err, ErrImageVerificationFailed) {
+			// the upgrade is rejected, the next reconciliations keep the pools on their image until the image, the
+			// policy, the upgrade source or the cosign public key changes
+			c.removeArtifacts()
+			klog.Warningf("%s rejecting the MinIO upgrade to %s: %v", key, tenant.Spec.Image, err)
+			if _, err = c.rejectUpgrade(ctx, tenant, images[0], err, totalAvailableReplicas); err != nil {
+				return WrapResult(Result{}, err)
+			}
+			return WrapResult(Result{}, nil)
+		}
 		if err != nil {
 			// Do not remove assets with errors, keep them for investigation.
 			return WrapResult(Result{}, err)
@@ -1190,56 +1340,71 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 		klog.V(4).Infof("Updating Tenant %s MinIO version from: %s, to: %s -> URL: %s",
 			tenantName, tenant.Spec.Image, images[0], updateURL)
 
-		if err := c.updateServer(
+		if tenant, err = c.startUpgradeRecord(ctx, tenant, images[0], latest, digest); err != nil {
+			return WrapResult(Result{}, err)
+		}
+		fromVersion, err := c.updateServer(
 			ctx,
 			tenantName,
 			tenant,
 			totalAvailableReplicas,
 			adminClnt,
 			updateURL,
-		); err != nil {
-			return WrapResult(Result{}, err)
+		)
+		if err != nil {
+			_, result, err := c.failUpgrade(ctx, tenant, fromVersion, err, totalAvailableReplicas)
+			return WrapResult(result, err)
 		}
-
-		for i, pool := range tenant.Spec.Pools {
-			// Now proceed to make the yaml changes for the tenant statefulset.
-			ss := statefulsets.NewPool(&statefulsets.NewPoolArgs{
-				Tenant:          tenant,
-				SkipEnvVars:     skipEnvVars,
-				Pool:            &pool,
-				PoolStatus:      &tenant.Status.Pools[i],
-				ServiceName:     tenant.MinIOHLServiceName(),
-				HostsTemplate:   c.hostsTemplate,
-				OperatorVersion: c.operatorVersion,
-			})
-			if _, err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Update(ctx, ss, uOpts); err != nil {
+		if fromVersion != "" && tenant.Spec.UpgradeStrategy != nil {
+			// the binary was swapped in-place, the statefulsets are updated once every server reports the new release
+			now := metav1.Now()
+			if tenant, err = c.setUpgradeRecord(ctx, tenant, fromVersion, miniov2.UpgradeVerifying, "", func(record *miniov2.UpgradeRecord) {
+				record.UpdateTime = &now
+			}); err != nil {
+				return WrapResult(Result{}, err)
+			}
+			if tenant, err = c.updateTenantStatus(ctx, tenant, StatusVerifyingMinIOUpgrade, totalAvailableReplicas); err != nil {
+				return WrapResult(Result{}, err)
+			}
+			poolImage, finalState = images[0], StatusVerifyingMinIOUpgrade
+			upgradeResult = Result{RequeueAfter: upgradeVerificationInterval}
+		} else {
+			for _, pool := range tenant.Spec.Pools {
+				// Now proceed to make the yaml changes for the tenant statefulset.
+				ss := statefulsets.NewPool(&statefulsets.NewPoolArgs{
+					Tenant:          tenant,
+					SkipEnvVars:     skipEnvVars,
+					Pool:            &pool,
+					PoolStatus:      tenant.PoolStatusForPool(&pool),
+					ServiceName:     tenant.MinIOHLServiceName(),
+					HostsTemplate:   c.hostsTemplate,
+					OperatorVersion: c.operatorVersion,
+					Image:           pinnedImage(tenant),
+				})
+				if _, err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Update(ctx, ss, uOpts); err != nil {
+					return WrapResult(Result{}, err)
+				}
+				c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolUpdated", fmt.Sprintf("Tenant pool %s updated", pool.Name))
+			}
+			if tenant, err = c.completeUpgradeRecord(ctx, tenant, fromVersion, miniov2.UpgradeSucceeded, ""); err != nil {
 				return WrapResult(Result{}, err)
 			}
-			c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolUpdated", fmt.Sprintf("Tenant pool %s updated", pool.Name))
 		}
-
 	}
 
 	// This loop will take care of updating the statefulset for each pool
-	for i, pool := range tenant.Spec.Pools {
-		// Get the StatefulSet with the name specified in Tenant.status.pools[i].SSName
-		// if this index is in the status of pools use it, else capture the desired name in the status and store it
-		ssName := tenant.PoolStatefulsetName(&pool)
-
-		poolInStatus := false
-		for _, poolStatus := range tenant.Status.Pools {
-			if poolStatus.SSName == ssName {
-				poolInStatus = true
-				break
-			}
-		}
+	for _, pool := range tenant.Spec.Pools {
+		// Get the StatefulSet serving the pool, if it's in the status of pools use it, else capture
+		// the desired name in the status and store it
+		ssName := tenant.StatefulsetNameForPool(&pool)
 
-		if !poolInStatus {
-			ssName = tenant.PoolStatefulsetName(&pool)
+		pi := poolStatusIndex(tenant, ssName)
+		if pi < 0 {
 			tenant.Status.Pools = append(tenant.Status.Pools, miniov2.PoolStatus{
 				SSName: ssName,
 				State:  miniov2.PoolNotCreated,
 			})
+			pi = len(tenant.Status.Pools) - 1
 			// push updates to status
 			if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
 				return WrapResult(Result{}, err)
@@ -1251,27 +1416,47 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 			klog.Errorf("%s's pool %s doesn't exist: %v", tenant.Name, ssName, err)
 			return WrapResult(Result{}, err)
 		}
-		if pool.Servers != *existingStatefulSet.Spec.Replicas {
-			// warn the user that replica count of an existing pool can't be changed
-			if tenant, err = c.updateTenantStatus(ctx, tenant, fmt.Sprintf("Can't modify server count for pool %s", pool.Name), 0); err != nil {
+
+		// If the StatefulSet is not controlled by this Tenant resource, we should log
+		// a warning to the event recorder and return before anything is changed or deferred for it
+		if !metav1.IsControlledBy(existingStatefulSet, tenant) {
+			if tenant, err = c.updateTenantStatus(ctx, tenant, StatusNotOwned, existingStatefulSet.Status.Replicas); err != nil {
 				return WrapResult(Result{}, err)
 			}
+			msg := fmt.Sprintf(MessageResourceExists, existingStatefulSet.Name)
+			c.recorder.Event(tenant, corev1.EventTypeWarning, ErrResourceExists, msg)
+			// return nil so we don't re-queue this work item, this error won't get fixed by reprocessing
+			return WrapResult(Result{}, nil)
+		}
+		if servers, volumes := statefulSetGeometry(existingStatefulSet, &pool); pool.Servers != servers || pool.VolumesPerServer != volumes {
+			// the geometry of an existing pool can't be changed in place, the pool has to be replaced
+			if !tenant.Spec.AllowPoolReplacement {
+				if tenant, err = c.updateTenantStatus(ctx, tenant, fmt.Sprintf("Can't modify server count for pool %s, set allowPoolReplacement to replace the pool", pool.Name), 0); err != nil {
+					return WrapResult(Result{}, err)
+				}
+			}
+			continue
 		}
 		// generated the expected StatefulSet based on the new tenant configuration
 		expectedStatefulSet := statefulsets.NewPool(&statefulsets.NewPoolArgs{
 			Tenant:          tenant,
 			SkipEnvVars:     skipEnvVars,
 			Pool:            &pool,
-			PoolStatus:      &tenant.Status.Pools[i],
+			PoolStatus:      &tenant.Status.Pools[pi],
 			ServiceName:     tenant.MinIOHLServiceName(),
 			HostsTemplate:   c.hostsTemplate,
 			OperatorVersion: c.operatorVersion,
+			Image:           poolImage,
 		})
 		// Verify if this pool matches the spec on the tenant (resources, affinity, sidecars, etc)
 		poolMatchesSS, err := poolSSMatchesSpec(expectedStatefulSet, existingStatefulSet)
 		if err != nil {
 			return WrapResult(Result{}, err)
 		}
+		if !poolMatchesSS && !windowOpen {
+			pendingChanges = append(pendingChanges, fmt.Sprintf("rollout of pool %s", pool.Name))
+			continue
+		}
 		// if the pool doesn't match the spec
 		if !poolMatchesSS {
 			// for legacy reasons, if the zone label is present in SS we must carry it over
@@ -1297,18 +1482,6 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 				return WrapResult(Result{RequeueAfter: time.Second * 5}, nil)
 			}
 		}
-
-		// If the StatefulSet is not controlled by this Tenant resource, we should log
-		// a warning to the event recorder and ret
-		if !metav1.IsControlledBy(existingStatefulSet, tenant) {
-			if tenant, err = c.updateTenantStatus(ctx, tenant, StatusNotOwned, existingStatefulSet.Status.Replicas); err != nil {
-				return WrapResult(Result{}, err)
-			}
-			msg := fmt.Sprintf(MessageResourceExists, existingStatefulSet.Name)
-			c.recorder.Event(tenant, corev1.EventTypeWarning, ErrResourceExists, msg)
-			// return nil so we don't re-queue this work item, this error won't get fixed by reprocessing
-			return WrapResult(Result{}, nil)
-		}
 	}
 
 	// Handle PVC expansion
@@ -1337,6 +1510,14 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 		return WrapResult(Result{RequeueAfter: time.Second * 5}, nil)
 	}
 
+	// Start, stop or track the rebalance of the pools after an expansion
+	if rebalancedTenant, err := c.checkForPoolRebalance(ctx, key, tenant, adminClnt); err != nil {
+		// show the error and continue, the rebalance is retried on the next sync
+		klog.Infof("'%s' pool rebalance: %v", key, err)
+	} else {
+		tenant = rebalancedTenant
+	}
+
 	// Ensure we are only provisioning users one time
 	if !tenant.Status.ProvisionedUsers && len(tenant.Spec.Users) > 0 {
 		if err := c.createUsers(ctx, tenant, tenantConfiguration); err != nil {
@@ -1360,9 +1541,24 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 		}
 	}
 
+	// Keep waiting for the maintenance window while the rollout of some pools is deferred
+	if len(pendingChanges) > 0 {
+		if err = c.CreateOrUpdatePDB(ctx, tenant); err != nil {
+			return WrapResult(Result{}, err)
+		}
+		result, err := c.waitForMaintenanceWindow(ctx, tenant, pendingChanges, nextWindow, totalAvailableReplicas)
+		if upgradeResult.RequeueAfter > 0 && (result.RequeueAfter == 0 || upgradeResult.RequeueAfter < result.RequeueAfter) {
+			result = upgradeResult
+		}
+		return WrapResult(result, err)
+	}
+	if tenant, err = c.clearPendingChanges(ctx, tenant); err != nil {
+		return WrapResult(Result{}, err)
+	}
+
 	// Finally, we update the status block of the Tenant resource to reflect the
 	// current state of the world
-	tenant, err = c.updateTenantStatus(ctx, tenant, StatusInitialized, totalAvailableReplicas)
+	tenant, err = c.updateTenantStatus(ctx, tenant, finalState, totalAvailableReplicas)
 
 	// Create Or Update PDB for tenant
 	err = c.CreateOrUpdatePDB(ctx, tenant)
@@ -1370,7 +1566,7 @@ func (c *Controller) syncHandler(key string) (Result, error) {
 		return WrapResult(Result{}, err)
 	}
 
-	return WrapResult(Result{}, err)
+	return WrapResult(upgradeResult, err)
 }
 
 // enqueueTenant takes a Tenant resource and converts it into a namespace/name
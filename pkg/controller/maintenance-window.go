@@ -0,0 +1,102 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	"github.com/minio/operator/pkg/utils/cron"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// maintenanceWindowOpen returns true if disruptive changes can be applied at the given time, either because the tenant
+// has no maintenance windows or because one of them is open, otherwise it returns when the next window opens
+func maintenanceWindowOpen(windows []miniov2.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	if len(windows) == 0 {
+		return true, time.Time{}, nil
+	}
+	var next time.Time
+	for i, window := range windows {
+		schedule, err := cron.ParseSchedule(window.Schedule)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("maintenance window %d: %w", i, err)
+		}
+		if window.Duration.Duration <= 0 {
+			return false, time.Time{}, fmt.Errorf("maintenance window %d: duration must be positive", i)
+		}
+		location := time.UTC
+		if window.TimeZone != "" {
+			if location, err = time.LoadLocation(window.TimeZone); err != nil {
+				return false, time.Time{}, fmt.Errorf("maintenance window %d: %w", i, err)
+			}
+		}
+		local := now.In(location)
+		// the window is open if it opened less than its duration ago
+		if start := schedule.Next(local.Add(-window.Duration.Duration)); !start.IsZero() && !start.After(local) {
+			return true, time.Time{}, nil
+		}
+		if start := schedule.Next(local); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return false, next, nil
+}
+
+// waitForMaintenanceWindow reports the disruptive changes as pending until the next maintenance window opens, the
+// tenant is synced again at that time
+func (c *Controller) waitForMaintenanceWindow(ctx context.Context, tenant *miniov2.Tenant, changes []string, next time.Time, totalAvailableReplicas int32) (Result, error) {
+	var nextWindow *metav1.Time
+	if !next.IsZero() {
+		nextWindow = &metav1.Time{Time: next}
+	}
+	if !slices.Equal(tenant.Status.PendingChanges, changes) || !tenant.Status.NextMaintenanceWindow.Equal(nextWindow) {
+		tenant.Status.PendingChanges = changes
+		tenant.Status.NextMaintenanceWindow = nextWindow
+		var err error
+		if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
+			return Result{}, err
+		}
+		message := fmt.Sprintf("Pending %s, no upcoming maintenance window", strings.Join(changes, ", "))
+		if nextWindow != nil {
+			message = fmt.Sprintf("Pending %s, next maintenance window at %s", strings.Join(changes, ", "), next.Format(time.RFC3339))
+		}
+		klog.Infof("'%s/%s' %s", tenant.Namespace, tenant.Name, message)
+		c.recorder.Event(tenant, corev1.EventTypeNormal, "ChangesPending", message)
+	}
+	if _, err := c.updateTenantStatus(ctx, tenant, StatusWaitingMaintenanceWindow, totalAvailableReplicas); err != nil {
+		return Result{}, err
+	}
+	if nextWindow == nil {
+		return Result{}, nil
+	}
+	return Result{RequeueAfter: time.Until(next)}, nil
+}
+
+// clearPendingChanges removes the pending changes from the status once they were applied
+func (c *Controller) clearPendingChanges(ctx context.Context, tenant *miniov2.Tenant) (*miniov2.Tenant, error) {
+	if tenant.Status.PendingChanges == nil && tenant.Status.NextMaintenanceWindow == nil {
+		return tenant, nil
+	}
+	tenant.Status.PendingChanges = nil
+	tenant.Status.NextMaintenanceWindow = nil
+	return c.updatePoolStatus(ctx, tenant)
+}
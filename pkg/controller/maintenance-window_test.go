@@ -0,0 +1,137 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	miniofake "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_maintenanceWindowOpen(t *testing.T) {
+	// saturday 2024-06-15 at 03:30 UTC
+	now := time.Date(2024, time.June, 15, 3, 30, 0, 0, time.UTC)
+	window := func(schedule, duration, timeZone string) miniov2.MaintenanceWindow {
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return miniov2.MaintenanceWindow{Schedule: schedule, Duration: metav1.Duration{Duration: d}, TimeZone: timeZone}
+	}
+	tests := []struct {
+		name     string
+		windows  []miniov2.MaintenanceWindow
+		wantOpen bool
+		wantNext time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "No windows",
+			wantOpen: true,
+		},
+		{
+			name:     "Window open",
+			windows:  []miniov2.MaintenanceWindow{window("0 2 * * sat", "4h", "")},
+			wantOpen: true,
+		},
+		{
+			name:     "Window closed",
+			windows:  []miniov2.MaintenanceWindow{window("0 2 * * sat", "1h", "")},
+			wantNext: time.Date(2024, time.June, 22, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Earliest of several windows",
+			windows:  []miniov2.MaintenanceWindow{window("0 2 * * sat", "1h", ""), window("0 22 * * *", "2h", "")},
+			wantNext: time.Date(2024, time.June, 15, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Window in a time zone",
+			// 05:30 in Paris, the window opened at 05:00
+			windows:  []miniov2.MaintenanceWindow{window("0 5 * * *", "1h", "Europe/Paris")},
+			wantOpen: true,
+		},
+		{
+			name:    "Invalid schedule",
+			windows: []miniov2.MaintenanceWindow{window("0 2 * *", "1h", "")},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid time zone",
+			windows: []miniov2.MaintenanceWindow{window("0 2 * * *", "1h", "Mars/Olympus")},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid duration",
+			windows: []miniov2.MaintenanceWindow{window("0 2 * * *", "0s", "")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, next, err := maintenanceWindowOpen(tt.windows, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("maintenanceWindowOpen() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if open != tt.wantOpen || !next.Equal(tt.wantNext) {
+				t.Errorf("maintenanceWindowOpen() = %v, %v, want %v, %v", open, next, tt.wantOpen, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestWaitForMaintenanceWindow(t *testing.T) {
+	ctx := context.Background()
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+		Status:     miniov2.TenantStatus{CurrentState: StatusInitialized},
+	}
+	c := &Controller{
+		minioClientSet: miniofake.NewSimpleClientset(tenant),
+		recorder:       record.NewFakeRecorder(10),
+	}
+	next := time.Now().Add(time.Hour).Truncate(time.Minute)
+
+	result, err := c.waitForMaintenanceWindow(ctx, tenant, []string{"rollout of pool pool-0"}, next, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+		t.Errorf("expected the tenant to be synced again when the window opens, got %v", result.RequeueAfter)
+	}
+	updated, err := c.minioClientSet.MinioV2().Tenants("tenant-ns").Get(ctx, "myminio", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.CurrentState != StatusWaitingMaintenanceWindow {
+		t.Errorf("expected state %q, got %q", StatusWaitingMaintenanceWindow, updated.Status.CurrentState)
+	}
+	if len(updated.Status.PendingChanges) != 1 || updated.Status.NextMaintenanceWindow == nil || !updated.Status.NextMaintenanceWindow.Time.Equal(next) {
+		t.Errorf("expected the pending change and the next window in the status, got %v at %v", updated.Status.PendingChanges, updated.Status.NextMaintenanceWindow)
+	}
+
+	if updated, err = c.clearPendingChanges(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.PendingChanges != nil || updated.Status.NextMaintenanceWindow != nil {
+		t.Errorf("expected the pending changes to be cleared, got %v at %v", updated.Status.PendingChanges, updated.Status.NextMaintenanceWindow)
+	}
+}
@@ -17,6 +17,7 @@ package controller
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 	"github.com/minio/operator/pkg/resources/services"
@@ -28,6 +29,10 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// ErrServiceOwnedByAnotherTenant is the error returned when a service of the tenant is already owned by another tenant
+// of the namespace
+var ErrServiceOwnedByAnotherTenant = errors.New("service is owned by another tenant")
+
 // checkMinIOSvc validates the existence of the MinIO service and validate it's status against what the specification
 // states
 func (c *Controller) checkMinIOSvc(ctx context.Context, tenant *miniov2.Tenant, nsName types.NamespacedName) error {
@@ -50,6 +55,9 @@ func (c *Controller) checkMinIOSvc(ctx context.Context, tenant *miniov2.Tenant,
 			return err
 		}
 	}
+	if !metav1.IsControlledBy(svc, tenant) {
+		return fmt.Errorf("%s: %w", svc.Name, ErrServiceOwnedByAnotherTenant)
+	}
 
 	// compare any other change from what is specified on the tenant, since some of the state of the service is saved
 	// on the service.spec we will compare individual parts
@@ -145,6 +153,9 @@ func (c *Controller) checkMinIOHLSvc(ctx context.Context, tenant *miniov2.Tenant
 			return err
 		}
 	}
+	if !metav1.IsControlledBy(hlSvc, tenant) {
+		return fmt.Errorf("%s: %w", hlSvc.Name, ErrServiceOwnedByAnotherTenant)
+	}
 	// compare the current version of the service to what we expect
 	expectedHlSvc := services.NewHeadlessForMinIO(tenant)
 	// does the current service matches our specification?
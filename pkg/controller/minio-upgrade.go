@@ -0,0 +1,460 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maxUpgradeHistory is the number of MinIO upgrades kept in the tenant status
+	maxUpgradeHistory = 10
+	// upgradeVerificationTimeout is how long the operator waits for every server to report the new release
+	upgradeVerificationTimeout = 5 * time.Minute
+	// upgradeVerificationInterval is how often the servers are asked for their release while verifying an upgrade
+	upgradeVerificationInterval = 10 * time.Second
+	// upgradeRejectedInterval is how often the cosign public key of a rejected upgrade is checked for changes
+	upgradeRejectedInterval = time.Minute
+)
+
+// upgradePreflightChecks checks the tenant can go through a MinIO upgrade: MinIO must be healthy, no drive may be
+// healing and every erasure set must keep the quorum margin of the upgrade strategy
+func (c *Controller) upgradePreflightChecks(ctx context.Context, tenant *miniov2.Tenant, adminClnt *madmin.AdminClient) error {
+	aClnt, err := madmin.NewAnonymousClient(tenant.MinIOServerHostAddress(), tenant.TLS())
+	if err != nil {
+		return err
+	}
+	aClnt.SetCustomTransport(c.getTransport())
+
+	hctx, hcancel := context.WithTimeout(ctx, 60*time.Second)
+	defer hcancel()
+	health, err := aClnt.Healthy(hctx, madmin.HealthOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to get cluster health: %w", err)
+	}
+	storageInfo, err := adminClnt.StorageInfo(hctx)
+	if err != nil {
+		return fmt.Errorf("failed to get storage info: %w", err)
+	}
+	return checkUpgradePreflight(tenant.Spec.UpgradeStrategy, health, storageInfo.Disks)
+}
+
+// checkUpgradePreflight returns why the cluster can't be upgraded, nil if it can
+func checkUpgradePreflight(strategy *miniov2.UpgradeStrategy, health madmin.HealthResult, disks []madmin.Disk) error {
+	if !health.Healthy {
+		return errors.New("MinIO is not healthy")
+	}
+	if health.HealingDrives > 0 {
+		return fmt.Errorf("%d drives are healing", health.HealingDrives)
+	}
+	var margin int32
+	if strategy != nil {
+		margin = strategy.QuorumMargin
+	}
+	type erasureSet struct{ pool, set int }
+	online := map[erasureSet]int{}
+	for _, disk := range disks {
+		set := erasureSet{pool: disk.PoolIndex, set: disk.SetIndex}
+		if _, ok := online[set]; !ok {
+			online[set] = 0
+		}
+		if disk.State == madmin.DriveStateOk {
+			online[set]++
+		}
+	}
+	for set, drives := range online {
+		if drives-health.WriteQuorum < int(margin) {
+			return fmt.Errorf("erasure set %d of pool %d has %d drives online for a write quorum of %d, %d more required",
+				set.set+1, set.pool+1, drives, health.WriteQuorum, margin)
+		}
+	}
+	return nil
+}
+
+// upgradeStep is what the reconciliation does about the upgrade to the spec image
+type upgradeStep int
+
+const (
+	// upgradeStart runs the upgrade to the spec image
+	upgradeStart upgradeStep = iota
+	// upgradeHold keeps the pools on the image they run while the upgrade is verified or rolled back, or once it was
+	// rolled back
+	upgradeHold
+	// upgradeVerified updates the pools to the spec image, every server runs its release
+	upgradeVerified
+)
+
+// syncUpgradeProgress moves the upgrade to the spec image one step further, the verification and the rollback don't
+// block the reconciliation, they are requeued until they complete
+func (c *Controller) syncUpgradeProgress(ctx context.Context, tenant *miniov2.Tenant, adminClnt *madmin.AdminClient, totalAvailableReplicas int32) (*miniov2.Tenant, upgradeStep, Result, error) {
+	record := lastUpgradeRecord(&tenant.Status)
+	if record == nil || record.ToImage != tenant.Spec.Image {
+		return tenant, upgradeStart, Result{}, nil
+	}
+	switch record.Result {
+	case miniov2.UpgradeRolledBack:
+		// keep the pools on the previous image until the image is changed again
+		tenant, err := c.updateTenantStatus(ctx, tenant, StatusMinIOUpgradeRolledBack, totalAvailableReplicas)
+		return tenant, upgradeHold, Result{}, err
+	case miniov2.UpgradeVerifying:
+		return c.verifyUpgrade(ctx, tenant, adminClnt, totalAvailableReplicas)
+	case miniov2.UpgradeRollingBack:
+		return c.rollbackUpgrade(ctx, tenant, adminClnt, totalAvailableReplicas)
+	case miniov2.UpgradeRejected:
+		hash, err := c.imageVerificationHash(ctx, tenant)
+		if err != nil {
+			return nil, upgradeHold, Result{}, err
+		}
+		if hash != record.VerificationHash {
+			// verify the image again with the new policy, upgrade source or cosign public key
+			return tenant, upgradeStart, Result{}, nil
+		}
+		// the cosign public key isn't watched, check it again after a while
+		tenant, err = c.updateTenantStatus(ctx, tenant, StatusImageVerificationFailed, totalAvailableReplicas)
+		return tenant, upgradeHold, Result{RequeueAfter: upgradeRejectedInterval}, err
+	case miniov2.UpgradeSucceeded:
+		// the statefulsets may not be updated yet
+		return tenant, upgradeVerified, Result{}, nil
+	}
+	return tenant, upgradeStart, Result{}, nil
+}
+
+// verifyUpgrade checks whether every MinIO server reports the release of the upgrade, it's checked again after a while
+// until the verification times out and the upgrade fails
+func (c *Controller) verifyUpgrade(ctx context.Context, tenant *miniov2.Tenant, adminClnt *madmin.AdminClient, totalAvailableReplicas int32) (*miniov2.Tenant, upgradeStep, Result, error) {
+	record := lastUpgradeRecord(&tenant.Status)
+	vctx, cancel := context.WithTimeout(ctx, upgradeVerificationInterval)
+	defer cancel()
+	info, err := adminClnt.ServerInfo(vctx)
+	if err == nil {
+		err = checkServersRelease(info.Servers, record.ToVersion)
+	}
+	if err == nil {
+		tenant, err = c.completeUpgradeRecord(ctx, tenant, "", miniov2.UpgradeSucceeded, "")
+		return tenant, upgradeVerified, Result{}, err
+	}
+	if record.UpdateTime != nil && time.Since(record.UpdateTime.Time) < upgradeVerificationTimeout {
+		klog.V(2).Infof("'%s/%s' waiting for MinIO to report release %s: %v", tenant.Namespace, tenant.Name, record.ToVersion, err)
+		return tenant, upgradeHold, Result{RequeueAfter: upgradeVerificationInterval}, nil
+	}
+	tenant, result, err := c.failUpgrade(ctx, tenant, "", fmt.Errorf("MinIO didn't report release %s after the upgrade: %w", record.ToVersion, err), totalAvailableReplicas)
+	return tenant, upgradeHold, result, err
+}
+
+// checkServersRelease returns an error listing the servers that are offline or not running the given release
+func checkServersRelease(servers []madmin.ServerProperties, release string) error {
+	expected, err := miniov2.ReleaseTagToReleaseTime(release)
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		return errors.New("no server reported its release")
+	}
+	var messages []string
+	for _, server := range servers {
+		if server.State != string(madmin.ItemOnline) {
+			messages = append(messages, fmt.Sprintf("host %s: %s", server.Endpoint, server.State))
+			continue
+		}
+		version, err := miniov2.ReleaseTagToReleaseTime(server.Version)
+		if err != nil || !version.Equal(expected) {
+			messages = append(messages, fmt.Sprintf("host %s: running %s", server.Endpoint, server.Version))
+		}
+	}
+	if messages != nil {
+		return errors.New(strings.Join(messages, ";"))
+	}
+	return nil
+}
+
+// rollbackUpgrade restarts the next MinIO pod still running the release of the upgrade, the in-place upgrade only
+// replaced the binary inside the running containers and a restarted pod runs the binary of the image of the
+// statefulsets again. The pods are restarted one at a time and once none is left the upgrade is rolled back.
+func (c *Controller) rollbackUpgrade(ctx context.Context, tenant *miniov2.Tenant, adminClnt *madmin.AdminClient, totalAvailableReplicas int32) (*miniov2.Tenant, upgradeStep, Result, error) {
+	record := lastUpgradeRecord(&tenant.Status)
+	pods, err := c.kubeClientSet.CoreV1().Pods(tenant.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", miniov2.TenantLabel, tenant.Name),
+	})
+	if err != nil {
+		return nil, upgradeHold, Result{}, err
+	}
+	var poolPods []corev1.Pod
+	for _, pod := range pods.Items {
+		owner := metav1.GetControllerOf(&pod)
+		if owner == nil || poolStatusIndex(tenant, owner.Name) < 0 {
+			continue
+		}
+		poolPods = append(poolPods, pod)
+	}
+	// without the release of the servers every pod started before the upgrade is restarted
+	var servers []madmin.ServerProperties
+	sctx, cancel := context.WithTimeout(ctx, upgradeVerificationInterval)
+	defer cancel()
+	if info, err := adminClnt.ServerInfo(sctx); err == nil {
+		servers = info.Servers
+	}
+
+	pod, wait := nextRollbackPod(poolPods, servers, record)
+	if wait || pod != nil {
+		if pod != nil {
+			klog.Infof("'%s/%s' rolling back the MinIO upgrade to %s, restarting pod %s", tenant.Namespace, tenant.Name, record.ToImage, pod.Name)
+			if err = c.kubeClientSet.CoreV1().Pods(tenant.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+				return nil, upgradeHold, Result{}, err
+			}
+		}
+		return tenant, upgradeHold, Result{RequeueAfter: upgradeVerificationInterval}, nil
+	}
+
+	message := record.Message
+	if tenant, err = c.completeUpgradeRecord(ctx, tenant, "", miniov2.UpgradeRolledBack, message); err != nil {
+		return nil, upgradeHold, Result{}, err
+	}
+	c.recorder.Event(tenant, corev1.EventTypeWarning, "UpgradeRolledBack",
+		fmt.Sprintf("MinIO upgrade to %s failed and was rolled back: %s", tenant.Spec.Image, message))
+	if tenant, err = c.updateTenantStatus(ctx, tenant, StatusMinIOUpgradeRolledBack, totalAvailableReplicas); err != nil {
+		return nil, upgradeHold, Result{}, err
+	}
+	return tenant, upgradeHold, Result{}, nil
+}
+
+// nextRollbackPod returns the pod to restart next to roll back the upgrade: a pod started before MinIO swapped its
+// binary whose server reports the release of the upgrade, or whose release is unknown. A pod started before the swap
+// whose server reports another release never changed release and is kept. While a restarted pod isn't ready yet no
+// other pod is restarted.
+func nextRollbackPod(pods []corev1.Pod, servers []madmin.ServerProperties, record *miniov2.UpgradeRecord) (next *corev1.Pod, wait bool) {
+	releases := map[string]string{}
+	for _, server := range servers {
+		if server.State != string(madmin.ItemOnline) {
+			continue
+		}
+		// the endpoint of a server is the host of its pod, <pod>.<headless service>.<namespace>...
+		host := strings.SplitN(server.Endpoint, ".", 2)[0]
+		releases[host] = server.Version
+	}
+	upgraded, _ := miniov2.ReleaseTagToReleaseTime(record.ToVersion)
+
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			return nil, true
+		}
+		swapped := record.UpdateTime == nil || pod.CreationTimestamp.Before(record.UpdateTime)
+		if !swapped {
+			if !podReady(pod) {
+				return nil, true
+			}
+			continue
+		}
+		if next != nil {
+			continue
+		}
+		release, ok := releases[pod.Name]
+		if !ok {
+			next = pod
+			continue
+		}
+		if version, err := miniov2.ReleaseTagToReleaseTime(release); err != nil || version.Equal(upgraded) {
+			next = pod
+		}
+	}
+	return next, false
+}
+
+// podReady returns true if the pod reports it's ready
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// startUpgradeRecord adds the upgrade to the spec image to the upgrade history, an upgrade interrupted before
+// completing is picked up again
+func (c *Controller) startUpgradeRecord(ctx context.Context, tenant *miniov2.Tenant, fromImage, toVersion, digest string) (*miniov2.Tenant, error) {
+	toImage := tenant.Spec.Image
+	return c.updateUpgradeHistory(ctx, tenant, func(status *miniov2.TenantStatus) {
+		if record := lastUpgradeRecord(status); record != nil && record.Result == miniov2.UpgradeInProgress && record.ToImage == toImage {
+			record.Digest = digest
+			return
+		}
+		now := metav1.Now()
+		status.UpgradeHistory = appendUpgradeRecord(status.UpgradeHistory, miniov2.UpgradeRecord{
+			FromImage: fromImage,
+			ToImage:   toImage,
+			ToVersion: toVersion,
+			Digest:    digest,
+			Result:    miniov2.UpgradeInProgress,
+			StartTime: &now,
+		})
+	})
+}
+
+// rejectUpgrade records the upgrade to the spec image was rejected by the image verification policy, it isn't
+// attempted again until the policy, the upgrade source or the cosign public key changes
+func (c *Controller) rejectUpgrade(ctx context.Context, tenant *miniov2.Tenant, fromImage string, rejectErr error, totalAvailableReplicas int32) (*miniov2.Tenant, error) {
+	hash, err := c.imageVerificationHash(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	toImage := tenant.Spec.Image
+	tenant, err = c.updateUpgradeHistory(ctx, tenant, func(status *miniov2.TenantStatus) {
+		now := metav1.Now()
+		status.UpgradeHistory = appendUpgradeRecord(status.UpgradeHistory, miniov2.UpgradeRecord{
+			FromImage:        fromImage,
+			ToImage:          toImage,
+			Result:           miniov2.UpgradeRejected,
+			Message:          rejectErr.Error(),
+			VerificationHash: hash,
+			StartTime:        &now,
+			CompletionTime:   &now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.recorder.Event(tenant, corev1.EventTypeWarning, "ImageVerificationFailed",
+		fmt.Sprintf("MinIO upgrade to %s rejected: %v", toImage, rejectErr))
+	return c.updateTenantStatus(ctx, tenant, StatusImageVerificationFailed, totalAvailableReplicas)
+}
+
+// appendUpgradeRecord adds the upgrade to the history, only the most recent upgrades are kept
+func appendUpgradeRecord(history []miniov2.UpgradeRecord, record miniov2.UpgradeRecord) []miniov2.UpgradeRecord {
+	history = append(history, record)
+	if len(history) > maxUpgradeHistory {
+		history = history[len(history)-maxUpgradeHistory:]
+	}
+	return history
+}
+
+// completeUpgradeRecord sets the result of the upgrade in progress
+func (c *Controller) completeUpgradeRecord(ctx context.Context, tenant *miniov2.Tenant, fromVersion string, result miniov2.UpgradeResult, message string) (*miniov2.Tenant, error) {
+	return c.setUpgradeRecord(ctx, tenant, fromVersion, result, message, func(record *miniov2.UpgradeRecord) {
+		now := metav1.Now()
+		record.CompletionTime = &now
+	})
+}
+
+// setUpgradeRecord moves the upgrade in progress to the given result, the update adds the fields of the result
+func (c *Controller) setUpgradeRecord(ctx context.Context, tenant *miniov2.Tenant, fromVersion string, result miniov2.UpgradeResult, message string, update func(record *miniov2.UpgradeRecord)) (*miniov2.Tenant, error) {
+	return c.updateUpgradeHistory(ctx, tenant, func(status *miniov2.TenantStatus) {
+		record := lastUpgradeRecord(status)
+		if record == nil || !upgradeRunning(record.Result) {
+			return
+		}
+		if fromVersion != "" {
+			record.FromVersion = fromVersion
+		}
+		record.Result = result
+		record.Message = message
+		if update != nil {
+			update(record)
+		}
+	})
+}
+
+// upgradeRunning returns true if the upgrade with the given result isn't completed yet
+func upgradeRunning(result miniov2.UpgradeResult) bool {
+	return result == miniov2.UpgradeInProgress || result == miniov2.UpgradeVerifying || result == miniov2.UpgradeRollingBack
+}
+
+// updateUpgradeHistory applies the change to the upgrade history and writes the status, the upgrade writes the status
+// on its own so on conflict the change is applied again to the latest tenant
+func (c *Controller) updateUpgradeHistory(ctx context.Context, tenant *miniov2.Tenant, update func(status *miniov2.TenantStatus)) (*miniov2.Tenant, error) {
+	tenant = tenant.DeepCopy()
+	update(&tenant.Status)
+	t, err := c.updatePoolStatusWithRetry(ctx, tenant, false)
+	if err == nil || !k8serrors.IsConflict(err) {
+		return t, err
+	}
+	latest, err := c.minioClientSet.MinioV2().Tenants(tenant.Namespace).Get(ctx, tenant.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	latest = latest.DeepCopy()
+	update(&latest.Status)
+	return c.updatePoolStatusWithRetry(ctx, latest, false)
+}
+
+// failUpgrade records the failed upgrade, with auto rollback the MinIO pods are restarted one at a time on the previous
+// image and the upgrade isn't retried until the image changes, otherwise the error is returned to retry the upgrade
+func (c *Controller) failUpgrade(ctx context.Context, tenant *miniov2.Tenant, fromVersion string, upgradeErr error, totalAvailableReplicas int32) (*miniov2.Tenant, Result, error) {
+	message := upgradeErr.Error()
+	if tenant.Spec.UpgradeStrategy == nil || !tenant.Spec.UpgradeStrategy.AutoRollback {
+		tenant, err := c.completeUpgradeRecord(ctx, tenant, fromVersion, miniov2.UpgradeFailed, message)
+		if err != nil {
+			return nil, Result{}, err
+		}
+		if tenant, err = c.updateTenantStatus(ctx, tenant, message, totalAvailableReplicas); err != nil {
+			return nil, Result{}, err
+		}
+		return tenant, Result{}, upgradeErr
+	}
+
+	klog.Warningf("'%s/%s' MinIO upgrade to %s failed, rolling back: %v", tenant.Namespace, tenant.Name, tenant.Spec.Image, upgradeErr)
+	tenant, err := c.setUpgradeRecord(ctx, tenant, fromVersion, miniov2.UpgradeRollingBack, message, func(record *miniov2.UpgradeRecord) {
+		if record.UpdateTime == nil {
+			// the servers may have swapped their binary until now
+			now := metav1.Now()
+			record.UpdateTime = &now
+		}
+	})
+	if err != nil {
+		return nil, Result{}, err
+	}
+	c.recorder.Event(tenant, corev1.EventTypeWarning, "UpgradeRollingBack",
+		fmt.Sprintf("MinIO upgrade to %s failed, rolling back: %s", tenant.Spec.Image, message))
+	if tenant, err = c.updateTenantStatus(ctx, tenant, StatusRollingBackMinIOUpgrade, totalAvailableReplicas); err != nil {
+		return nil, Result{}, err
+	}
+	return tenant, Result{RequeueAfter: upgradeVerificationInterval}, nil
+}
+
+// pinnedImage returns the spec image pinned to the digest it was verified with, so the pools pull the verified image
+// rather than whatever its tag points to
+func pinnedImage(tenant *miniov2.Tenant) string {
+	if strings.Contains(tenant.Spec.Image, "@") {
+		return tenant.Spec.Image
+	}
+	for i := len(tenant.Status.UpgradeHistory) - 1; i >= 0; i-- {
+		record := tenant.Status.UpgradeHistory[i]
+		if record.ToImage == tenant.Spec.Image && record.Digest != "" {
+			return tenant.Spec.Image + "@" + record.Digest
+		}
+	}
+	return tenant.Spec.Image
+}
+
+// lastUpgradeRecord returns the most recent upgrade of the tenant, nil if it was never upgraded
+func lastUpgradeRecord(status *miniov2.TenantStatus) *miniov2.UpgradeRecord {
+	if len(status.UpgradeHistory) == 0 {
+		return nil
+	}
+	return &status.UpgradeHistory[len(status.UpgradeHistory)-1]
+}
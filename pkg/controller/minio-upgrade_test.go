@@ -0,0 +1,642 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	miniofake "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_checkUpgradePreflight(t *testing.T) {
+	disks := func(states ...string) []madmin.Disk {
+		var d []madmin.Disk
+		for _, state := range states {
+			d = append(d, madmin.Disk{State: state})
+		}
+		return d
+	}
+	healthy := madmin.HealthResult{Healthy: true, WriteQuorum: 3}
+	tests := []struct {
+		name     string
+		strategy *miniov2.UpgradeStrategy
+		health   madmin.HealthResult
+		disks    []madmin.Disk
+		wantErr  bool
+	}{
+		{
+			name:   "Healthy",
+			health: healthy,
+			disks:  disks(madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOk),
+		},
+		{
+			name:    "Unhealthy",
+			health:  madmin.HealthResult{WriteQuorum: 3},
+			disks:   disks(madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOk),
+			wantErr: true,
+		},
+		{
+			name:    "Healing drives",
+			health:  madmin.HealthResult{Healthy: true, WriteQuorum: 3, HealingDrives: 1},
+			disks:   disks(madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOk),
+			wantErr: true,
+		},
+		{
+			name:     "Quorum margin kept",
+			strategy: &miniov2.UpgradeStrategy{QuorumMargin: 1},
+			health:   healthy,
+			disks:    disks(madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOk),
+		},
+		{
+			name:     "Quorum margin not kept",
+			strategy: &miniov2.UpgradeStrategy{QuorumMargin: 1},
+			health:   healthy,
+			disks:    disks(madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOk, madmin.DriveStateOffline),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkUpgradePreflight(tt.strategy, tt.health, tt.disks); (err != nil) != tt.wantErr {
+				t.Errorf("checkUpgradePreflight() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkServersRelease(t *testing.T) {
+	release := "RELEASE.2024-03-15T01-07-19Z"
+	tests := []struct {
+		name    string
+		servers []madmin.ServerProperties
+		wantErr bool
+	}{
+		{
+			name: "All servers upgraded",
+			servers: []madmin.ServerProperties{
+				{Endpoint: "pool-0-0:9000", State: "online", Version: "2024-03-15T01:07:19Z"},
+				{Endpoint: "pool-0-1:9000", State: "online", Version: "2024-03-15T01:07:19Z"},
+			},
+		},
+		{
+			name: "Server on the previous release",
+			servers: []madmin.ServerProperties{
+				{Endpoint: "pool-0-0:9000", State: "online", Version: "2024-03-15T01:07:19Z"},
+				{Endpoint: "pool-0-1:9000", State: "online", Version: "2024-02-26T09:33:48Z"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Server offline",
+			servers: []madmin.ServerProperties{
+				{Endpoint: "pool-0-0:9000", State: "online", Version: "2024-03-15T01:07:19Z"},
+				{Endpoint: "pool-0-1:9000", State: "offline"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "No servers",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkServersRelease(tt.servers, release); (err != nil) != tt.wantErr {
+				t.Errorf("checkServersRelease() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFailUpgrade(t *testing.T) {
+	ctx := context.Background()
+	ssOwner := metav1.OwnerReference{Kind: "StatefulSet", Name: "myminio-pool-0", Controller: &[]bool{true}[0]}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            "myminio-pool-0-0",
+		Namespace:       "tenant-ns",
+		Labels:          map[string]string{miniov2.TenantLabel: "myminio"},
+		OwnerReferences: []metav1.OwnerReference{ssOwner},
+	}}
+	newTenant := func(strategy *miniov2.UpgradeStrategy) *miniov2.Tenant {
+		return &miniov2.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+			Spec: miniov2.TenantSpec{
+				Image:           "minio/minio:RELEASE.2024-03-15T01-07-19Z",
+				UpgradeStrategy: strategy,
+			},
+			Status: miniov2.TenantStatus{
+				CurrentState: StatusUpdatingMinIOVersion,
+				Pools:        []miniov2.PoolStatus{{SSName: "myminio-pool-0", State: miniov2.PoolInitialized}},
+			},
+		}
+	}
+	newController := func(tenant *miniov2.Tenant) (*Controller, *fake.Clientset) {
+		kubeClientSet := fake.NewSimpleClientset(pod.DeepCopy())
+		return &Controller{
+			kubeClientSet:  kubeClientSet,
+			minioClientSet: miniofake.NewSimpleClientset(tenant),
+			recorder:       record.NewFakeRecorder(10),
+		}, kubeClientSet
+	}
+	upgradeErr := errors.New("host myminio-pool-0-0: update failed")
+
+	t.Run("Without rollback", func(t *testing.T) {
+		tenant := newTenant(nil)
+		c, kubeClientSet := newController(tenant)
+		tenant, err := c.startUpgradeRecord(ctx, tenant, "minio/minio:RELEASE.2024-02-26T09-33-48Z", "RELEASE.2024-03-15T01-07-19Z", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tenant.Spec = newTenant(nil).Spec
+		if _, _, err = c.failUpgrade(ctx, tenant, "2024-02-26T09:33:48Z", upgradeErr, 1); !errors.Is(err, upgradeErr) {
+			t.Fatalf("expected the upgrade error to be returned to retry the upgrade, got %v", err)
+		}
+		updated, err := c.minioClientSet.MinioV2().Tenants("tenant-ns").Get(ctx, "myminio", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := lastUpgradeRecord(&updated.Status)
+		if record == nil || record.Result != miniov2.UpgradeFailed || record.FromVersion != "2024-02-26T09:33:48Z" || record.CompletionTime == nil {
+			t.Fatalf("expected the upgrade to be recorded as failed, got %+v", record)
+		}
+		if _, err = kubeClientSet.CoreV1().Pods("tenant-ns").Get(ctx, pod.Name, metav1.GetOptions{}); err != nil {
+			t.Fatalf("expected the MinIO pods to be kept, got %v", err)
+		}
+	})
+
+	t.Run("With rollback", func(t *testing.T) {
+		tenant := newTenant(&miniov2.UpgradeStrategy{AutoRollback: true})
+		c, kubeClientSet := newController(tenant)
+		tenant, err := c.startUpgradeRecord(ctx, tenant, "minio/minio:RELEASE.2024-02-26T09-33-48Z", "RELEASE.2024-03-15T01-07-19Z", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tenant.Spec = newTenant(&miniov2.UpgradeStrategy{AutoRollback: true}).Spec
+		_, result, err := c.failUpgrade(ctx, tenant, "", upgradeErr, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.RequeueAfter == 0 {
+			t.Fatal("expected the rollback to be requeued")
+		}
+		updated, err := c.minioClientSet.MinioV2().Tenants("tenant-ns").Get(ctx, "myminio", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated.Status.CurrentState != StatusRollingBackMinIOUpgrade {
+			t.Fatalf("expected state %q, got %q", StatusRollingBackMinIOUpgrade, updated.Status.CurrentState)
+		}
+		record := lastUpgradeRecord(&updated.Status)
+		if record == nil || record.Result != miniov2.UpgradeRollingBack || record.UpdateTime == nil || record.CompletionTime != nil {
+			t.Fatalf("expected the upgrade to be rolling back, got %+v", record)
+		}
+		// the pods are restarted one at a time by the next reconciliations
+		if _, err = kubeClientSet.CoreV1().Pods("tenant-ns").Get(ctx, pod.Name, metav1.GetOptions{}); err != nil {
+			t.Fatalf("expected the MinIO pods to be kept, got %v", err)
+		}
+	})
+}
+
+// fakeInfoServer serves the info of the MinIO servers
+func fakeInfoServer(t *testing.T, servers ...madmin.ServerProperties) *madmin.AdminClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/info") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(madmin.InfoMessage{Servers: servers})
+	}))
+	t.Cleanup(server.Close)
+	serverURL, _ := url.Parse(server.URL)
+	adminClnt, err := madmin.New(serverURL.Host, "root", "root-secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return adminClnt
+}
+
+func TestVerifyUpgrade(t *testing.T) {
+	ctx := context.Background()
+	newTenant := func(updated time.Time) *miniov2.Tenant {
+		return &miniov2.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+			Spec: miniov2.TenantSpec{
+				Image:           "minio/minio:RELEASE.2024-03-15T01-07-19Z",
+				UpgradeStrategy: &miniov2.UpgradeStrategy{AutoRollback: true},
+			},
+			Status: miniov2.TenantStatus{
+				CurrentState: StatusVerifyingMinIOUpgrade,
+				UpgradeHistory: []miniov2.UpgradeRecord{{
+					FromImage:  "minio/minio:RELEASE.2024-02-26T09-33-48Z",
+					ToImage:    "minio/minio:RELEASE.2024-03-15T01-07-19Z",
+					ToVersion:  "RELEASE.2024-03-15T01-07-19Z",
+					Result:     miniov2.UpgradeVerifying,
+					UpdateTime: &metav1.Time{Time: updated},
+				}},
+			},
+		}
+	}
+	previous := madmin.ServerProperties{Endpoint: "myminio-pool-0-0.myminio-hl.tenant-ns.svc.cluster.local:9000", State: "online", Version: "2024-02-26T09:33:48Z"}
+	upgraded := madmin.ServerProperties{Endpoint: "myminio-pool-0-0.myminio-hl.tenant-ns.svc.cluster.local:9000", State: "online", Version: "2024-03-15T01:07:19Z"}
+	tests := []struct {
+		name       string
+		updated    time.Time
+		server     madmin.ServerProperties
+		wantStep   upgradeStep
+		wantResult miniov2.UpgradeResult
+		wantState  string
+	}{
+		{
+			name:       "Upgraded",
+			updated:    time.Now(),
+			server:     upgraded,
+			wantStep:   upgradeVerified,
+			wantResult: miniov2.UpgradeSucceeded,
+			wantState:  StatusVerifyingMinIOUpgrade,
+		},
+		{
+			name:       "Not upgraded yet",
+			updated:    time.Now(),
+			server:     previous,
+			wantStep:   upgradeHold,
+			wantResult: miniov2.UpgradeVerifying,
+			wantState:  StatusVerifyingMinIOUpgrade,
+		},
+		{
+			name:       "Timed out",
+			updated:    time.Now().Add(-upgradeVerificationTimeout),
+			server:     previous,
+			wantStep:   upgradeHold,
+			wantResult: miniov2.UpgradeRollingBack,
+			wantState:  StatusRollingBackMinIOUpgrade,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := newTenant(tt.updated)
+			c := &Controller{
+				minioClientSet: miniofake.NewSimpleClientset(tenant),
+				recorder:       record.NewFakeRecorder(10),
+			}
+			updated, step, result, err := c.syncUpgradeProgress(ctx, tenant, fakeInfoServer(t, tt.server), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if step != tt.wantStep {
+				t.Errorf("expected step %d, got %d", tt.wantStep, step)
+			}
+			if step == upgradeHold && result.RequeueAfter == 0 {
+				t.Error("expected the upgrade to be checked again")
+			}
+			if record := lastUpgradeRecord(&updated.Status); record.Result != tt.wantResult {
+				t.Errorf("expected result %s, got %s", tt.wantResult, record.Result)
+			}
+			if updated.Status.CurrentState != tt.wantState {
+				t.Errorf("expected state %q, got %q", tt.wantState, updated.Status.CurrentState)
+			}
+		})
+	}
+}
+
+func TestNextRollbackPod(t *testing.T) {
+	updated := time.Now()
+	record := &miniov2.UpgradeRecord{
+		ToVersion:  "RELEASE.2024-03-15T01-07-19Z",
+		Result:     miniov2.UpgradeRollingBack,
+		UpdateTime: &metav1.Time{Time: updated},
+	}
+	newPod := func(name string, created time.Time, ready bool) corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.Time{Time: created}},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+		}
+	}
+	server := func(name, version string) madmin.ServerProperties {
+		return madmin.ServerProperties{Endpoint: name + ".myminio-hl.tenant-ns.svc.cluster.local:9000", State: "online", Version: version}
+	}
+	before := updated.Add(-time.Hour)
+	after := updated.Add(time.Minute)
+	deleting := newPod("myminio-pool-0-0", before, true)
+	deleting.DeletionTimestamp = &metav1.Time{Time: updated}
+	tests := []struct {
+		name     string
+		pods     []corev1.Pod
+		servers  []madmin.ServerProperties
+		wantPod  string
+		wantWait bool
+	}{
+		{
+			name: "Restarts the first upgraded server",
+			pods: []corev1.Pod{newPod("myminio-pool-0-1", before, true), newPod("myminio-pool-0-0", before, true)},
+			servers: []madmin.ServerProperties{
+				server("myminio-pool-0-0", "2024-03-15T01:07:19Z"),
+				server("myminio-pool-0-1", "2024-03-15T01:07:19Z"),
+			},
+			wantPod: "myminio-pool-0-0",
+		},
+		{
+			name: "Waits for the restarted pod to be deleted",
+			pods: []corev1.Pod{deleting, newPod("myminio-pool-0-1", before, true)},
+			servers: []madmin.ServerProperties{
+				server("myminio-pool-0-1", "2024-03-15T01:07:19Z"),
+			},
+			wantWait: true,
+		},
+		{
+			name: "Waits for the restarted pod to be ready",
+			pods: []corev1.Pod{newPod("myminio-pool-0-0", after, false), newPod("myminio-pool-0-1", before, true)},
+			servers: []madmin.ServerProperties{
+				server("myminio-pool-0-1", "2024-03-15T01:07:19Z"),
+			},
+			wantWait: true,
+		},
+		{
+			name: "Restarts the next server once the restarted pod is ready",
+			pods: []corev1.Pod{newPod("myminio-pool-0-0", after, true), newPod("myminio-pool-0-1", before, true)},
+			servers: []madmin.ServerProperties{
+				server("myminio-pool-0-0", "2024-02-26T09:33:48Z"),
+				server("myminio-pool-0-1", "2024-03-15T01:07:19Z"),
+			},
+			wantPod: "myminio-pool-0-1",
+		},
+		{
+			name: "No server changed release",
+			pods: []corev1.Pod{newPod("myminio-pool-0-0", before, true), newPod("myminio-pool-0-1", before, true)},
+			servers: []madmin.ServerProperties{
+				server("myminio-pool-0-0", "2024-02-26T09:33:48Z"),
+				server("myminio-pool-0-1", "2024-02-26T09:33:48Z"),
+			},
+		},
+		{
+			name:    "Unknown release",
+			pods:    []corev1.Pod{newPod("myminio-pool-0-0", before, true), newPod("myminio-pool-0-1", before, true)},
+			wantPod: "myminio-pool-0-0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod, wait := nextRollbackPod(tt.pods, tt.servers, record)
+			var name string
+			if pod != nil {
+				name = pod.Name
+			}
+			if name != tt.wantPod || wait != tt.wantWait {
+				t.Errorf("nextRollbackPod() = %q, %v, expected %q, %v", name, wait, tt.wantPod, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestRollbackUpgrade(t *testing.T) {
+	ctx := context.Background()
+	ssOwner := metav1.OwnerReference{Kind: "StatefulSet", Name: "myminio-pool-0", Controller: &[]bool{true}[0]}
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "tenant-ns",
+			Labels:            map[string]string{miniov2.TenantLabel: "myminio"},
+			OwnerReferences:   []metav1.OwnerReference{ssOwner},
+			CreationTimestamp: metav1.Time{Time: time.Now().Add(-time.Hour)},
+		}}
+	}
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+		Spec:       miniov2.TenantSpec{Image: "minio/minio:RELEASE.2024-03-15T01-07-19Z"},
+		Status: miniov2.TenantStatus{
+			CurrentState: StatusRollingBackMinIOUpgrade,
+			Pools:        []miniov2.PoolStatus{{SSName: "myminio-pool-0", State: miniov2.PoolInitialized}},
+			UpgradeHistory: []miniov2.UpgradeRecord{{
+				ToImage:    "minio/minio:RELEASE.2024-03-15T01-07-19Z",
+				ToVersion:  "RELEASE.2024-03-15T01-07-19Z",
+				Result:     miniov2.UpgradeRollingBack,
+				Message:    "host myminio-pool-0-0: update failed",
+				UpdateTime: &metav1.Time{Time: time.Now()},
+			}},
+		},
+	}
+	server := func(name, version string) madmin.ServerProperties {
+		return madmin.ServerProperties{Endpoint: name + ".myminio-hl.tenant-ns.svc.cluster.local:9000", State: "online", Version: version}
+	}
+
+	t.Run("Restarts one pod", func(t *testing.T) {
+		kubeClientSet := fake.NewSimpleClientset(newPod("myminio-pool-0-0"), newPod("myminio-pool-0-1"))
+		c := &Controller{
+			kubeClientSet:  kubeClientSet,
+			minioClientSet: miniofake.NewSimpleClientset(tenant.DeepCopy()),
+			recorder:       record.NewFakeRecorder(10),
+		}
+		adminClnt := fakeInfoServer(t, server("myminio-pool-0-0", "2024-03-15T01:07:19Z"), server("myminio-pool-0-1", "2024-03-15T01:07:19Z"))
+		_, step, result, err := c.syncUpgradeProgress(ctx, tenant.DeepCopy(), adminClnt, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if step != upgradeHold || result.RequeueAfter == 0 {
+			t.Fatalf("expected the rollback to be checked again, got step %d and %+v", step, result)
+		}
+		pods, err := kubeClientSet.CoreV1().Pods("tenant-ns").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pods.Items) != 1 || pods.Items[0].Name != "myminio-pool-0-1" {
+			t.Fatalf("expected a single pod to be restarted, got %+v", pods.Items)
+		}
+	})
+
+	t.Run("No server changed release", func(t *testing.T) {
+		kubeClientSet := fake.NewSimpleClientset(newPod("myminio-pool-0-0"), newPod("myminio-pool-0-1"))
+		c := &Controller{
+			kubeClientSet:  kubeClientSet,
+			minioClientSet: miniofake.NewSimpleClientset(tenant.DeepCopy()),
+			recorder:       record.NewFakeRecorder(10),
+		}
+		adminClnt := fakeInfoServer(t, server("myminio-pool-0-0", "2024-02-26T09:33:48Z"), server("myminio-pool-0-1", "2024-02-26T09:33:48Z"))
+		updated, step, _, err := c.syncUpgradeProgress(ctx, tenant.DeepCopy(), adminClnt, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if step != upgradeHold || updated.Status.CurrentState != StatusMinIOUpgradeRolledBack {
+			t.Fatalf("expected the upgrade to be rolled back, got step %d and state %q", step, updated.Status.CurrentState)
+		}
+		if record := lastUpgradeRecord(&updated.Status); record.Result != miniov2.UpgradeRolledBack || record.CompletionTime == nil {
+			t.Fatalf("expected the upgrade to be recorded as rolled back, got %+v", record)
+		}
+		pods, err := kubeClientSet.CoreV1().Pods("tenant-ns").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pods.Items) != 2 {
+			t.Fatalf("expected no pod to be restarted, got %+v", pods.Items)
+		}
+	})
+}
+
+func TestStartUpgradeRecord(t *testing.T) {
+	ctx := context.Background()
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+		Spec:       miniov2.TenantSpec{Image: "minio/minio:RELEASE.2024-03-15T01-07-19Z"},
+	}
+	for i := 0; i < maxUpgradeHistory; i++ {
+		tenant.Status.UpgradeHistory = append(tenant.Status.UpgradeHistory, miniov2.UpgradeRecord{
+			ToImage: "minio/minio:RELEASE.2024-02-26T09-33-48Z",
+			Result:  miniov2.UpgradeSucceeded,
+		})
+	}
+	c := &Controller{minioClientSet: miniofake.NewSimpleClientset(tenant)}
+
+	updated, err := c.startUpgradeRecord(ctx, tenant, "minio/minio:RELEASE.2024-02-26T09-33-48Z", "RELEASE.2024-03-15T01-07-19Z", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.Status.UpgradeHistory) != maxUpgradeHistory {
+		t.Fatalf("expected the history to keep %d upgrades, got %d", maxUpgradeHistory, len(updated.Status.UpgradeHistory))
+	}
+	record := lastUpgradeRecord(&updated.Status)
+	if record.ToImage != tenant.Spec.Image || record.Result != miniov2.UpgradeInProgress {
+		t.Fatalf("expected the upgrade in progress to be the last record, got %+v", record)
+	}
+	// an interrupted upgrade is picked up again instead of recorded twice
+	updated.Spec = tenant.Spec
+	if updated, err = c.startUpgradeRecord(ctx, updated, "minio/minio:RELEASE.2024-02-26T09-33-48Z", "RELEASE.2024-03-15T01-07-19Z", ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.Status.UpgradeHistory) != maxUpgradeHistory || updated.Status.UpgradeHistory[maxUpgradeHistory-2].Result != miniov2.UpgradeSucceeded {
+		t.Fatalf("expected the upgrade in progress to be recorded once, got %+v", updated.Status.UpgradeHistory)
+	}
+}
+
+func TestRejectUpgrade(t *testing.T) {
+	ctx := context.Background()
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+		Spec: miniov2.TenantSpec{
+			Image: "minio/minio:RELEASE.2024-03-15T01-07-19Z",
+			ImageVerification: &miniov2.ImageVerification{
+				CosignPublicKey: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "minio-cosign"},
+					Key:                  "cosign.pub",
+				},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio-cosign", Namespace: "tenant-ns"},
+		Data:       map[string][]byte{"cosign.pub": []byte("old key")},
+	}
+	kubeClientSet := fake.NewSimpleClientset(secret)
+	c := &Controller{
+		kubeClientSet:  kubeClientSet,
+		minioClientSet: miniofake.NewSimpleClientset(tenant),
+		recorder:       record.NewFakeRecorder(10),
+	}
+
+	rejected, err := c.rejectUpgrade(ctx, tenant, "minio/minio:RELEASE.2024-02-26T09-33-48Z", errors.New("no valid cosign signature found"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Status.CurrentState != StatusImageVerificationFailed {
+		t.Fatalf("expected state %q, got %q", StatusImageVerificationFailed, rejected.Status.CurrentState)
+	}
+	rejected.Spec = tenant.Spec
+	updated, step, result, err := c.syncUpgradeProgress(ctx, rejected, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step != upgradeHold || result.RequeueAfter == 0 {
+		t.Fatalf("expected the rejected upgrade to keep the pools on their image, got step %d and %+v", step, result)
+	}
+	if updated.Status.CurrentState != StatusImageVerificationFailed {
+		t.Fatalf("expected state %q, got %q", StatusImageVerificationFailed, updated.Status.CurrentState)
+	}
+
+	// a new cosign public key verifies the image again
+	secret.Data["cosign.pub"] = []byte("new key")
+	if _, err = kubeClientSet.CoreV1().Secrets("tenant-ns").Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, step, _, err = c.syncUpgradeProgress(ctx, rejected, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	if step != upgradeStart {
+		t.Fatalf("expected the image to be verified again, got step %d", step)
+	}
+}
+
+func TestPinnedImage(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		name    string
+		image   string
+		history []miniov2.UpgradeRecord
+		want    string
+	}{
+		{
+			name:  "Not verified",
+			image: "minio/minio:RELEASE.2024-03-15T01-07-19Z",
+			history: []miniov2.UpgradeRecord{
+				{ToImage: "minio/minio:RELEASE.2024-03-15T01-07-19Z", Result: miniov2.UpgradeSucceeded},
+			},
+			want: "minio/minio:RELEASE.2024-03-15T01-07-19Z",
+		},
+		{
+			name:  "Verified",
+			image: "minio/minio:RELEASE.2024-03-15T01-07-19Z",
+			history: []miniov2.UpgradeRecord{
+				{ToImage: "minio/minio:RELEASE.2024-03-15T01-07-19Z", Digest: digest, Result: miniov2.UpgradeSucceeded},
+				{ToImage: "minio/minio:RELEASE.2024-04-18T19-09-19Z", Result: miniov2.UpgradeRejected},
+			},
+			want: "minio/minio:RELEASE.2024-03-15T01-07-19Z@" + digest,
+		},
+		{
+			name:  "Already pinned",
+			image: "minio/minio:RELEASE.2024-03-15T01-07-19Z@" + digest,
+			history: []miniov2.UpgradeRecord{
+				{ToImage: "minio/minio:RELEASE.2024-03-15T01-07-19Z@" + digest, Digest: digest, Result: miniov2.UpgradeSucceeded},
+			},
+			want: "minio/minio:RELEASE.2024-03-15T01-07-19Z@" + digest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &miniov2.Tenant{
+				Spec:   miniov2.TenantSpec{Image: tt.image},
+				Status: miniov2.TenantStatus{UpgradeHistory: tt.history},
+			}
+			if got := pinnedImage(tenant); got != tt.want {
+				t.Errorf("pinnedImage() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
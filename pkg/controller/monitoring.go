@@ -67,6 +67,10 @@ func (c *Controller) tenantsHealthMonitor() error {
 		return err
 	}
 	for _, t := range tenants.Items {
+		// a paused tenant is left as it is, including its health status, a hibernated one runs no pods
+		if t.Spec.Paused || t.Spec.Hibernate {
+			continue
+		}
 		tenant, err := c.updateHealthStatusForTenant(&t)
 		if err != nil {
 			klog.Errorf("%v", err)
@@ -279,6 +283,11 @@ func (c *Controller) syncHealthCheckHandler(key string) (Result, error) {
 		return WrapResult(Result{}, err)
 	}
 
+	// the tenant was paused or hibernated after being queued
+	if tenant.Spec.Paused || tenant.Spec.Hibernate {
+		return WrapResult(Result{}, nil)
+	}
+
 	tenant.EnsureDefaults()
 
 	tenant, err = c.updateHealthStatusForTenant(tenant)
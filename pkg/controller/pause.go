@@ -0,0 +1,35 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"context"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// syncPausedTenant only reports that the reconciliation of the tenant is paused, nothing else is touched
+func (c *Controller) syncPausedTenant(ctx context.Context, tenant *miniov2.Tenant) (Result, error) {
+	if tenant.Status.CurrentState != StatusPaused {
+		klog.Infof("'%s/%s' reconciliation paused", tenant.Namespace, tenant.Name)
+		c.recorder.Event(tenant, corev1.EventTypeNormal, "ReconciliationPaused", "Tenant reconciliation paused")
+	}
+	if _, err := c.updateTenantStatus(ctx, tenant, StatusPaused, tenant.Status.AvailableReplicas); err != nil {
+		return WrapResult(Result{}, err)
+	}
+	return WrapResult(Result{}, nil)
+}
@@ -0,0 +1,69 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	miniofake "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSyncPausedTenant(t *testing.T) {
+	ctx := context.Background()
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns", Generation: 2},
+		Spec:       miniov2.TenantSpec{Paused: true},
+		Status:     miniov2.TenantStatus{CurrentState: StatusInitialized, AvailableReplicas: 4},
+	}
+	kubeClientSet := fake.NewSimpleClientset()
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{
+		kubeClientSet:  kubeClientSet,
+		minioClientSet: miniofake.NewSimpleClientset(tenant),
+		recorder:       recorder,
+	}
+
+	if _, err := c.syncHandler("tenant-ns/myminio"); err != nil {
+		t.Fatal(err)
+	}
+
+	if actions := kubeClientSet.Actions(); len(actions) != 0 {
+		t.Errorf("expected no change to the tenant resources, got %v", actions)
+	}
+	updated, err := c.minioClientSet.MinioV2().Tenants("tenant-ns").Get(ctx, "myminio", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.CurrentState != StatusPaused || updated.Status.AvailableReplicas != 4 {
+		t.Errorf("unexpected status %+v", updated.Status)
+	}
+	if condition := meta.FindStatusCondition(updated.Status.Conditions, miniov2.TenantConditionProgressing); condition == nil || condition.Reason != ReasonPaused {
+		t.Errorf("unexpected progressing condition %+v", condition)
+	}
+	if len(updated.Finalizers) != 0 {
+		t.Errorf("expected a paused tenant to be left as it is, got finalizers %v", updated.Finalizers)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expected a pause event, got %d", len(recorder.Events))
+	}
+}
@@ -88,7 +88,7 @@ func (c *Controller) CreateOrUpdatePDB(ctx context.Context, t *v2.Tenant) (err e
 		}
 		if available.Available() {
 			// check sts status first.
-			ssName := t.PoolStatefulsetName(&pool)
+			ssName := t.StatefulsetNameForPool(&pool)
 			existingStatefulSet, err := c.statefulSetLister.StatefulSets(t.Namespace).Get(ssName)
 			if err != nil {
 				return err
@@ -0,0 +1,248 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// checkForPoolReplacement replaces the pools whose server or volume count changed when the tenant allows it. The new
+// pool is deployed next to the existing one, once MinIO initialized it the existing pool is decommissioned and removed.
+func (c *Controller) checkForPoolReplacement(ctx context.Context, key string, tenant *miniov2.Tenant, tenantConfiguration map[string][]byte) (*miniov2.Tenant, error) {
+	for i := range tenant.Status.Pools {
+		if tenant.Status.Pools[i].Replacement != nil {
+			return c.continuePoolReplacement(ctx, key, tenant, tenant.Status.Pools[i].SSName, tenantConfiguration)
+		}
+	}
+	if !tenant.Spec.AllowPoolReplacement {
+		return tenant, nil
+	}
+
+	// replace one pool at a time
+	for _, pool := range tenant.Spec.Pools {
+		pstatus := tenant.PoolStatusForPool(&pool)
+		if pstatus == nil || pstatus.State != miniov2.PoolInitialized {
+			continue
+		}
+		ss, err := c.statefulSetLister.StatefulSets(tenant.Namespace).Get(pstatus.SSName)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		servers, volumes := statefulSetGeometry(ss, &pool)
+		if servers == pool.Servers && volumes == pool.VolumesPerServer {
+			continue
+		}
+		// a standalone MinIO can't be expanded, neither can it join an expanded deployment
+		if len(tenant.Spec.Pools) == 1 && (servers == 1 || pool.Servers == 1) {
+			klog.Warningf("%s Detected we are replacing pool %s of a standalone tenant - disallowing replacement", key, pool.Name)
+			if tenant, err = c.updateTenantStatus(ctx, tenant, StatusPoolReplacementNotAllowed, 0); err != nil {
+				return nil, err
+			}
+			return nil, errors.New("replacing a standalone pool is not allowed")
+		}
+
+		adminClnt, err := tenant.NewMinIOAdmin(tenantConfiguration, c.getTransport())
+		if err != nil {
+			return nil, err
+		}
+		minioPools, err := adminClnt.ListPoolsStatus(ctx)
+		if err != nil {
+			return nil, err
+		}
+		minioPool, ok := minioPoolForStatefulSet(minioPools, pstatus.SSName)
+		if !ok {
+			return nil, fmt.Errorf("pool %s is not part of the MinIO deployment", pstatus.SSName)
+		}
+
+		now := metav1.Now()
+		pstatus.Replacement = &miniov2.PoolReplacementStatus{
+			Stage:            miniov2.PoolReplacementCreating,
+			SSName:           nextPoolReplacementName(tenant, &pool, pstatus.SSName),
+			CmdLine:          minioPool.CmdLine,
+			Servers:          servers,
+			VolumesPerServer: volumes,
+			StartTime:        &now,
+		}
+		klog.Infof("%s Replacing pool %s by %s", key, pstatus.SSName, pstatus.Replacement.SSName)
+		c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolReplacementStarted", fmt.Sprintf("Tenant pool %s is being replaced by %s", pstatus.SSName, pstatus.Replacement.SSName))
+		tenant.Status.CurrentState = StatusReplacingPool
+		return c.updatePoolStatus(ctx, tenant)
+	}
+	return tenant, nil
+}
+
+// continuePoolReplacement moves the replacement of the pool deployed by the given statefulset to its next stage, the
+// new pool is created and initialized by the regular reconciliation of the pools
+func (c *Controller) continuePoolReplacement(ctx context.Context, key string, tenant *miniov2.Tenant, ssName string, tenantConfiguration map[string][]byte) (*miniov2.Tenant, error) {
+	var err error
+	pstatus := &tenant.Status.Pools[poolStatusIndex(tenant, ssName)]
+	replacement := pstatus.Replacement
+
+	if pstatus.State != miniov2.PoolDecommissioned {
+		stage := miniov2.PoolReplacementDecommissioning
+		if ni := poolStatusIndex(tenant, replacement.SSName); ni < 0 || tenant.Status.Pools[ni].State == miniov2.PoolNotCreated {
+			stage = miniov2.PoolReplacementCreating
+		} else if tenant.Status.Pools[ni].State == miniov2.PoolCreated {
+			stage = miniov2.PoolReplacementInitializing
+		}
+		if stage != miniov2.PoolReplacementDecommissioning {
+			if stage == replacement.Stage {
+				return tenant, nil
+			}
+			replacement.Stage = stage
+			tenant.Status.CurrentState = StatusReplacingPool
+			return c.updatePoolStatus(ctx, tenant)
+		}
+
+		// the new pool is initialized, move the data out of the replaced pool
+		if !tenant.MinIOHealthCheck(c.getTransport()) {
+			klog.Infof("%s is not running can't decommission pool %s", key, ssName)
+			return nil, ErrMinIONotReady
+		}
+		replacement.Stage = miniov2.PoolReplacementDecommissioning
+		var decommissioned bool
+		if tenant, decommissioned, err = c.decommissionPools(ctx, key, tenant, []string{ssName}, tenantConfiguration); err != nil {
+			return nil, err
+		}
+		if !decommissioned {
+			// let MinIO pick up the arguments without the decommissioned pool before removing it
+			pstatus = &tenant.Status.Pools[poolStatusIndex(tenant, ssName)]
+			if pstatus.State == miniov2.PoolDecommissioned {
+				pstatus.Replacement.Stage = miniov2.PoolReplacementRemoving
+				if _, err = c.updatePoolStatus(ctx, tenant); err != nil {
+					return nil, err
+				}
+			}
+			return nil, ErrPoolDecommissioning
+		}
+	}
+
+	// the pool was decommissioned, drop it and restart MinIO to serve the new pool only
+	var poolStatus []miniov2.PoolStatus
+	for _, p := range tenant.Status.Pools {
+		if p.SSName != ssName {
+			poolStatus = append(poolStatus, *p.DeepCopy())
+		}
+	}
+	tenant.Status.Pools = poolStatus
+
+	for _, pool := range tenant.Spec.Pools {
+		if !tenant.IsPoolStatefulset(&pool, ssName) {
+			continue
+		}
+		if err = c.restartInitializedPool(ctx, tenant, pool, tenantConfiguration); err != nil {
+			return nil, err
+		}
+		break
+	}
+	metaNowTime := metav1.Now()
+	tenant.Status.WaitingOnReady = &metaNowTime
+	tenant.Status.CurrentState = StatusRestartingMinIO
+	if tenant, err = c.updatePoolStatus(ctx, tenant); err != nil {
+		klog.Infof("'%s' Can't update tenant status: %v", key, err)
+		return nil, err
+	}
+	klog.Infof("'%s' was restarted", key)
+
+	if err = c.kubeClientSet.AppsV1().StatefulSets(tenant.Namespace).Delete(ctx, ssName, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+	c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolReplaced", fmt.Sprintf("Tenant pool %s replaced by %s", ssName, replacement.SSName))
+	return nil, ErrMinIORestarting
+}
+
+// poolReplacementInProgress returns true if a pool of the tenant is being replaced
+func poolReplacementInProgress(tenant *miniov2.Tenant) bool {
+	for _, pstatus := range tenant.Status.Pools {
+		if pstatus.Replacement != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// initializedReplacedPool returns the spec pool of a pool being replaced that MinIO still serves, empty if there is none
+func initializedReplacedPool(tenant *miniov2.Tenant) miniov2.Pool {
+	for _, pstatus := range tenant.Status.Pools {
+		if pstatus.Replacement == nil || pstatus.State != miniov2.PoolInitialized {
+			continue
+		}
+		for _, pool := range tenant.Spec.Pools {
+			if tenant.IsPoolStatefulset(&pool, pstatus.SSName) {
+				return pool
+			}
+		}
+	}
+	return miniov2.Pool{}
+}
+
+// poolStatusIndex returns the index of the status of the given statefulset, -1 if it isn't in the status
+func poolStatusIndex(tenant *miniov2.Tenant, ssName string) int {
+	for i := range tenant.Status.Pools {
+		if tenant.Status.Pools[i].SSName == ssName {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextPoolReplacementName returns the name of the statefulset replacing the given statefulset of the pool, the
+// sequence number only grows so the volumes of previous replacements are never reused
+func nextPoolReplacementName(tenant *miniov2.Tenant, pool *miniov2.Pool, ssName string) string {
+	n := 0
+	if suffix, ok := strings.CutPrefix(ssName, tenant.PoolStatefulsetName(pool)+miniov2.PoolReplacementSuffix); ok {
+		n, _ = strconv.Atoi(suffix)
+	}
+	return tenant.PoolReplacementStatefulsetName(pool, n+1)
+}
+
+// statefulSetGeometry returns the number of servers and volumes per server a pool was deployed with
+func statefulSetGeometry(ss *appsv1.StatefulSet, pool *miniov2.Pool) (servers int32, volumesPerServer int32) {
+	servers = 1
+	if ss.Spec.Replicas != nil {
+		servers = *ss.Spec.Replicas
+	}
+	name := miniov2.MinIOVolumeName
+	if pool.VolumeClaimTemplate != nil {
+		name = pool.VolumeClaimTemplate.Name
+	}
+	for _, container := range ss.Spec.Template.Spec.Containers {
+		if container.Name != miniov2.MinIOServerName {
+			continue
+		}
+		for _, mount := range container.VolumeMounts {
+			if n, ok := strings.CutPrefix(mount.Name, name); ok && n != "" {
+				if _, err := strconv.ParseUint(n, 10, 32); err == nil {
+					volumesPerServer++
+				}
+			}
+		}
+	}
+	return servers, volumesPerServer
+}
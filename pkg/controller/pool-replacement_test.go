@@ -0,0 +1,337 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	miniofake "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	"github.com/minio/operator/pkg/resources/statefulsets"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_statefulSetGeometry(t *testing.T) {
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "ns"},
+		Spec: miniov2.TenantSpec{
+			Configuration: &corev1.LocalObjectReference{Name: "myminio-env-configuration"},
+			Pools: []miniov2.Pool{
+				{
+					Name:             "pool-0",
+					Servers:          4,
+					VolumesPerServer: 2,
+					VolumeClaimTemplate: &corev1.PersistentVolumeClaim{
+						ObjectMeta: metav1.ObjectMeta{Name: "data"},
+					},
+				},
+				{
+					Name:             "pool-1",
+					Servers:          1,
+					VolumesPerServer: 1,
+				},
+			},
+		},
+	}
+	tenant.EnsureDefaults()
+	for _, pool := range tenant.Spec.Pools {
+		ss := statefulsets.NewPool(&statefulsets.NewPoolArgs{
+			Tenant:      tenant,
+			Pool:        &pool,
+			PoolStatus:  &miniov2.PoolStatus{},
+			ServiceName: tenant.MinIOHLServiceName(),
+		})
+		servers, volumes := statefulSetGeometry(ss, &pool)
+		if servers != pool.Servers || volumes != pool.VolumesPerServer {
+			t.Errorf("statefulSetGeometry() = %d servers %d volumes, want %d servers %d volumes", servers, volumes, pool.Servers, pool.VolumesPerServer)
+		}
+	}
+}
+
+func Test_nextPoolReplacementName(t *testing.T) {
+	tenant := &miniov2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "myminio"}}
+	pool := &miniov2.Pool{Name: "pool-0"}
+	tests := []struct {
+		ssName string
+		want   string
+	}{
+		{ssName: "myminio-pool-0", want: "myminio-pool-0-r1"},
+		{ssName: "myminio-pool-0-r1", want: "myminio-pool-0-r2"},
+		{ssName: "myminio-pool-0-r9", want: "myminio-pool-0-r10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ssName, func(t *testing.T) {
+			if got := nextPoolReplacementName(tenant, pool, tt.ssName); got != tt.want {
+				t.Errorf("nextPoolReplacementName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakePoolsServer serves the health, pools and service APIs of a MinIO deployment
+type fakePoolsServer struct {
+	mu             sync.Mutex
+	pools          []madmin.PoolStatus
+	decommissioned []string
+	restarts       int
+}
+
+func (s *fakePoolsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.URL.Path == "/minio/health/cluster":
+	case strings.HasSuffix(r.URL.Path, "/pools/list"):
+		json.NewEncoder(w).Encode(s.pools)
+	case strings.HasSuffix(r.URL.Path, "/pools/decommission"):
+		for i := range s.pools {
+			if s.pools[i].CmdLine == r.URL.Query().Get("pool") {
+				s.pools[i].Decommission = &madmin.PoolDecommissionInfo{StartTime: time.Now()}
+				s.decommissioned = append(s.decommissioned, s.pools[i].CmdLine)
+			}
+		}
+	case strings.HasSuffix(r.URL.Path, "/pools/status"):
+		for _, pool := range s.pools {
+			if pool.CmdLine == r.URL.Query().Get("pool") {
+				json.NewEncoder(w).Encode(pool)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case strings.HasSuffix(r.URL.Path, "/service"):
+		s.restarts++
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newFakePoolReplacementController returns a controller whose MinIO clients all reach the given server
+func newFakePoolReplacementController(t *testing.T, server http.Handler, tenant *miniov2.Tenant, objects ...runtime.Object) *Controller {
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	dialer := &net.Dialer{}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range objects {
+		if ss, ok := obj.(*appsv1.StatefulSet); ok {
+			if err := indexer.Add(ss); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return &Controller{
+		kubeClientSet:     fake.NewSimpleClientset(objects...),
+		minioClientSet:    miniofake.NewSimpleClientset(tenant),
+		statefulSetLister: appslisters.NewStatefulSetLister(indexer),
+		recorder:          record.NewFakeRecorder(20),
+		transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, httpServer.Listener.Addr().String())
+			},
+		},
+	}
+}
+
+// newReplacedPoolTenant returns a tenant whose pool-0 is deployed with 2 servers of 2 volumes and now asks for 4 servers
+// of 4 volumes, along with the statefulset of the deployed pool
+func newReplacedPoolTenant(servers int32) (*miniov2.Tenant, *appsv1.StatefulSet) {
+	autoCert := false
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns"},
+		Spec: miniov2.TenantSpec{
+			Configuration:        &corev1.LocalObjectReference{Name: "myminio-env-configuration"},
+			RequestAutoCert:      &autoCert,
+			AllowPoolReplacement: true,
+			Pools:                []miniov2.Pool{{Name: "pool-0", Servers: 4, VolumesPerServer: 4}},
+		},
+		Status: miniov2.TenantStatus{
+			CurrentState: StatusInitialized,
+			Pools:        []miniov2.PoolStatus{{SSName: "myminio-pool-0", State: miniov2.PoolInitialized}},
+		},
+	}
+	tenant.EnsureDefaults()
+	deployed := tenant.Spec.Pools[0]
+	deployed.Servers = servers
+	deployed.VolumesPerServer = 2
+	ss := statefulsets.NewPool(&statefulsets.NewPoolArgs{
+		Tenant:      tenant,
+		Pool:        &deployed,
+		PoolStatus:  &tenant.Status.Pools[0],
+		ServiceName: tenant.MinIOHLServiceName(),
+	})
+	ss.Name = "myminio-pool-0"
+	ss.Namespace = tenant.Namespace
+	return tenant, ss
+}
+
+func TestPoolReplacement(t *testing.T) {
+	ctx := context.Background()
+	tenant, ss := newReplacedPoolTenant(2)
+	spec := tenant.Spec.DeepCopy()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio-pool-0-0", Namespace: "tenant-ns", Labels: map[string]string{miniov2.PoolLabel: "pool-0"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	cmdLine := "http://myminio-pool-0-{0...1}.myminio-hl.tenant-ns.svc.cluster.local/export{0...1}"
+	server := &fakePoolsServer{pools: []madmin.PoolStatus{{ID: 0, CmdLine: cmdLine}}}
+	c := newFakePoolReplacementController(t, server, tenant, ss, pod)
+	tenantConfiguration := map[string][]byte{"accesskey": []byte("root"), "secretkey": []byte("root-secret")}
+
+	current := func() *miniov2.Tenant {
+		t.Helper()
+		stored, err := c.minioClientSet.MinioV2().Tenants("tenant-ns").Get(ctx, "myminio", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored.Spec = *spec.DeepCopy()
+		return stored
+	}
+	sync := func() error {
+		_, err := c.checkForPoolReplacement(ctx, "tenant-ns/myminio", current(), tenantConfiguration)
+		return err
+	}
+	replacedPool := func() miniov2.PoolStatus {
+		t.Helper()
+		updated := current()
+		i := poolStatusIndex(updated, "myminio-pool-0")
+		if i < 0 || updated.Status.Pools[i].Replacement == nil {
+			t.Fatalf("expected pool myminio-pool-0 to be replaced, got %+v", updated.Status.Pools)
+		}
+		return updated.Status.Pools[i]
+	}
+	setPoolState := func(ssName string, state miniov2.PoolState) {
+		t.Helper()
+		updated := current()
+		if i := poolStatusIndex(updated, ssName); i >= 0 {
+			updated.Status.Pools[i].State = state
+		} else {
+			updated.Status.Pools = append(updated.Status.Pools, miniov2.PoolStatus{SSName: ssName, State: state})
+		}
+		if _, err := c.minioClientSet.MinioV2().Tenants("tenant-ns").UpdateStatus(ctx, updated, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertStatefulSet := func(exists bool) {
+		t.Helper()
+		_, err := c.kubeClientSet.AppsV1().StatefulSets("tenant-ns").Get(ctx, "myminio-pool-0", metav1.GetOptions{})
+		if exists && err != nil {
+			t.Fatalf("expected the replaced statefulset to be kept, got %v", err)
+		}
+		if !exists && !k8serrors.IsNotFound(err) {
+			t.Fatalf("expected the replaced statefulset to be deleted, got %v", err)
+		}
+	}
+
+	// the replacement starts with the geometry and the arguments of the deployed pool
+	if err := sync(); err != nil {
+		t.Fatal(err)
+	}
+	pstatus := replacedPool()
+	replacement := pstatus.Replacement
+	if replacement.Stage != miniov2.PoolReplacementCreating || replacement.SSName != "myminio-pool-0-r1" ||
+		replacement.Servers != 2 || replacement.VolumesPerServer != 2 || replacement.CmdLine != cmdLine {
+		t.Fatalf("expected the replacement by myminio-pool-0-r1 to be created, got %+v", replacement)
+	}
+	if err := sync(); err != nil {
+		t.Fatal(err)
+	}
+	if stage := replacedPool().Replacement.Stage; stage != miniov2.PoolReplacementCreating {
+		t.Fatalf("expected stage %s until the new pool is created, got %s", miniov2.PoolReplacementCreating, stage)
+	}
+
+	setPoolState("myminio-pool-0-r1", miniov2.PoolCreated)
+	if err := sync(); err != nil {
+		t.Fatal(err)
+	}
+	if stage := replacedPool().Replacement.Stage; stage != miniov2.PoolReplacementInitializing {
+		t.Fatalf("expected stage %s, got %s", miniov2.PoolReplacementInitializing, stage)
+	}
+
+	// the replaced pool is decommissioned once MinIO initialized the new pool
+	setPoolState("myminio-pool-0-r1", miniov2.PoolInitialized)
+	if err := sync(); !errors.Is(err, ErrPoolDecommissioning) {
+		t.Fatalf("expected the pool to be decommissioning, got %v", err)
+	}
+	pstatus = replacedPool()
+	if pstatus.Replacement.Stage != miniov2.PoolReplacementDecommissioning || pstatus.State != miniov2.PoolDecommissioning {
+		t.Fatalf("expected stage %s, got %s with pool state %s", miniov2.PoolReplacementDecommissioning, pstatus.Replacement.Stage, pstatus.State)
+	}
+	if len(server.decommissioned) != 1 || server.decommissioned[0] != cmdLine {
+		t.Fatalf("expected the decommission of %s, got %v", cmdLine, server.decommissioned)
+	}
+	assertStatefulSet(true)
+
+	// MinIO restarts without the decommissioned pool before its statefulset is removed
+	server.mu.Lock()
+	server.pools[0].Decommission.Complete = true
+	server.mu.Unlock()
+	if err := sync(); !errors.Is(err, ErrPoolDecommissioning) {
+		t.Fatalf("expected the pool to be decommissioning, got %v", err)
+	}
+	pstatus = replacedPool()
+	if pstatus.Replacement.Stage != miniov2.PoolReplacementRemoving || pstatus.State != miniov2.PoolDecommissioned {
+		t.Fatalf("expected stage %s, got %s with pool state %s", miniov2.PoolReplacementRemoving, pstatus.Replacement.Stage, pstatus.State)
+	}
+	assertStatefulSet(true)
+
+	if err := sync(); !errors.Is(err, ErrMinIORestarting) {
+		t.Fatalf("expected MinIO to restart, got %v", err)
+	}
+	assertStatefulSet(false)
+	if server.restarts != 1 {
+		t.Errorf("expected MinIO to be restarted once, got %d", server.restarts)
+	}
+	if updated := current(); len(updated.Status.Pools) != 1 || updated.Status.Pools[0].SSName != "myminio-pool-0-r1" {
+		t.Errorf("expected only the new pool to be left, got %+v", updated.Status.Pools)
+	}
+}
+
+func TestPoolReplacementStandalone(t *testing.T) {
+	ctx := context.Background()
+	tenant, ss := newReplacedPoolTenant(1)
+	server := &fakePoolsServer{}
+	c := newFakePoolReplacementController(t, server, tenant, ss)
+
+	if _, err := c.checkForPoolReplacement(ctx, "tenant-ns/myminio", tenant.DeepCopy(), nil); err == nil {
+		t.Fatal("expected the replacement of a standalone pool to be refused")
+	}
+	updated, err := c.minioClientSet.MinioV2().Tenants("tenant-ns").Get(ctx, "myminio", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.CurrentState != StatusPoolReplacementNotAllowed {
+		t.Errorf("expected state %q, got %q", StatusPoolReplacementNotAllowed, updated.Status.CurrentState)
+	}
+	if poolReplacementInProgress(updated) {
+		t.Errorf("expected no pool to be replaced, got %+v", updated.Status.Pools)
+	}
+}
@@ -110,12 +110,24 @@ func (c *Controller) restartInitializedPool(ctx context.Context, tenant *miniov2
 			return err
 		}
 	}
+	// a pool being replaced has pods in two statefulsets, prefer the pods of an initialized one
+	initialized := map[string]bool{}
+	for _, poolStatus := range tenant.Status.Pools {
+		initialized[poolStatus.SSName] = poolStatus.State == miniov2.PoolInitialized
+	}
 	var livePod *corev1.Pod
-	for _, p := range livePods.Items {
-		if p.Status.Phase == corev1.PodRunning {
-			livePod = &p
+	for i := range livePods.Items {
+		p := &livePods.Items[i]
+		if p.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if owner := metav1.GetControllerOf(p); owner != nil && initialized[owner.Name] {
+			livePod = p
 			break
 		}
+		if livePod == nil {
+			livePod = p
+		}
 	}
 	if livePod == nil {
 		return fmt.Errorf("no running pods found for statefulsets %s", pool.Name)
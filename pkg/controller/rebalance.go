@@ -0,0 +1,183 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// MinIO reports these statuses for each pool taking part of a rebalance
+const (
+	minioRebalanceNone      = "None"
+	minioRebalanceCompleted = "Completed"
+	minioRebalanceFailed    = "Failed"
+)
+
+// rebalanceEnabled returns true if the tenant opted in to rebalance the pools after an expansion
+func rebalanceEnabled(tenant *miniov2.Tenant) bool {
+	return tenant.Spec.PoolRebalance != nil && tenant.Spec.PoolRebalance.Enabled
+}
+
+// rebalanceStopRequested returns true if the tenant is annotated to stop the rebalance or the policy was disabled
+func rebalanceStopRequested(tenant *miniov2.Tenant) bool {
+	return !rebalanceEnabled(tenant) || tenant.Annotations[miniov2.PoolRebalanceAnnotation] == miniov2.PoolRebalanceStop
+}
+
+// checkForPoolRebalance starts, stops or resumes the pool rebalance requested by an expansion and records its progress
+func (c *Controller) checkForPoolRebalance(ctx context.Context, key string, tenant *miniov2.Tenant, adminClnt *madmin.AdminClient) (*miniov2.Tenant, error) {
+	rebalance := tenant.Status.Rebalance
+	if rebalance == nil {
+		return tenant, nil
+	}
+	var err error
+	now := metav1.Now()
+	switch rebalance.State {
+	case miniov2.PoolRebalancePending, miniov2.PoolRebalanceStopped:
+		if rebalanceStopRequested(tenant) {
+			// a pending rebalance that was opted out of is simply forgotten
+			if !rebalanceEnabled(tenant) && rebalance.State == miniov2.PoolRebalancePending {
+				tenant.Status.Rebalance = nil
+				return c.updatePoolStatus(ctx, tenant)
+			}
+			if rebalance.State == miniov2.PoolRebalancePending {
+				rebalance.State = miniov2.PoolRebalanceStopped
+				rebalance.StopTime = &now
+				return c.updatePoolStatus(ctx, tenant)
+			}
+			return tenant, nil
+		}
+		id, err := adminClnt.RebalanceStart(ctx)
+		if err != nil {
+			c.recorder.Event(tenant, corev1.EventTypeWarning, "PoolRebalanceFailed", fmt.Sprintf("Pool rebalance failed to start: %s", err))
+			return tenant, err
+		}
+		if rebalance.State == miniov2.PoolRebalanceStopped {
+			klog.Infof("'%s' resumed pool rebalance %s", key, id)
+			c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolRebalanceResumed", "Pool rebalance resumed")
+		} else {
+			klog.Infof("'%s' started pool rebalance %s", key, id)
+			c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolRebalanceStarted", "Pool rebalance started")
+		}
+		rebalance.ID = id
+		rebalance.State = miniov2.PoolRebalanceRunning
+		rebalance.StartTime = &now
+		rebalance.StopTime = nil
+		return c.updatePoolStatus(ctx, tenant)
+	case miniov2.PoolRebalanceRunning:
+		if rebalanceStopRequested(tenant) {
+			if err = adminClnt.RebalanceStop(ctx); err != nil {
+				return tenant, err
+			}
+			klog.Infof("'%s' stopped pool rebalance %s", key, rebalance.ID)
+			c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolRebalanceStopped", "Pool rebalance stopped")
+			rebalance.State = miniov2.PoolRebalanceStopped
+			rebalance.StopTime = &now
+			return c.updatePoolStatus(ctx, tenant)
+		}
+		rs, err := adminClnt.RebalanceStatus(ctx)
+		if err != nil {
+			return tenant, err
+		}
+		pools, err := adminClnt.ListPoolsStatus(ctx)
+		if err != nil {
+			return tenant, err
+		}
+		updatePoolRebalanceStatus(tenant, rs, pools, now.Time)
+		switch rebalance.State {
+		case miniov2.PoolRebalanceCompleted:
+			klog.Infof("'%s' completed pool rebalance %s", key, rebalance.ID)
+			c.recorder.Event(tenant, corev1.EventTypeNormal, "PoolRebalanceCompleted", "Pool rebalance completed")
+		case miniov2.PoolRebalanceStopped:
+			// not stopped through the tenant, it's resumed on the next sync unless the tenant is annotated
+			klog.Infof("'%s' pool rebalance %s was stopped in MinIO", key, rebalance.ID)
+			c.recorder.Event(tenant, corev1.EventTypeWarning, "PoolRebalanceStopped", "Pool rebalance stopped in MinIO before completing")
+		case miniov2.PoolRebalanceFailed:
+			c.recorder.Event(tenant, corev1.EventTypeWarning, "PoolRebalanceFailed", "Pool rebalance failed")
+		}
+		return c.updatePoolStatus(ctx, tenant)
+	}
+	return tenant, nil
+}
+
+// updatePoolRebalanceStatus copies the rebalance progress reported by MinIO into the tenant status, marking the
+// rebalance as completed, stopped or failed once MinIO stopped it. The MinIO pools are matched to their statefulsets
+// by their command line, the pools of MinIO don't follow the order of the tenant status once a pool was decommissioned.
+func updatePoolRebalanceStatus(tenant *miniov2.Tenant, rs madmin.RebalanceStatus, pools []madmin.PoolStatus, now time.Time) {
+	rebalance := tenant.Status.Rebalance
+	lastUpdate := metav1.NewTime(now)
+	rebalance.LastUpdate = &lastUpdate
+	if rs.ID != "" {
+		rebalance.ID = rs.ID
+	}
+
+	ssNames := map[int]string{}
+	for _, poolStatus := range tenant.Status.Pools {
+		if pool, ok := minioPoolForStatefulSet(pools, poolStatus.SSName); ok {
+			ssNames[pool.ID] = poolStatus.SSName
+		}
+	}
+
+	failed, completed := false, true
+	rebalance.Pools = nil
+	for _, pool := range rs.Pools {
+		progress := miniov2.PoolRebalanceProgress{
+			ID:             int32(pool.ID),
+			SSName:         ssNames[pool.ID],
+			Status:         pool.Status,
+			UsedPercentage: fmt.Sprintf("%.2f", pool.Used*100),
+			Objects:        int64(pool.Progress.NumObjects),
+			Versions:       int64(pool.Progress.NumVersions),
+			Bytes:          int64(pool.Progress.Bytes),
+		}
+		if pool.Progress.ETA > 0 && pool.Status != minioRebalanceCompleted {
+			eta := metav1.NewTime(now.Add(pool.Progress.ETA))
+			progress.ETA = &eta
+		}
+		switch pool.Status {
+		case minioRebalanceFailed:
+			failed = true
+		case minioRebalanceCompleted, minioRebalanceNone, "":
+		default:
+			// the pool was still moving its data when the rebalance was stopped
+			completed = false
+		}
+		rebalance.Pools = append(rebalance.Pools, progress)
+	}
+
+	switch {
+	case failed:
+		rebalance.State = miniov2.PoolRebalanceFailed
+	case rs.StoppedAt.IsZero():
+	case completed:
+		rebalance.State = miniov2.PoolRebalanceCompleted
+	default:
+		rebalance.State = miniov2.PoolRebalanceStopped
+	}
+	if rebalance.State != miniov2.PoolRebalanceRunning {
+		stopTime := lastUpdate
+		if !rs.StoppedAt.IsZero() {
+			stopTime = metav1.NewTime(rs.StoppedAt)
+		}
+		rebalance.StopTime = &stopTime
+	}
+}
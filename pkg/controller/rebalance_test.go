@@ -0,0 +1,129 @@
+// Copyright (C) 2024, MinIO, Inc.
+//
+// This code is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License, version 3,
+// as published by the Free Software Foundation.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License, version 3,
+// along with this program.  If not, see <http://www.gnu.org/licenses/>
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+)
+
+func Test_updatePoolRebalanceStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	newTenant := func() *miniov2.Tenant {
+		return &miniov2.Tenant{
+			Status: miniov2.TenantStatus{
+				// the first pool was decommissioned, MinIO numbers the remaining pools from 0
+				Pools: []miniov2.PoolStatus{
+					{SSName: "myminio-pool-0", State: miniov2.PoolInitialized},
+					{SSName: "myminio-pool-1", State: miniov2.PoolInitialized},
+					{SSName: "myminio-pool-2", State: miniov2.PoolInitialized},
+				},
+				Rebalance: &miniov2.PoolRebalanceStatus{
+					ID:    "rebalance-id",
+					State: miniov2.PoolRebalanceRunning,
+				},
+			},
+		}
+	}
+	pools := []madmin.PoolStatus{
+		{ID: 0, CmdLine: "https://myminio-pool-1-{0...3}.myminio-hl.tenant-ns.svc.cluster.local/export{0...3}"},
+		{ID: 1, CmdLine: "https://myminio-pool-2-{0...3}.myminio-hl.tenant-ns.svc.cluster.local/export{0...3}"},
+	}
+	tests := []struct {
+		name      string
+		status    madmin.RebalanceStatus
+		wantState miniov2.PoolRebalanceState
+	}{
+		{
+			name: "running",
+			status: madmin.RebalanceStatus{
+				ID: "rebalance-id",
+				Pools: []madmin.RebalancePoolStatus{
+					{ID: 0, Status: "Started", Used: 0.8, Progress: madmin.RebalPoolProgress{NumObjects: 10, Bytes: 1024, ETA: time.Hour}},
+					{ID: 1, Status: "None", Used: 0.1},
+				},
+			},
+			wantState: miniov2.PoolRebalanceRunning,
+		},
+		{
+			name: "completed",
+			status: madmin.RebalanceStatus{
+				ID:        "rebalance-id",
+				StoppedAt: now,
+				Pools: []madmin.RebalancePoolStatus{
+					{ID: 0, Status: "Completed", Used: 0.45},
+					{ID: 1, Status: "None", Used: 0.45},
+				},
+			},
+			wantState: miniov2.PoolRebalanceCompleted,
+		},
+		{
+			name: "failed",
+			status: madmin.RebalanceStatus{
+				ID:        "rebalance-id",
+				StoppedAt: now,
+				Pools: []madmin.RebalancePoolStatus{
+					{ID: 0, Status: "Failed", Used: 0.7},
+					{ID: 1, Status: "None", Used: 0.2},
+				},
+			},
+			wantState: miniov2.PoolRebalanceFailed,
+		},
+		{
+			name: "stopped",
+			status: madmin.RebalanceStatus{
+				ID:        "rebalance-id",
+				StoppedAt: now,
+				Pools: []madmin.RebalancePoolStatus{
+					{ID: 0, Status: "Stopped", Used: 0.6},
+					{ID: 1, Status: "None", Used: 0.3},
+				},
+			},
+			wantState: miniov2.PoolRebalanceStopped,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := newTenant()
+			updatePoolRebalanceStatus(tenant, tt.status, pools, now)
+			rebalance := tenant.Status.Rebalance
+			if rebalance.State != tt.wantState {
+				t.Errorf("updatePoolRebalanceStatus() state = %s, want %s", rebalance.State, tt.wantState)
+			}
+			if len(rebalance.Pools) != 2 {
+				t.Fatalf("updatePoolRebalanceStatus() got %d pools, want 2", len(rebalance.Pools))
+			}
+			if rebalance.Pools[0].SSName != "myminio-pool-1" || rebalance.Pools[1].SSName != "myminio-pool-2" {
+				t.Errorf("updatePoolRebalanceStatus() pool ssNames = %s, %s", rebalance.Pools[0].SSName, rebalance.Pools[1].SSName)
+			}
+			if (rebalance.StopTime != nil) != (tt.wantState != miniov2.PoolRebalanceRunning) {
+				t.Errorf("updatePoolRebalanceStatus() unexpected stop time %v", rebalance.StopTime)
+			}
+		})
+	}
+
+	tenant := newTenant()
+	updatePoolRebalanceStatus(tenant, tests[0].status, pools, now)
+	pool := tenant.Status.Rebalance.Pools[0]
+	if pool.UsedPercentage != "80.00" || pool.Objects != 10 || pool.Bytes != 1024 {
+		t.Errorf("updatePoolRebalanceStatus() progress not copied: %+v", pool)
+	}
+	if pool.ETA == nil || !pool.ETA.Time.Equal(now.Add(time.Hour)) {
+		t.Errorf("updatePoolRebalanceStatus() ETA = %v, want %v", pool.ETA, now.Add(time.Hour))
+	}
+}
@@ -18,13 +18,195 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 )
 
+// Reasons of the tenant conditions
+const (
+	ReasonInitialized           = "Initialized"
+	ReasonProvisioning          = "Provisioning"
+	ReasonWaitingForCertificate = "WaitingForCertificate"
+	ReasonWaitingForHealthy     = "WaitingForHealthy"
+	ReasonUpdating              = "Updating"
+	ReasonDecommissioning       = "Decommissioning"
+	ReasonReplacingPool         = "ReplacingPool"
+	ReasonDeleting              = "Deleting"
+	ReasonPaused                = "Paused"
+	ReasonHibernated            = "Hibernated"
+	ReasonWaitingForMaintenance = "WaitingForMaintenanceWindow"
+	ReasonReconcileFailed       = "ReconcileFailed"
+	ReasonPoolsInitialized      = "PoolsInitialized"
+	ReasonPoolsNotInitialized   = "PoolsNotInitialized"
+	ReasonReducedAvailability   = "ReducedAvailability"
+	ReasonUnavailable           = "Unavailable"
+)
+
+// tenantStateReasons gives the reason of the states the Operator goes through while reconciling a tenant, any other
+// state is an error preventing the reconciliation
+var tenantStateReasons = map[string]string{
+	StatusInitialized:                ReasonInitialized,
+	StatusProvisioningCIService:      ReasonProvisioning,
+	StatusProvisioningHLService:      ReasonProvisioning,
+	StatusProvisioningStatefulSet:    ReasonProvisioning,
+	StatusProvisioningConsoleService: ReasonProvisioning,
+	StatusProvisioningKESStatefulSet: ReasonProvisioning,
+	StatusProvisioningInitialUsers:   ReasonProvisioning,
+	StatusProvisioningDefaultBuckets: ReasonProvisioning,
+	StatusWaitingMinIOIsHealthy:      ReasonWaitingForHealthy,
+	StatusWaitingMinIOCert:           ReasonWaitingForCertificate,
+	StatusWaitingMinIOClientCert:     ReasonWaitingForCertificate,
+	StatusWaitingKESCert:             ReasonWaitingForCertificate,
+	StatusUpdatingMinIOVersion:       ReasonUpdating,
+	StatusUpgradePreflightFailed:     ReasonWaitingForHealthy,
+	StatusVerifyingMinIOUpgrade:      ReasonUpdating,
+	StatusRollingBackMinIOUpgrade:    ReasonUpdating,
+	StatusUpdatingKES:                ReasonUpdating,
+	StatusRestartingMinIO:            ReasonUpdating,
+	StatusDecommissioningPool:        ReasonDecommissioning,
+	StatusReplacingPool:              ReasonReplacingPool,
+	StatusDeletingTenant:             ReasonDeleting,
+	StatusPaused:                     ReasonPaused,
+	StatusHibernated:                 ReasonHibernated,
+	StatusResumingFromHibernation:    ReasonWaitingForHealthy,
+	StatusWaitingMaintenanceWindow:   ReasonWaitingForMaintenance,
+}
+
+// setTenantConditions derives the conditions of the tenant from the state the Operator reports
+func setTenantConditions(tenant *miniov2.Tenant, status *miniov2.TenantStatus, currentState string) {
+	reason, ok := tenantStateReasons[currentState]
+	if !ok {
+		reason = ReasonReconcileFailed
+	}
+	condition := func(conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			ObservedGeneration: tenant.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+	status.ObservedGeneration = tenant.Generation
+
+	switch reason {
+	case ReasonInitialized:
+		condition(miniov2.TenantConditionReady, metav1.ConditionTrue, reason, currentState)
+		condition(miniov2.TenantConditionProgressing, metav1.ConditionFalse, reason, currentState)
+		condition(miniov2.TenantConditionDegraded, metav1.ConditionFalse, reason, currentState)
+		condition(miniov2.TenantConditionUpgradeInProgress, metav1.ConditionFalse, reason, currentState)
+	case ReasonPaused:
+		// nothing is deployed or changed while paused, the readiness is left as last observed
+		condition(miniov2.TenantConditionProgressing, metav1.ConditionFalse, reason, currentState)
+	case ReasonHibernated:
+		condition(miniov2.TenantConditionReady, metav1.ConditionFalse, reason, currentState)
+		condition(miniov2.TenantConditionProgressing, metav1.ConditionFalse, reason, currentState)
+		condition(miniov2.TenantConditionDegraded, metav1.ConditionFalse, reason, currentState)
+	case ReasonWaitingForMaintenance:
+		// MinIO keeps serving on the current spec until the window opens
+		condition(miniov2.TenantConditionReady, metav1.ConditionTrue, reason, currentState)
+		condition(miniov2.TenantConditionProgressing, metav1.ConditionFalse, reason, currentState)
+		condition(miniov2.TenantConditionDegraded, metav1.ConditionFalse, reason, currentState)
+	case ReasonReconcileFailed:
+		condition(miniov2.TenantConditionReady, metav1.ConditionFalse, reason, currentState)
+		condition(miniov2.TenantConditionProgressing, metav1.ConditionFalse, reason, currentState)
+		condition(miniov2.TenantConditionDegraded, metav1.ConditionTrue, reason, currentState)
+	default:
+		condition(miniov2.TenantConditionReady, metav1.ConditionFalse, reason, currentState)
+		condition(miniov2.TenantConditionProgressing, metav1.ConditionTrue, reason, currentState)
+		condition(miniov2.TenantConditionDegraded, metav1.ConditionFalse, reason, currentState)
+	}
+	if reason == ReasonUpdating {
+		condition(miniov2.TenantConditionUpgradeInProgress, metav1.ConditionTrue, reason, currentState)
+	}
+
+	if !tenant.TLS() {
+		meta.RemoveStatusCondition(&status.Conditions, miniov2.TenantConditionCertificatesReady)
+	} else if reason == ReasonWaitingForCertificate {
+		condition(miniov2.TenantConditionCertificatesReady, metav1.ConditionFalse, reason, currentState)
+	} else if reason == ReasonInitialized {
+		condition(miniov2.TenantConditionCertificatesReady, metav1.ConditionTrue, reason, "TLS certificates are available")
+	}
+
+	switch {
+	case !tenant.HasKESEnabled():
+		meta.RemoveStatusCondition(&status.Conditions, miniov2.TenantConditionKESReady)
+	case currentState == StatusProvisioningKESStatefulSet || currentState == StatusUpdatingKES || currentState == StatusWaitingKESCert:
+		condition(miniov2.TenantConditionKESReady, metav1.ConditionFalse, reason, currentState)
+	case reason == ReasonInitialized:
+		condition(miniov2.TenantConditionKESReady, metav1.ConditionTrue, reason, "KES is deployed")
+	}
+
+	setHealthConditions(status, currentState)
+	setPoolsInitializedCondition(tenant, status)
+}
+
+// setHealthConditions factors the health the Operator last observed into the Ready and Degraded conditions of a tenant
+// it has nothing left to change: a red tenant lost its quorum and isn't ready, a yellow one serves with reduced
+// resilience and is degraded
+func setHealthConditions(status *miniov2.TenantStatus, currentState string) {
+	reason := tenantStateReasons[currentState]
+	if reason != ReasonInitialized && reason != ReasonWaitingForMaintenance {
+		return
+	}
+	ready, degraded, message := metav1.ConditionTrue, metav1.ConditionFalse, currentState
+	switch status.HealthStatus {
+	case miniov2.HealthStatusRed:
+		ready, degraded, reason = metav1.ConditionFalse, metav1.ConditionTrue, ReasonUnavailable
+	case miniov2.HealthStatusYellow:
+		degraded, reason = metav1.ConditionTrue, ReasonReducedAvailability
+	}
+	if degraded == metav1.ConditionTrue && status.HealthMessage != "" {
+		message = status.HealthMessage
+	}
+	condition := func(conditionType string, conditionStatus metav1.ConditionStatus) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			ObservedGeneration: status.ObservedGeneration,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+	condition(miniov2.TenantConditionReady, ready)
+	condition(miniov2.TenantConditionDegraded, degraded)
+}
+
+// setPoolsInitializedCondition sets whether all the pools of the tenant, except the ones being decommissioned or
+// replaced, have been observed online
+func setPoolsInitializedCondition(tenant *miniov2.Tenant, status *miniov2.TenantStatus) {
+	initialized, total := 0, 0
+	for _, pool := range status.Pools {
+		if pool.Replacement != nil {
+			continue
+		}
+		switch pool.State {
+		case miniov2.PoolDecommissioning, miniov2.PoolDecommissioned:
+			continue
+		case miniov2.PoolInitialized:
+			initialized++
+		}
+		total++
+	}
+	condition := metav1.Condition{
+		Type:               miniov2.TenantConditionPoolsInitialized,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: tenant.Generation,
+		Reason:             ReasonPoolsNotInitialized,
+		Message:            fmt.Sprintf("%d of %d pools initialized", initialized, total),
+	}
+	if total > 0 && initialized == total {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonPoolsInitialized
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
 func (c *Controller) updateTenantStatus(ctx context.Context, tenant *miniov2.Tenant, currentState string, availableReplicas int32) (*miniov2.Tenant, error) {
 	return c.updateTenantStatusWithRetry(ctx, tenant, currentState, availableReplicas, true)
 }
@@ -32,7 +214,8 @@ func (c *Controller) updateTenantStatus(ctx context.Context, tenant *miniov2.Ten
 func (c *Controller) updateTenantStatusWithRetry(ctx context.Context, tenant *miniov2.Tenant, currentState string, availableReplicas int32, retry bool) (*miniov2.Tenant, error) {
 	// If we are updating the tenant with the same status as before we are going to skip it as to avoid a resource number
 	// change and have the operator loop re-processing the tenant endlessly
-	if tenant.Status.CurrentState == currentState && tenant.Status.AvailableReplicas == availableReplicas {
+	if tenant.Status.CurrentState == currentState && tenant.Status.AvailableReplicas == availableReplicas &&
+		tenant.Status.ObservedGeneration == tenant.Generation {
 		return tenant, nil
 	}
 	// NEVER modify objects from the store. It's a read-only, local cache.
@@ -42,6 +225,7 @@ func (c *Controller) updateTenantStatusWithRetry(ctx context.Context, tenant *mi
 	tenantCopy.Spec = miniov2.TenantSpec{}
 	tenantCopy.Status.AvailableReplicas = availableReplicas
 	tenantCopy.Status.CurrentState = currentState
+	setTenantConditions(tenant, &tenantCopy.Status, currentState)
 	// If the CustomResourceSubresources feature gate is not enabled,
 	// we must use Update instead of UpdateStatus to update the Status block of the Tenant resource.
 	// UpdateStatus will not allow changes to the Spec of the resource,
@@ -76,6 +260,8 @@ func (c *Controller) updatePoolStatusWithRetry(ctx context.Context, tenant *mini
 	tenantCopy.Spec = miniov2.TenantSpec{}
 	tenantCopy.Status = *tenant.Status.DeepCopy()
 	tenantCopy.Status.Pools = tenant.Status.Pools
+	setHealthConditions(&tenantCopy.Status, tenantCopy.Status.CurrentState)
+	setPoolsInitializedCondition(tenant, &tenantCopy.Status)
 	// If the CustomResourceSubresources feature gate is not enabled,
 	// we must use Update instead of UpdateStatus to update the Status block of the Tenant resource.
 	// UpdateStatus will not allow changes to the Spec of the resource,
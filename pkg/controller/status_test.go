@@ -0,0 +1,154 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	miniofake "github.com/minio/operator/pkg/client/clientset/versioned/fake"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetTenantConditions(t *testing.T) {
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns", Generation: 3},
+		Spec: miniov2.TenantSpec{
+			KES: &miniov2.KESConfig{},
+		},
+		Status: miniov2.TenantStatus{
+			Pools: []miniov2.PoolStatus{{SSName: "myminio-pool-0", State: miniov2.PoolInitialized}, {SSName: "myminio-pool-1", State: miniov2.PoolCreated}},
+		},
+	}
+	status := &tenant.Status
+	expect := func(conditionType string, conditionStatus metav1.ConditionStatus, reason string) {
+		t.Helper()
+		condition := meta.FindStatusCondition(status.Conditions, conditionType)
+		if condition == nil {
+			t.Fatalf("condition %s is not set", conditionType)
+		}
+		if condition.Status != conditionStatus || condition.Reason != reason || condition.ObservedGeneration != 3 {
+			t.Errorf("unexpected condition %+v", condition)
+		}
+	}
+
+	setTenantConditions(tenant, status, StatusWaitingKESCert)
+	expect(miniov2.TenantConditionReady, metav1.ConditionFalse, ReasonWaitingForCertificate)
+	expect(miniov2.TenantConditionProgressing, metav1.ConditionTrue, ReasonWaitingForCertificate)
+	expect(miniov2.TenantConditionCertificatesReady, metav1.ConditionFalse, ReasonWaitingForCertificate)
+	expect(miniov2.TenantConditionKESReady, metav1.ConditionFalse, ReasonWaitingForCertificate)
+	expect(miniov2.TenantConditionPoolsInitialized, metav1.ConditionFalse, ReasonPoolsNotInitialized)
+	if status.ObservedGeneration != 3 {
+		t.Errorf("expected the observed generation to be set, got %d", status.ObservedGeneration)
+	}
+
+	setTenantConditions(tenant, status, StatusUpdatingMinIOVersion)
+	expect(miniov2.TenantConditionUpgradeInProgress, metav1.ConditionTrue, ReasonUpdating)
+
+	setTenantConditions(tenant, status, "pool #0 cannot have 0 servers")
+	expect(miniov2.TenantConditionDegraded, metav1.ConditionTrue, ReasonReconcileFailed)
+	expect(miniov2.TenantConditionProgressing, metav1.ConditionFalse, ReasonReconcileFailed)
+	if condition := meta.FindStatusCondition(status.Conditions, miniov2.TenantConditionDegraded); condition.Message != "pool #0 cannot have 0 servers" {
+		t.Errorf("expected the error as message, got %s", condition.Message)
+	}
+
+	status.Pools[1].State = miniov2.PoolInitialized
+	setTenantConditions(tenant, status, StatusInitialized)
+	expect(miniov2.TenantConditionReady, metav1.ConditionTrue, ReasonInitialized)
+	expect(miniov2.TenantConditionProgressing, metav1.ConditionFalse, ReasonInitialized)
+	expect(miniov2.TenantConditionDegraded, metav1.ConditionFalse, ReasonInitialized)
+	expect(miniov2.TenantConditionUpgradeInProgress, metav1.ConditionFalse, ReasonInitialized)
+	expect(miniov2.TenantConditionCertificatesReady, metav1.ConditionTrue, ReasonInitialized)
+	expect(miniov2.TenantConditionKESReady, metav1.ConditionTrue, ReasonInitialized)
+	expect(miniov2.TenantConditionPoolsInitialized, metav1.ConditionTrue, ReasonPoolsInitialized)
+
+	// the health MinIO reports once the tenant is initialized
+	status.HealthStatus, status.HealthMessage = miniov2.HealthStatusYellow, HealthReduceAvailabilityMessage
+	setTenantConditions(tenant, status, StatusInitialized)
+	expect(miniov2.TenantConditionReady, metav1.ConditionTrue, ReasonReducedAvailability)
+	expect(miniov2.TenantConditionDegraded, metav1.ConditionTrue, ReasonReducedAvailability)
+	if condition := meta.FindStatusCondition(status.Conditions, miniov2.TenantConditionDegraded); condition.Message != HealthReduceAvailabilityMessage {
+		t.Errorf("expected the health message, got %s", condition.Message)
+	}
+	status.HealthStatus, status.HealthMessage = miniov2.HealthStatusRed, HealthUnavailableMessage
+	setTenantConditions(tenant, status, StatusInitialized)
+	expect(miniov2.TenantConditionReady, metav1.ConditionFalse, ReasonUnavailable)
+	expect(miniov2.TenantConditionDegraded, metav1.ConditionTrue, ReasonUnavailable)
+	// the state of a tenant being changed takes precedence over its health
+	setTenantConditions(tenant, status, StatusUpdatingMinIOVersion)
+	expect(miniov2.TenantConditionDegraded, metav1.ConditionFalse, ReasonUpdating)
+	status.HealthStatus, status.HealthMessage = miniov2.HealthStatusGreen, ""
+	setTenantConditions(tenant, status, StatusInitialized)
+	expect(miniov2.TenantConditionReady, metav1.ConditionTrue, ReasonInitialized)
+	expect(miniov2.TenantConditionDegraded, metav1.ConditionFalse, ReasonInitialized)
+
+	// KES turned off
+	tenant.Spec.KES = nil
+	setTenantConditions(tenant, status, StatusInitialized)
+	if meta.FindStatusCondition(status.Conditions, miniov2.TenantConditionKESReady) != nil {
+		t.Errorf("expected the KESReady condition to be removed")
+	}
+}
+
+func TestUpdateTenantStatusObservedGeneration(t *testing.T) {
+	ctx := context.Background()
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns", Generation: 1},
+	}
+	c := &Controller{minioClientSet: miniofake.NewSimpleClientset(tenant)}
+
+	updated, err := c.updateTenantStatus(ctx, tenant, StatusInitialized, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.ObservedGeneration != 1 || !meta.IsStatusConditionTrue(updated.Status.Conditions, miniov2.TenantConditionReady) {
+		t.Fatalf("unexpected status %+v", updated.Status)
+	}
+
+	// the same state is reported again once the spec changed
+	updated.Generation = 2
+	if updated, err = c.updateTenantStatus(ctx, updated, StatusInitialized, 4); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Status.ObservedGeneration != 2 {
+		t.Errorf("expected the observed generation to follow the spec, got %d", updated.Status.ObservedGeneration)
+	}
+}
+
+func TestUpdatePoolStatusHealthConditions(t *testing.T) {
+	ctx := context.Background()
+	tenant := &miniov2.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "tenant-ns", Generation: 1},
+	}
+	c := &Controller{minioClientSet: miniofake.NewSimpleClientset(tenant)}
+	updated, err := c.updateTenantStatus(ctx, tenant, StatusInitialized, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the health monitor only updates the health of the tenant, its conditions follow
+	updated.Status.HealthStatus, updated.Status.HealthMessage = miniov2.HealthStatusRed, HealthUnavailableMessage
+	if updated, err = c.updatePoolStatus(ctx, updated); err != nil {
+		t.Fatal(err)
+	}
+	if meta.IsStatusConditionTrue(updated.Status.Conditions, miniov2.TenantConditionReady) ||
+		!meta.IsStatusConditionTrue(updated.Status.Conditions, miniov2.TenantConditionDegraded) {
+		t.Errorf("expected a red tenant not to be ready and to be degraded, got %+v", updated.Status.Conditions)
+	}
+}
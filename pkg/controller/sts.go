@@ -264,6 +264,9 @@ func configureSTSServer(c *Controller) *http.Server {
 	router.Methods(http.MethodPost).
 		Path(STSEndpoint + "/{tenantNamespace}").
 		HandlerFunc(c.AssumeRoleWithWebIdentityHandler)
+	router.Methods(http.MethodPost).
+		Path(STSEndpoint + "/{tenantNamespace}/{tenantName}").
+		HandlerFunc(c.AssumeRoleWithWebIdentityHandler)
 
 	router.NotFoundHandler = http.NotFoundHandler()
 
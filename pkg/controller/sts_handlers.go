@@ -49,12 +49,12 @@ const (
 
 const contextLogKey = contextKeyType("operatorlog")
 
-// AssumeRoleWithWebIdentityHandler - POST /sts/{tenantNamespace}
+// AssumeRoleWithWebIdentityHandler - POST /sts/{tenantNamespace} or POST /sts/{tenantNamespace}/{tenantName}
 // AssumeRoleWithWebIdentity - implementation of AWS STS API.
 // Authenticates a Kubernetes Service accounts using a JWT Token
 // Evalues a PolicyBinding CRD as Mapping of the Minio Policies that the ServiceAccount can assume on a minio tenant
 // Eg:-
-// $ curl -k -X POST https://operator:9443/sts/{tenantNamespace} -d "Version=2011-06-15&Action=AssumeRoleWithWebIdentity&WebIdentityToken=<jwt>" -H "Content-Type: application/x-www-form-urlencoded"
+// $ curl -k -X POST https://operator:9443/sts/{tenantNamespace}/{tenantName} -d "Version=2011-06-15&Action=AssumeRoleWithWebIdentity&WebIdentityToken=<jwt>" -H "Content-Type: application/x-www-form-urlencoded"
 func (c *Controller) AssumeRoleWithWebIdentityHandler(w http.ResponseWriter, r *http.Request) {
 	routerVars := mux.Vars(r)
 	tenantNamespace := ""
@@ -63,6 +63,12 @@ func (c *Controller) AssumeRoleWithWebIdentityHandler(w http.ResponseWriter, r *
 		writeSTSErrorResponse(w, true, ErrSTSInvalidParameterValue, fmt.Errorf("Unable to unescape tenant namespace: %s", err))
 		return
 	}
+	// the tenant name is optional, it is only required when the namespace holds several tenants
+	tenantName, err := xhttp.UnescapeQueryPath(routerVars["tenantName"])
+	if err != nil {
+		writeSTSErrorResponse(w, true, ErrSTSInvalidParameterValue, fmt.Errorf("Unable to unescape tenant name: %s", err))
+		return
+	}
 
 	reqInfo := ReqInfo{
 		RequestID:       w.Header().Get(AmzRequestID),
@@ -152,7 +158,7 @@ func (c *Controller) AssumeRoleWithWebIdentityHandler(w http.ResponseWriter, r *
 	tenants, err := c.minioClientSet.MinioV2().Tenants(tenantNamespace).List(ctx, metav1.ListOptions{})
 
 	if err != nil || len(tenants.Items) == 0 {
-		if k8serrors.IsNotFound(err) {
+		if err == nil || k8serrors.IsNotFound(err) {
 			writeSTSErrorResponse(w, true, ErrSTSInvalidParameterValue, fmt.Errorf("No tenant found in namespace '%s'", tenantNamespace))
 			return
 		}
@@ -160,10 +166,17 @@ func (c *Controller) AssumeRoleWithWebIdentityHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	// Only one tenant is allowed in a single namespace, gathering the first tenant in the list
-	tenant := tenants.Items[0]
+	tenant, policyBindings, err := resolvePolicyBindingsTenant(tenants.Items, policyBindings, tenantName)
+	if err != nil {
+		writeSTSErrorResponse(w, true, ErrSTSInvalidParameterValue, err)
+		return
+	}
+	if len(policyBindings) == 0 {
+		writeSTSErrorResponse(w, true, ErrSTSAccessDenied, fmt.Errorf("Service account '%s' has no PolicyBindings for tenant '%s' in namespace '%s'", saAuthResult.Status.User.Username, tenant.Name, tenantNamespace))
+		return
+	}
 
-	tenantConfiguration, err := c.getTenantCredentials(ctx, &tenant)
+	tenantConfiguration, err := c.getTenantCredentials(ctx, tenant)
 	if err != nil {
 		if errors.Is(err, ErrEmptyRootCredentials) {
 			writeSTSErrorResponse(w, true, ErrSTSInternalError, fmt.Errorf("Tenant '%s' is missing root credentials", tenant.Name))
@@ -259,7 +272,7 @@ func (c *Controller) AssumeRoleWithWebIdentityHandler(w http.ResponseWriter, r *
 		durationInSeconds = duration
 	}
 
-	stsCredentials, err := AssumeRole(ctx, c, &tenant, region, bfCompact, durationInSeconds)
+	stsCredentials, err := AssumeRole(ctx, c, tenant, region, bfCompact, durationInSeconds)
 	if err != nil {
 		writeSTSErrorResponse(w, true, ErrSTSInternalError, err)
 		return
@@ -279,3 +292,54 @@ func (c *Controller) AssumeRoleWithWebIdentityHandler(w http.ResponseWriter, r *
 	assumeRoleResponse.ResponseMetadata.RequestID = w.Header().Get(AmzRequestID)
 	writeSuccessResponseXML(w, xhttp.EncodeResponse(assumeRoleResponse))
 }
+
+// resolvePolicyBindingsTenant returns the tenant the service account is requesting credentials for along with the
+// PolicyBindings granting policies on it. The tenant named in the request is used when set, otherwise the single tenant
+// of the namespace or the single tenant referenced by the PolicyBindings of the service account. PolicyBindings
+// without tenant only apply when the namespace holds a single tenant.
+func resolvePolicyBindingsTenant(tenants []miniov2.Tenant, policyBindings []v1beta1.PolicyBinding, tenantName string) (*miniov2.Tenant, []v1beta1.PolicyBinding, error) {
+	if tenantName == "" && len(tenants) == 1 {
+		tenantName = tenants[0].Name
+	} else if tenantName == "" {
+		referenced := map[string]bool{}
+		for _, pb := range policyBindings {
+			if pb.Spec.Tenant != "" {
+				referenced[pb.Spec.Tenant] = true
+			}
+		}
+		if len(referenced) != 1 {
+			return nil, nil, fmt.Errorf("Namespace '%s' has %d tenants, the tenant name is required", tenants[0].Namespace, len(tenants))
+		}
+		for name := range referenced {
+			tenantName = name
+		}
+	}
+
+	var tenant *miniov2.Tenant
+	for i := range tenants {
+		if tenants[i].Name == tenantName {
+			tenant = &tenants[i]
+			break
+		}
+	}
+	if tenant == nil {
+		return nil, nil, fmt.Errorf("Tenant '%s' not found", tenantName)
+	}
+
+	tenantBindings := []v1beta1.PolicyBinding{}
+	for _, pb := range policyBindings {
+		if policyBindingAppliesToTenant(&pb, tenant.Name, len(tenants)) {
+			tenantBindings = append(tenantBindings, pb)
+		}
+	}
+	return tenant, tenantBindings, nil
+}
+
+// policyBindingAppliesToTenant returns true if the PolicyBinding grants its policies on the tenant, a PolicyBinding
+// without tenant applies to the tenant of the namespace as long as it is the only one
+func policyBindingAppliesToTenant(pb *v1beta1.PolicyBinding, tenantName string, tenantsInNamespace int) bool {
+	if pb.Spec.Tenant != "" {
+		return pb.Spec.Tenant == tenantName
+	}
+	return tenantsInNamespace == 1
+}
@@ -0,0 +1,87 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"testing"
+
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	stsv1beta1 "github.com/minio/operator/pkg/apis/sts.min.io/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolvePolicyBindingsTenant(t *testing.T) {
+	tenant := func(name string) miniov2.Tenant {
+		return miniov2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant-ns"}}
+	}
+	binding := func(name, tenant string) stsv1beta1.PolicyBinding {
+		return stsv1beta1.PolicyBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant-ns"},
+			Spec:       stsv1beta1.PolicyBindingSpec{Tenant: tenant, Policies: []string{"readwrite"}},
+		}
+	}
+	single := []miniov2.Tenant{tenant("myminio")}
+	several := []miniov2.Tenant{tenant("myminio"), tenant("analytics")}
+
+	testCases := []struct {
+		name           string
+		tenants        []miniov2.Tenant
+		policyBindings []stsv1beta1.PolicyBinding
+		tenantName     string
+		wantTenant     string
+		wantBindings   []string
+		wantErr        bool
+	}{
+		{name: "single tenant", tenants: single, policyBindings: []stsv1beta1.PolicyBinding{binding("pb", "")}, wantTenant: "myminio", wantBindings: []string{"pb"}},
+		{name: "single tenant by name", tenants: single, policyBindings: []stsv1beta1.PolicyBinding{binding("pb", ""), binding("named", "myminio")}, tenantName: "myminio", wantTenant: "myminio", wantBindings: []string{"pb", "named"}},
+		{name: "unknown tenant", tenants: single, policyBindings: []stsv1beta1.PolicyBinding{binding("pb", "")}, tenantName: "other", wantErr: true},
+		{name: "several tenants by name", tenants: several, policyBindings: []stsv1beta1.PolicyBinding{binding("pb", ""), binding("mine", "myminio"), binding("theirs", "analytics")}, tenantName: "analytics", wantTenant: "analytics", wantBindings: []string{"theirs"}},
+		{name: "several tenants from the bindings", tenants: several, policyBindings: []stsv1beta1.PolicyBinding{binding("pb", ""), binding("theirs", "analytics")}, wantTenant: "analytics", wantBindings: []string{"theirs"}},
+		{name: "several tenants without reference", tenants: several, policyBindings: []stsv1beta1.PolicyBinding{binding("pb", "")}, wantErr: true},
+		{name: "several tenants ambiguous", tenants: several, policyBindings: []stsv1beta1.PolicyBinding{binding("mine", "myminio"), binding("theirs", "analytics")}, wantErr: true},
+		{name: "several tenants no binding", tenants: several, policyBindings: []stsv1beta1.PolicyBinding{binding("pb", "")}, tenantName: "myminio", wantTenant: "myminio"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tenant, policyBindings, err := resolvePolicyBindingsTenant(tc.tenants, tc.policyBindings, tc.tenantName)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got tenant %s", tenant.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tenant.Name != tc.wantTenant {
+				t.Errorf("expected tenant %s, got %s", tc.wantTenant, tenant.Name)
+			}
+			names := []string{}
+			for _, pb := range policyBindings {
+				names = append(names, pb.Name)
+			}
+			if len(names) != len(tc.wantBindings) {
+				t.Fatalf("expected the bindings %v, got %v", tc.wantBindings, names)
+			}
+			for i := range names {
+				if names[i] != tc.wantBindings[i] {
+					t.Errorf("expected the bindings %v, got %v", tc.wantBindings, names)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/minio/madmin-go/v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
@@ -82,3 +83,17 @@ func (c *Controller) getTenantCredentials(ctx context.Context, tenant *miniov2.T
 
 	return tenantConfiguration, nil
 }
+
+// getTenantAdminClient returns an admin client for the tenant, using its root credentials
+func (c *Controller) getTenantAdminClient(ctx context.Context, tenant *miniov2.Tenant) (*madmin.AdminClient, error) {
+	tenantConfiguration, err := c.getTenantCredentials(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return tenant.NewMinIOAdmin(tenantConfiguration, c.getTransport())
+}
+
+// getTenantUserAdminClient gets an admin client for a tenant with the credentials of one of its users
+func (c *Controller) getTenantUserAdminClient(tenant *miniov2.Tenant, accessKey, secretKey string) (*madmin.AdminClient, error) {
+	return tenant.NewMinIOAdmin(map[string][]byte{"accesskey": []byte(accessKey), "secretkey": []byte(secretKey)}, c.getTransport())
+}
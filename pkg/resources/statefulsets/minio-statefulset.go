@@ -233,10 +233,44 @@ func GetContainerArgs(t *miniov2.Tenant, hostsTemplate string) []string {
 		for index, endpoint := range t.MinIOEndpoints(hostsTemplate) {
 			args = append(args, fmt.Sprintf("%s%s", endpoint, t.VolumePathForPool(&t.Spec.Pools[index])))
 		}
+		args = withDecommissioningPools(t, args)
 	}
 	return args
 }
 
+// withDecommissioningPools keeps the pools that are being decommissioned or replaced in the MinIO arguments, in
+// their original position, so a restarted MinIO server still knows about them until they are removed. The pools
+// are ordered as deployed, pools not deployed yet are appended in the order of the spec.
+func withDecommissioningPools(t *miniov2.Tenant, args []string) []string {
+	var merged []string
+	used := make([]bool, len(args))
+	for _, poolStatus := range t.Status.Pools {
+		if t.IsPoolRemoved(poolStatus) {
+			// once decommissioned the pool is dropped from the arguments on the next restart
+			switch {
+			case poolStatus.State == miniov2.PoolDecommissioning && poolStatus.Decommission != nil && poolStatus.Decommission.CmdLine != "":
+				merged = append(merged, poolStatus.Decommission.CmdLine)
+			case poolStatus.State != miniov2.PoolDecommissioned && poolStatus.Replacement != nil && poolStatus.Replacement.CmdLine != "":
+				merged = append(merged, poolStatus.Replacement.CmdLine)
+			}
+			continue
+		}
+		for i := range t.Spec.Pools {
+			if i < len(args) && !used[i] && t.IsPoolStatefulset(&t.Spec.Pools[i], poolStatus.SSName) {
+				merged = append(merged, args[i])
+				used[i] = true
+				break
+			}
+		}
+	}
+	for i, arg := range args {
+		if !used[i] {
+			merged = append(merged, arg)
+		}
+	}
+	return merged
+}
+
 // Builds the tolerations for a Pool.
 func poolTolerations(z *miniov2.Pool) []corev1.Toleration {
 	var tolerations []corev1.Toleration
@@ -268,7 +302,7 @@ func poolSecurityContext(pool *miniov2.Pool, status *miniov2.PoolStatus) *corev1
 	if pool != nil && pool.SecurityContext != nil {
 		securityContext = *pool.SecurityContext
 		// if the pool has no security context, and it's market as legacy security context return nil
-	} else if status.LegacySecurityContext {
+	} else if status != nil && status.LegacySecurityContext {
 		return nil
 	}
 	// Prevents high CPU usage of kubelet by preventing chown on the entire CSI
@@ -335,11 +369,16 @@ type NewPoolArgs struct {
 	ServiceName     string
 	HostsTemplate   string
 	OperatorVersion string
+	// Image overrides the MinIO image of the tenant, the pools keep their image while an upgrade isn't completed
+	Image string
 }
 
 // NewPool creates a new StatefulSet for the given Cluster.
 func NewPool(args *NewPoolArgs) *appsv1.StatefulSet {
 	t := args.Tenant.DeepCopy()
+	if args.Image != "" {
+		t.Spec.Image = args.Image
+	}
 	skipEnvVars := args.SkipEnvVars
 	pool := args.Pool
 	poolStatus := args.PoolStatus
@@ -625,9 +664,14 @@ func NewPool(args *NewPoolArgs) *appsv1.StatefulSet {
 		})
 	}
 
+	// a pool keeps the statefulset it was deployed with, replacements of the pool get a new one
+	ssName := t.PoolStatefulsetName(pool)
+	if poolStatus != nil && poolStatus.SSName != "" {
+		ssName = poolStatus.SSName
+	}
 	ssMeta := metav1.ObjectMeta{
 		Namespace: t.Namespace,
-		Name:      t.PoolStatefulsetName(pool),
+		Name:      ssName,
 		OwnerReferences: []metav1.OwnerReference{
 			*metav1.NewControllerRef(t, schema.GroupVersionKind{
 				Group:   miniov2.SchemeGroupVersion.Group,
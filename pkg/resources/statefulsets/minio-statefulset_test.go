@@ -90,6 +90,106 @@ func TestGetContainerArgs(t *testing.T) {
 				"https://minio-pool-0-{0...3}.minio-hl..svc.cluster.local/export{0...3}",
 			},
 		},
+		{
+			name: "Tenant With Pool Being Decommissioned",
+			args: args{
+				t: &miniov2.Tenant{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "minio",
+					},
+					Spec: miniov2.TenantSpec{
+						Pools: []miniov2.Pool{
+							{
+								Name:             "pool-0",
+								Servers:          4,
+								VolumesPerServer: 4,
+							},
+							{
+								Name:             "pool-2",
+								Servers:          4,
+								VolumesPerServer: 4,
+							},
+						},
+					},
+					Status: miniov2.TenantStatus{
+						Pools: []miniov2.PoolStatus{
+							{
+								SSName: "minio-pool-0",
+								State:  miniov2.PoolInitialized,
+							},
+							{
+								SSName: "minio-pool-1",
+								State:  miniov2.PoolDecommissioning,
+								Decommission: &miniov2.PoolDecommissionStatus{
+									CmdLine: "https://minio-pool-1-{0...3}.minio-hl..svc.cluster.local/export{0...3}",
+								},
+							},
+							{
+								SSName: "minio-pool-2",
+								State:  miniov2.PoolInitialized,
+							},
+						},
+					},
+				},
+				hostsTemplate: "",
+			},
+			want: []string{
+				"https://minio-pool-0-{0...3}.minio-hl..svc.cluster.local/export{0...3}",
+				"https://minio-pool-1-{0...3}.minio-hl..svc.cluster.local/export{0...3}",
+				"https://minio-pool-2-{0...3}.minio-hl..svc.cluster.local/export{0...3}",
+			},
+		},
+		{
+			name: "Tenant With Pool Being Replaced",
+			args: args{
+				t: &miniov2.Tenant{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "minio",
+					},
+					Spec: miniov2.TenantSpec{
+						Pools: []miniov2.Pool{
+							{
+								Name:             "pool-0",
+								Servers:          8,
+								VolumesPerServer: 4,
+							},
+							{
+								Name:             "pool-1",
+								Servers:          4,
+								VolumesPerServer: 4,
+							},
+						},
+					},
+					Status: miniov2.TenantStatus{
+						Pools: []miniov2.PoolStatus{
+							{
+								SSName: "minio-pool-0",
+								State:  miniov2.PoolInitialized,
+								Replacement: &miniov2.PoolReplacementStatus{
+									Stage:   miniov2.PoolReplacementInitializing,
+									SSName:  "minio-pool-0-r1",
+									CmdLine: "https://minio-pool-0-{0...3}.minio-hl..svc.cluster.local/export{0...3}",
+								},
+							},
+							{
+								SSName: "minio-pool-1",
+								State:  miniov2.PoolInitialized,
+							},
+							{
+								SSName: "minio-pool-0-r1",
+								State:  miniov2.PoolCreated,
+							},
+						},
+					},
+				},
+				hostsTemplate: "",
+			},
+			want: []string{
+				"https://minio-pool-0-{0...3}.minio-hl..svc.cluster.local/export{0...3}",
+				"https://minio-pool-1-{0...3}.minio-hl..svc.cluster.local/export{0...3}",
+				"https://minio-pool-0-r1-{0...7}.minio-hl..svc.cluster.local/export{0...3}",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
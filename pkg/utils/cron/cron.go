@@ -0,0 +1,222 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package cron parses cron schedules and computes their ticks
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule - a parsed cron schedule, in the standard 5 fields format:
+// minute hour day-of-month month day-of-week
+// The Operator only needs the standard format and the previous and next ticks of a schedule, which doesn't justify a
+// new dependency on a cron library.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// if both day-of-month and day-of-week are restricted, a day matching either one matches
+	domRestricted, dowRestricted bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule - parse a cron schedule like `*/15 2 * * mon-fri` or a descriptor like `@daily`
+func ParseSchedule(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if descriptor, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q must have 5 fields, got %d", spec, len(fields))
+	}
+	var err error
+	schedule := &Schedule{}
+	if schedule.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if schedule.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if schedule.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// 7 is accepted as sunday as well
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow = schedule.dow&^(1<<7) | 1
+	}
+	// like vixie cron and Kubernetes CronJobs, a day field starting with `*`, such as `*/2`, doesn't restrict the days
+	schedule.domRestricted = !strings.HasPrefix(fields[2], "*") && !strings.HasPrefix(fields[2], "?")
+	schedule.dowRestricted = !strings.HasPrefix(fields[4], "*") && !strings.HasPrefix(fields[4], "?")
+	return schedule, nil
+}
+
+// parse - parse a comma separated list of values, ranges and steps into a bit set
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepExpr, f.name)
+			}
+		}
+		start, end := f.min, f.max
+		if rangeExpr != "*" && rangeExpr != "?" {
+			lowExpr, highExpr, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if start, err = f.value(lowExpr); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = f.value(highExpr); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = f.max
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q in %s field", rangeExpr, f.name)
+			}
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value - parse a single number or name of the field
+func (f cronField) value(expr string) (int, error) {
+	if v, ok := f.names[strings.ToLower(expr)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %s field, must be between %d and %d", expr, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Next - get the first time after t matching the schedule, zero if there is none in the next five years
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Prev - get the last time at or before t matching the schedule, zero if there is none in the previous five years
+func (s *Schedule) Prev(t time.Time) time.Time {
+	t = t.Truncate(time.Minute)
+	limit := t.AddDate(-5, 0, 0)
+	for t.After(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(-time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// LastTick - get the most recent schedule tick after t that is not after now, zero if there is none. The ticks missed
+// in between are skipped without being walked, however long ago t is
+func (s *Schedule) LastTick(t, now time.Time) time.Time {
+	last := s.Prev(now)
+	if last.IsZero() || !last.After(t) {
+		return time.Time{}
+	}
+	return last
+}
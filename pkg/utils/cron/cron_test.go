@@ -0,0 +1,130 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// a monday
+	from := time.Date(2024, 7, 15, 10, 30, 20, 0, time.UTC)
+	testCases := []struct {
+		schedule    string
+		expect      time.Time
+		expectError bool
+	}{
+		{schedule: "* * * * *", expect: time.Date(2024, 7, 15, 10, 31, 0, 0, time.UTC)},
+		{schedule: "*/15 * * * *", expect: time.Date(2024, 7, 15, 10, 45, 0, 0, time.UTC)},
+		{schedule: "0 2 * * *", expect: time.Date(2024, 7, 16, 2, 0, 0, 0, time.UTC)},
+		{schedule: "@daily", expect: time.Date(2024, 7, 16, 0, 0, 0, 0, time.UTC)},
+		{schedule: "@hourly", expect: time.Date(2024, 7, 15, 11, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 * * sat,sun", expect: time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 * * 7", expect: time.Date(2024, 7, 21, 0, 0, 0, 0, time.UTC)},
+		{schedule: "30 9-17/4 * * mon-fri", expect: time.Date(2024, 7, 15, 13, 30, 0, 0, time.UTC)},
+		{schedule: "0 0 1 jan *", expect: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week when both are restricted
+		{schedule: "0 0 20 * 2", expect: time.Date(2024, 7, 16, 0, 0, 0, 0, time.UTC)},
+		// a day field starting with * doesn't restrict the days, both fields must match
+		{schedule: "0 0 */2 * mon", expect: time.Date(2024, 7, 29, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 * * */2", expect: time.Date(2024, 7, 16, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 30 2 *", expect: time.Time{}},
+		{schedule: "0 0 * *", expectError: true},
+		{schedule: "60 * * * *", expectError: true},
+		{schedule: "5-1 * * * *", expectError: true},
+		{schedule: "*/0 * * * *", expectError: true},
+		{schedule: "0 0 * foo *", expectError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.schedule, func(t *testing.T) {
+			schedule, err := ParseSchedule(tc.schedule)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("ParseSchedule() error = %v, expectError %v", err, tc.expectError)
+			}
+			if err != nil {
+				return
+			}
+			if next := schedule.Next(from); !next.Equal(tc.expect) {
+				t.Errorf("Next() = %v, expect %v", next, tc.expect)
+			}
+		})
+	}
+}
+
+func TestSchedulePrev(t *testing.T) {
+	// a monday
+	from := time.Date(2024, 7, 15, 10, 30, 20, 0, time.UTC)
+	testCases := []struct {
+		schedule string
+		expect   time.Time
+	}{
+		{schedule: "* * * * *", expect: time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)},
+		{schedule: "*/15 * * * *", expect: time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)},
+		{schedule: "0 2 * * *", expect: time.Date(2024, 7, 15, 2, 0, 0, 0, time.UTC)},
+		{schedule: "@hourly", expect: time.Date(2024, 7, 15, 10, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 * * sat,sun", expect: time.Date(2024, 7, 14, 0, 0, 0, 0, time.UTC)},
+		{schedule: "30 9-17/4 * * mon-fri", expect: time.Date(2024, 7, 15, 9, 30, 0, 0, time.UTC)},
+		{schedule: "0 18 * * mon-fri", expect: time.Date(2024, 7, 12, 18, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 1 jan *", expect: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week when both are restricted
+		{schedule: "0 0 20 * 2", expect: time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 */2 * mon", expect: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 */2 * tue", expect: time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 30 2 *", expect: time.Time{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.schedule, func(t *testing.T) {
+			schedule, err := ParseSchedule(tc.schedule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prev := schedule.Prev(from); !prev.Equal(tc.expect) {
+				t.Errorf("Prev() = %v, expect %v", prev, tc.expect)
+			}
+		})
+	}
+}
+
+func TestScheduleLastTick(t *testing.T) {
+	schedule, err := ParseSchedule("0 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := time.Date(2024, 7, 15, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 7, 15, 13, 30, 0, 0, time.UTC)
+	if tick := schedule.LastTick(last, now); !tick.Equal(time.Date(2024, 7, 15, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("LastTick() = %v", tick)
+	}
+	if tick := schedule.LastTick(now, now); !tick.IsZero() {
+		t.Errorf("LastTick() expected no tick, got %v", tick)
+	}
+	// a schedule missed for years
+	if tick := schedule.LastTick(last.AddDate(-3, 0, 0), now); !tick.Equal(time.Date(2024, 7, 15, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("LastTick() = %v", tick)
+	}
+	yearly, err := ParseSchedule("30 4 29 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tick := yearly.LastTick(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), now); !tick.Equal(time.Date(2024, 2, 29, 4, 30, 0, 0, time.UTC)) {
+		t.Errorf("LastTick() = %v", tick)
+	}
+	if tick := yearly.LastTick(time.Date(2024, 2, 29, 4, 30, 0, 0, time.UTC), now); !tick.IsZero() {
+		t.Errorf("LastTick() expected no tick after the last one, got %v", tick)
+	}
+}
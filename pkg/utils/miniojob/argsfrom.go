@@ -0,0 +1,89 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// argsFromMountPath - where the args passed as files are mounted in the command container
+	argsFromMountPath = "/job-args"
+	// argsFromFileName - name of the file holding the value of an arg passed as a file
+	argsFromFileName = "value"
+)
+
+// ArgEnvName - name of the env var holding the value of the arg sourced from a Secret or a ConfigMap at the given
+// index of argsFrom
+func ArgEnvName(index int) string {
+	return fmt.Sprintf("MINIOJOB_ARG_%d", index)
+}
+
+// resolveArgsFrom - add the args sourced from Secrets and ConfigMaps to the args of the command, as references to
+// the env vars or files their values are injected into when the command runs
+func (jobCommand *MinIOIntervalJobCommand) resolveArgsFrom(args map[string]string) error {
+	for i, arg := range jobCommand.CommandSpec.ArgsFrom {
+		if arg.Name == "" {
+			return fmt.Errorf("argsFrom[%d] has no name", i)
+		}
+		if _, found := args[arg.Name]; found {
+			return fmt.Errorf("arg %s is set in both args and argsFrom", arg.Name)
+		}
+		source := arg.ValueFrom
+		if (source.SecretKeyRef == nil) == (source.ConfigMapKeyRef == nil) {
+			return fmt.Errorf("arg %s must be read from either a secretKeyRef or a configMapKeyRef", arg.Name)
+		}
+		if !arg.AsFile {
+			// the kubelet expands the reference to the env var in the command of the container
+			envName := ArgEnvName(i)
+			jobCommand.ArgsEnv = append(jobCommand.ArgsEnv, corev1.EnvVar{
+				Name: envName,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef:    source.SecretKeyRef,
+					ConfigMapKeyRef: source.ConfigMapKeyRef,
+				},
+			})
+			args[arg.Name] = fmt.Sprintf("$(%s)", envName)
+			continue
+		}
+		volume := corev1.Volume{Name: fmt.Sprintf("job-arg-%d", i)}
+		if source.SecretKeyRef != nil {
+			volume.Secret = &corev1.SecretVolumeSource{
+				SecretName: source.SecretKeyRef.Name,
+				Items:      []corev1.KeyToPath{{Key: source.SecretKeyRef.Key, Path: argsFromFileName}},
+				Optional:   source.SecretKeyRef.Optional,
+			}
+		} else {
+			volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: source.ConfigMapKeyRef.LocalObjectReference,
+				Items:                []corev1.KeyToPath{{Key: source.ConfigMapKeyRef.Key, Path: argsFromFileName}},
+				Optional:             source.ConfigMapKeyRef.Optional,
+			}
+		}
+		mountPath := fmt.Sprintf("%s/%d", argsFromMountPath, i)
+		jobCommand.ArgsVolumes = append(jobCommand.ArgsVolumes, volume)
+		jobCommand.ArgsVolumeMounts = append(jobCommand.ArgsVolumeMounts, corev1.VolumeMount{
+			Name:      volume.Name,
+			MountPath: mountPath,
+			ReadOnly:  true,
+		})
+		args[arg.Name] = fmt.Sprintf("%s/%s", mountPath, argsFromFileName)
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	iampolicy "github.com/minio/pkg/iam/policy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BatchJobPollInterval - how often the progress of the running batch jobs is read from the tenant
+const BatchJobPollInterval = 10 * time.Second
+
+// BatchJobClient - the admin API of the tenant running the batch jobs, implemented by madmin.AdminClient
+type BatchJobClient interface {
+	StartBatchJob(ctx context.Context, job string) (madmin.BatchJobResult, error)
+	BatchJobStatus(ctx context.Context, jobID string) (madmin.BatchJobStatus, error)
+	CancelBatchJob(ctx context.Context, jobID string) error
+}
+
+// IsBatch - check if the command submits a MinIO batch job instead of running a Kubernetes Job
+func (jobCommand *MinIOIntervalJobCommand) IsBatch() bool {
+	return jobCommand.CommandSpec.Batch != nil
+}
+
+// validateBatch - check the batch command sets a single definition and nothing an mc command would
+func validateBatch(commandSpec v1alpha1.CommandSpec) error {
+	if commandSpec.Operation != "" || len(commandSpec.Args) > 0 || len(commandSpec.ArgsFrom) > 0 || len(commandSpec.Command) > 0 {
+		return fmt.Errorf("batch command %s can't set op, args, argsFrom or command", commandSpec.Name)
+	}
+	batch := commandSpec.Batch
+	if (batch.Definition == "") == (batch.ConfigMapKeyRef == nil) {
+		return fmt.Errorf("batch command %s must set exactly one of definition and configMapKeyRef", commandSpec.Name)
+	}
+	if batch.Definition != "" {
+		if _, err := BatchJobType(batch.Definition); err != nil {
+			return fmt.Errorf("batch command %s: %w", commandSpec.Name, err)
+		}
+	}
+	return nil
+}
+
+// BatchJobType - get the type of the batch job the definition describes
+func BatchJobType(definition string) (madmin.BatchJobType, error) {
+	job := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(definition), &job); err != nil {
+		return "", fmt.Errorf("invalid batch job definition: %w", err)
+	}
+	for _, jobType := range madmin.SupportedJobTypes {
+		if _, found := job[string(jobType)]; found && len(job) == 1 {
+			return jobType, nil
+		}
+	}
+	types := []string{}
+	for _, jobType := range madmin.SupportedJobTypes {
+		types = append(types, string(jobType))
+	}
+	return "", fmt.Errorf("batch job definition must describe a single job of type %s", strings.Join(types, ", "))
+}
+
+// batchJobLocation - a bucket and prefix a batch job reads or writes, its endpoint is empty when it's in the tenant
+type batchJobLocation struct {
+	Endpoint string `json:"endpoint"`
+	Bucket   string `json:"bucket"`
+	Prefix   string `json:"prefix"`
+}
+
+// batchJobTargets - the locations of the batch jobs of each type
+type batchJobTargets struct {
+	Replicate *struct {
+		Source batchJobLocation `json:"source"`
+		Target batchJobLocation `json:"target"`
+	} `json:"replicate"`
+	KeyRotate *batchJobLocation `json:"keyrotate"`
+	Expire    *batchJobLocation `json:"expire"`
+}
+
+// CheckBatchPolicy - check the policy allows submitting the batch job and every action it takes on the buckets of the
+// tenant, the job runs inside MinIO so the policy has to be checked before it's submitted
+func CheckBatchPolicy(policy iampolicy.Policy, definition string) error {
+	if !policy.IsAllowed(iampolicy.Args{Action: iampolicy.Action(iampolicy.StartBatchJobAction)}) {
+		return fmt.Errorf("the policies bound to the service account don't allow %s", iampolicy.StartBatchJobAction)
+	}
+	targets := batchJobTargets{}
+	if err := yaml.Unmarshal([]byte(definition), &targets); err != nil {
+		return fmt.Errorf("invalid batch job definition: %w", err)
+	}
+	type access struct {
+		location batchJobLocation
+		actions  []iampolicy.Action
+	}
+	var accesses []access
+	switch {
+	case targets.Replicate != nil:
+		// a remote side is reached with the credentials of its definition
+		if targets.Replicate.Source.Endpoint == "" {
+			accesses = append(accesses, access{targets.Replicate.Source, []iampolicy.Action{iampolicy.ListBucketAction, iampolicy.GetObjectAction}})
+		}
+		if targets.Replicate.Target.Endpoint == "" {
+			accesses = append(accesses, access{targets.Replicate.Target, []iampolicy.Action{iampolicy.PutObjectAction}})
+		}
+	case targets.KeyRotate != nil:
+		accesses = append(accesses, access{*targets.KeyRotate, []iampolicy.Action{iampolicy.ListBucketAction, iampolicy.GetObjectAction, iampolicy.PutObjectAction}})
+	case targets.Expire != nil:
+		accesses = append(accesses, access{*targets.Expire, []iampolicy.Action{iampolicy.ListBucketAction, iampolicy.DeleteObjectAction}})
+	}
+	for _, a := range accesses {
+		if a.location.Bucket == "" {
+			return errors.New("batch job definition without bucket")
+		}
+		for _, action := range a.actions {
+			args := iampolicy.Args{
+				Action:          action,
+				BucketName:      a.location.Bucket,
+				ConditionValues: map[string][]string{"prefix": {a.location.Prefix}},
+			}
+			if action != iampolicy.ListBucketAction {
+				args.ObjectName = a.location.Prefix + "*"
+			}
+			if !policy.IsAllowed(args) {
+				return fmt.Errorf("the policies bound to the service account don't allow %s on %s/%s", action, a.location.Bucket, a.location.Prefix)
+			}
+		}
+	}
+	return nil
+}
+
+// batchDefinition - get the definition of the batch job, read from its ConfigMap if it's not inline
+func (jobCommand *MinIOIntervalJobCommand) batchDefinition(ctx context.Context, k8sClient client.Client, jobCR *v1alpha1.MinIOJob) (string, error) {
+	batch := jobCommand.CommandSpec.Batch
+	if batch.ConfigMapKeyRef == nil {
+		return batch.Definition, nil
+	}
+	configMap := &corev1.ConfigMap{}
+	err := k8sClient.Get(ctx, client.ObjectKey{Namespace: jobCR.Namespace, Name: batch.ConfigMapKeyRef.Name}, configMap)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the batch job definition of %s: %w", jobCommand.JobName, err)
+	}
+	definition, found := configMap.Data[batch.ConfigMapKeyRef.Key]
+	if !found {
+		return "", fmt.Errorf("key %s not found in ConfigMap %s", batch.ConfigMapKeyRef.Key, batch.ConfigMapKeyRef.Name)
+	}
+	if _, err = BatchJobType(definition); err != nil {
+		return "", err
+	}
+	return definition, nil
+}
+
+// StartBatchJob - submit the batch job of the next attempt of the command
+func (jobCommand *MinIOIntervalJobCommand) StartBatchJob(ctx context.Context, k8sClient client.Client, jobCR *v1alpha1.MinIOJob, batchClient BatchJobClient) error {
+	jobCommand.mutex.RLock()
+	// a created command only runs again once it's waiting for a retry
+	if jobCommand.Succeeded || jobCommand.Skipped || jobCommand.Cancelled || jobCommand.Failed || (jobCommand.Created && jobCommand.RetryAt == nil) {
+		jobCommand.mutex.RUnlock()
+		return nil
+	}
+	attempt := jobCommand.Attempts + 1
+	jobCommand.mutex.RUnlock()
+	if batchClient == nil {
+		return fmt.Errorf("no admin client to start the batch job of %s", jobCommand.JobName)
+	}
+	definition, err := jobCommand.batchDefinition(ctx, k8sClient, jobCR)
+	if err != nil {
+		return err
+	}
+	result, err := batchClient.StartBatchJob(ctx, definition)
+	if err != nil {
+		return fmt.Errorf("unable to start the batch job of %s: %w", jobCommand.JobName, err)
+	}
+	startTime := metav1.NewTime(result.Started)
+	jobCommand.mutex.Lock()
+	defer jobCommand.mutex.Unlock()
+	jobCommand.Created = true
+	jobCommand.Attempts = attempt
+	jobCommand.RetryAt = nil
+	jobCommand.CompletionTime = nil
+	if attempt == 1 || jobCommand.StartTime == nil {
+		jobCommand.StartTime = &startTime
+	}
+	jobCommand.Batch = &v1alpha1.BatchJobStatus{
+		ID:   result.ID,
+		Type: string(result.Type),
+	}
+	return nil
+}
+
+// batchRunning - check if the batch job of the command was submitted and did not finish yet
+func (jobCommand *MinIOIntervalJobCommand) batchRunning() bool {
+	jobCommand.mutex.RLock()
+	defer jobCommand.mutex.RUnlock()
+	return jobCommand.IsBatch() && jobCommand.Created && jobCommand.RetryAt == nil && jobCommand.Batch != nil &&
+		!jobCommand.Succeeded && !jobCommand.Failed && !jobCommand.Cancelled
+}
+
+// loadBatchStatus - update the state of the command from the last metric of its batch job, a failed batch job is
+// retried like a failed Kubernetes Job, one running for longer than the timeout is cancelled
+func (jobCommand *MinIOIntervalJobCommand) loadBatchStatus(ctx context.Context, batchClient BatchJobClient, status madmin.BatchJobStatus, now time.Time) error {
+	metric := status.LastMetric
+	jobCommand.mutex.Lock()
+	defer jobCommand.mutex.Unlock()
+	batch := jobCommand.Batch
+	if !metric.LastUpdate.IsZero() {
+		lastUpdate := metav1.NewTime(metric.LastUpdate)
+		batch.LastUpdate = &lastUpdate
+	}
+	switch {
+	case metric.Replicate != nil:
+		batch.Objects, batch.ObjectsFailed = metric.Replicate.Objects, metric.Replicate.ObjectsFailed
+		batch.BytesTransferred, batch.BytesFailed = metric.Replicate.BytesTransferred, metric.Replicate.BytesFailed
+		batch.LastBucket, batch.LastObject = metric.Replicate.Bucket, metric.Replicate.Object
+	case metric.KeyRotate != nil:
+		batch.Objects, batch.ObjectsFailed = metric.KeyRotate.Objects, metric.KeyRotate.ObjectsFailed
+		batch.LastBucket, batch.LastObject = metric.KeyRotate.Bucket, metric.KeyRotate.Object
+	case metric.Expired != nil:
+		batch.Objects, batch.ObjectsFailed = metric.Expired.Objects, metric.Expired.ObjectsFailed
+		batch.LastBucket, batch.LastObject = metric.Expired.Bucket, metric.Expired.Object
+	}
+	timedOut := false
+	if !metric.Complete && !metric.Failed && jobCommand.Policy.TimeoutSeconds != nil && !metric.StartTime.IsZero() &&
+		now.Sub(metric.StartTime) > time.Duration(*jobCommand.Policy.TimeoutSeconds)*time.Second {
+		if err := batchClient.CancelBatchJob(ctx, batch.ID); err != nil {
+			return fmt.Errorf("unable to cancel the batch job %s of %s: %w", batch.ID, jobCommand.JobName, err)
+		}
+		timedOut = true
+	}
+	if !metric.Complete && !metric.Failed && !timedOut {
+		return nil
+	}
+	completionTime := metav1.NewTime(now)
+	if !metric.LastUpdate.IsZero() && !timedOut {
+		completionTime = metav1.NewTime(metric.LastUpdate)
+	}
+	jobCommand.CompletionTime = &completionTime
+	if metric.Complete && !metric.Failed {
+		jobCommand.Succeeded = true
+		jobCommand.Message = ""
+		return nil
+	}
+	jobCommand.Message = fmt.Sprintf("Batch job %s failed, %d objects failed", batch.ID, batch.ObjectsFailed)
+	if timedOut {
+		jobCommand.Message = fmt.Sprintf("Batch job %s was active longer than the timeout", batch.ID)
+	}
+	if jobCommand.Attempts <= jobCommand.retries() {
+		retryAt := metav1.NewTime(completionTime.Add(jobCommand.backoff(jobCommand.Attempts)))
+		jobCommand.RetryAt = &retryAt
+		return nil
+	}
+	jobCommand.Failed = true
+	jobCommand.TimedOut = timedOut
+	return nil
+}
+
+// SyncBatchJobs - read the progress of the running batch jobs from the tenant
+func (intervalJob *MinIOIntervalJob) SyncBatchJobs(ctx context.Context, now time.Time) error {
+	for _, command := range intervalJob.Command {
+		if !command.batchRunning() {
+			continue
+		}
+		if intervalJob.BatchClient == nil {
+			return fmt.Errorf("no admin client to read the batch job of %s", command.JobName)
+		}
+		status, err := intervalJob.BatchClient.BatchJobStatus(ctx, command.Batch.ID)
+		if err != nil {
+			return fmt.Errorf("unable to read the status of the batch job %s of %s: %w", command.Batch.ID, command.JobName, err)
+		}
+		if err = command.loadBatchStatus(ctx, intervalJob.BatchClient, status, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CancelBatchJobs - cancel the batch jobs that did not finish yet, all of them are tried even if one fails
+func (intervalJob *MinIOIntervalJob) CancelBatchJobs(ctx context.Context) error {
+	var errs []error
+	for _, command := range intervalJob.Command {
+		if !command.batchRunning() {
+			continue
+		}
+		if intervalJob.BatchClient == nil {
+			errs = append(errs, fmt.Errorf("no admin client to cancel the batch job of %s", command.JobName))
+			continue
+		}
+		if err := intervalJob.BatchClient.CancelBatchJob(ctx, command.Batch.ID); err != nil {
+			errs = append(errs, fmt.Errorf("unable to cancel the batch job %s of %s: %w", command.Batch.ID, command.JobName, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HasBatchCommands - check if some commands of the job submit a batch job
+func (intervalJob *MinIOIntervalJob) HasBatchCommands() bool {
+	for _, command := range intervalJob.Command {
+		if command.IsBatch() {
+			return true
+		}
+	}
+	return false
+}
+
+// RunningBatchJobs - check if a batch job is running, its progress has to be polled
+func (intervalJob *MinIOIntervalJob) RunningBatchJobs() bool {
+	for _, command := range intervalJob.Command {
+		if command.batchRunning() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,288 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	iampolicy "github.com/minio/pkg/iam/policy"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testExpireDefinition = `expire:
+  apiVersion: v1
+  bucket: memes
+  rules:
+    - type: object
+      olderThan: 720h
+`
+
+// fakeBatchClient runs batch jobs whose metric is set by the test
+type fakeBatchClient struct {
+	started   int
+	cancelled []string
+	metrics   map[string]madmin.JobMetric
+}
+
+func (f *fakeBatchClient) StartBatchJob(_ context.Context, job string) (madmin.BatchJobResult, error) {
+	jobType, err := BatchJobType(job)
+	if err != nil {
+		return madmin.BatchJobResult{}, err
+	}
+	f.started++
+	return madmin.BatchJobResult{ID: fmt.Sprintf("job-%d", f.started), Type: jobType, Started: time.Now()}, nil
+}
+
+func (f *fakeBatchClient) BatchJobStatus(_ context.Context, jobID string) (madmin.BatchJobStatus, error) {
+	return madmin.BatchJobStatus{LastMetric: f.metrics[jobID]}, nil
+}
+
+func (f *fakeBatchClient) CancelBatchJob(_ context.Context, jobID string) error {
+	f.cancelled = append(f.cancelled, jobID)
+	return nil
+}
+
+func batchCommand(name string, dependsOn ...string) v1alpha1.CommandSpec {
+	return v1alpha1.CommandSpec{
+		Name:      name,
+		Batch:     &v1alpha1.BatchJobSpec{Definition: testExpireDefinition},
+		DependsOn: dependsOn,
+	}
+}
+
+func TestBatchJobType(t *testing.T) {
+	if jobType, err := BatchJobType(testExpireDefinition); err != nil || jobType != madmin.BatchJobExpire {
+		t.Errorf("BatchJobType() = %s, %v", jobType, err)
+	}
+	for _, definition := range []string{"", "mirror:\n  apiVersion: v1\n", "expire: {}\nreplicate: {}\n", "expire: ["} {
+		if _, err := BatchJobType(definition); err == nil {
+			t.Errorf("expected an error for %q", definition)
+		}
+	}
+}
+
+func TestValidateBatch(t *testing.T) {
+	testCases := []struct {
+		name        string
+		spec        v1alpha1.CommandSpec
+		expectError bool
+	}{
+		{
+			name: "inline definition",
+			spec: batchCommand("expire"),
+		},
+		{
+			name: "configmap definition",
+			spec: v1alpha1.CommandSpec{Batch: &v1alpha1.BatchJobSpec{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "batch"},
+				Key:                  "expire.yaml",
+			}}},
+		},
+		{
+			name:        "no definition",
+			spec:        v1alpha1.CommandSpec{Batch: &v1alpha1.BatchJobSpec{}},
+			expectError: true,
+		},
+		{
+			name:        "with an operation",
+			spec:        v1alpha1.CommandSpec{Operation: "mb", Batch: &v1alpha1.BatchJobSpec{Definition: testExpireDefinition}},
+			expectError: true,
+		},
+		{
+			name:        "unknown type",
+			spec:        v1alpha1.CommandSpec{Batch: &v1alpha1.BatchJobSpec{Definition: "mirror:\n  apiVersion: v1\n"}},
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			command, err := GenerateMinIOIntervalJobCommand(tc.spec, 0)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("expected error %v, got %v", tc.expectError, err)
+			}
+			if err == nil && (!command.IsBatch() || len(command.MCCommand()) != 4) {
+				t.Errorf("unexpected command %v", command.MCCommand())
+			}
+		})
+	}
+}
+
+func TestBatchJobs(t *testing.T) {
+	ctx := context.Background()
+	batchClient := &fakeBatchClient{metrics: map[string]madmin.JobMetric{}}
+	intervalJob := newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure, batchCommand("expire"), command("after", "expire"))
+	intervalJob.JobCR.Name = "job"
+	intervalJob.BatchClient = batchClient
+	intervalJob.CommandMap["expire"].Policy.BackoffSeconds = int32Ptr(0)
+
+	if err := intervalJob.CreateCommandJob(ctx, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	if batchClient.started != 1 || !intervalJob.RunningBatchJobs() {
+		t.Fatalf("expected the batch job to be started")
+	}
+	status := intervalJob.GetMinioJobStatus(ctx)
+	if status.CommandsStatus[0].Result != MinioJobCommandRunning || status.CommandsStatus[0].Batch.ID != "job-1" {
+		t.Fatalf("unexpected status %+v", status.CommandsStatus[0])
+	}
+
+	// the state survives across syncs through the status
+	intervalJob = newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure, batchCommand("expire"), command("after", "expire"))
+	intervalJob.JobCR.Name = "job"
+	intervalJob.BatchClient = batchClient
+	intervalJob.CommandMap["expire"].Policy.BackoffSeconds = int32Ptr(0)
+	intervalJob.LoadState(status.CommandsStatus, nil)
+	if !intervalJob.RunningBatchJobs() {
+		t.Fatalf("expected the batch job to be running after loading the status")
+	}
+
+	// a failed batch job is retried
+	batchClient.metrics["job-1"] = madmin.JobMetric{JobID: "job-1", Failed: true, LastUpdate: time.Now(), Expired: &madmin.ExpirationInfo{Objects: 3, ObjectsFailed: 1}}
+	if err := intervalJob.SyncBatchJobs(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if result := intervalJob.CommandMap["expire"].Result(); result != MinioJobCommandRetrying {
+		t.Fatalf("expected the batch command to be retried, got %s", result)
+	}
+	if err := intervalJob.CreateCommandJob(ctx, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	if batchClient.started != 2 || intervalJob.CommandMap["expire"].Batch.ID != "job-2" {
+		t.Fatalf("expected the batch job to be started again")
+	}
+
+	// the commands depending on it run once it completed
+	batchClient.metrics["job-2"] = madmin.JobMetric{JobID: "job-2", Complete: true, LastUpdate: time.Now(), Expired: &madmin.ExpirationInfo{Objects: 4, Bucket: "memes", Object: "old.png"}}
+	if err := intervalJob.SyncBatchJobs(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	status = intervalJob.GetMinioJobStatus(ctx)
+	if status.CommandsStatus[0].Result != MinioJobCommandSuccess || status.CommandsStatus[0].Attempts != 2 {
+		t.Fatalf("unexpected status %+v", status.CommandsStatus[0])
+	}
+	if batch := status.CommandsStatus[0].Batch; batch.Objects != 4 || batch.LastObject != "old.png" {
+		t.Errorf("unexpected progress %+v", batch)
+	}
+	if names := readyNames(intervalJob); len(names) != 1 || names[0] != "after" {
+		t.Errorf("expected the dependent command to be ready, got %v", names)
+	}
+	if intervalJob.RunningBatchJobs() {
+		t.Errorf("no batch job is running anymore")
+	}
+}
+
+func TestBatchJobTimeoutAndCancel(t *testing.T) {
+	ctx := context.Background()
+	batchClient := &fakeBatchClient{metrics: map[string]madmin.JobMetric{}}
+	intervalJob := newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure, batchCommand("slow"), batchCommand("other"))
+	intervalJob.BatchClient = batchClient
+	timeout := int64(60)
+	intervalJob.CommandMap["slow"].Policy = v1alpha1.CommandPolicy{Retries: int32Ptr(0), TimeoutSeconds: &timeout}
+	if err := intervalJob.CreateCommandJob(ctx, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	batchClient.metrics["job-1"] = madmin.JobMetric{JobID: "job-1", StartTime: now.Add(-2 * time.Minute)}
+	batchClient.metrics["job-2"] = madmin.JobMetric{JobID: "job-2", StartTime: now.Add(-2 * time.Minute)}
+	if err := intervalJob.SyncBatchJobs(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if result := intervalJob.CommandMap["slow"].Result(); result != MinioJobCommandTimedOut {
+		t.Errorf("expected the batch command to time out, got %s", result)
+	}
+	if len(batchClient.cancelled) != 1 || batchClient.cancelled[0] != "job-1" {
+		t.Fatalf("expected the timed out batch job to be cancelled, got %v", batchClient.cancelled)
+	}
+
+	if err := intervalJob.CancelBatchJobs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	intervalJob.Cancel()
+	if len(batchClient.cancelled) != 2 || batchClient.cancelled[1] != "job-2" {
+		t.Errorf("expected the running batch job to be cancelled, got %v", batchClient.cancelled)
+	}
+	if status := intervalJob.GetMinioJobStatus(ctx); status.Phase != MinioJobPhaseCancelled {
+		t.Errorf("unexpected phase %s", status.Phase)
+	}
+}
+
+func TestCheckBatchPolicy(t *testing.T) {
+	policy, err := iampolicy.ParseConfig(strings.NewReader(`{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":["admin:StartBatchJob"]},
+		{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::memes","arn:aws:s3:::memes/*"]},
+		{"Effect":"Allow","Action":["s3:GetObject","s3:ListBucket"],"Resource":["arn:aws:s3:::archive","arn:aws:s3:::archive/*"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name        string
+		definition  string
+		expectError bool
+	}{
+		{
+			name:       "expire a bucket of the policy",
+			definition: testExpireDefinition,
+		},
+		{
+			name:        "expire a bucket outside of the policy",
+			definition:  strings.Replace(testExpireDefinition, "bucket: memes", "bucket: secrets", 1),
+			expectError: true,
+		},
+		{
+			name:        "expire a read only bucket",
+			definition:  strings.Replace(testExpireDefinition, "bucket: memes", "bucket: archive", 1),
+			expectError: true,
+		},
+		{
+			name:       "replicate between buckets of the policy",
+			definition: "replicate:\n  apiVersion: v1\n  source:\n    bucket: archive\n  target:\n    bucket: memes\n",
+		},
+		{
+			name:        "replicate into a bucket outside of the policy",
+			definition:  "replicate:\n  apiVersion: v1\n  source:\n    bucket: memes\n  target:\n    bucket: secrets\n",
+			expectError: true,
+		},
+		{
+			name:       "replicate to a remote bucket",
+			definition: "replicate:\n  apiVersion: v1\n  source:\n    bucket: archive\n  target:\n    endpoint: https://remote:9000\n    bucket: secrets\n",
+		},
+		{
+			name:        "rotate the keys of a bucket outside of the policy",
+			definition:  "keyrotate:\n  apiVersion: v1\n  bucket: secrets\n",
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := CheckBatchPolicy(*policy, tc.definition); (err != nil) != tc.expectError {
+				t.Errorf("expected error %v, got %v", tc.expectError, err)
+			}
+		})
+	}
+
+	// the policy has to allow submitting batch jobs
+	policy.Statements = policy.Statements[1:]
+	if err = CheckBatchPolicy(*policy, testExpireDefinition); err == nil {
+		t.Errorf("expected an error without %s", iampolicy.StartBatchJobAction)
+	}
+}
@@ -0,0 +1,50 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+)
+
+const (
+	// AccessKeyFinalizer - finalizer of the MinIOJobs with an access key to revoke
+	AccessKeyFinalizer = "job.min.io/access-key"
+	// AccessKeySecretKey - key of the job secret holding the access key the jobs use in access key mode
+	AccessKeySecretKey = "accessKey"
+)
+
+// JobSecretName - name of the secret holding the mc configuration of the jobs of a MinIOJob
+func JobSecretName(jobCRName string) string {
+	return fmt.Sprintf("%s-job-secret", jobCRName)
+}
+
+// UseAccessKey - check if the jobs of the MinIOJob use an access key created for it instead of STS
+func UseAccessKey(jobCR *v1alpha1.MinIOJob) bool {
+	return jobCR.Spec.CredentialMode == v1alpha1.CredentialModeAccessKey
+}
+
+// AccessKeySecretData - the content of the job secret in access key mode, mc reads the credentials from the host
+func AccessKeySecretData(tenant *miniov2.Tenant, accessKey, secretKey string) map[string][]byte {
+	return map[string][]byte{
+		"MC_HOST_myminio":  []byte(fmt.Sprintf("https://%s@%s", url.UserPassword(accessKey, secretKey), tenant.MinIOFQDNServiceName())),
+		AccessKeySecretKey: []byte(accessKey),
+	}
+}
@@ -0,0 +1,157 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	batchjobv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CommandContainer - name of the container running the command in the job pods
+	CommandContainer = "mc"
+	// CommandOutputTailLines - max number of lines of output kept in the status of a command
+	CommandOutputTailLines = 20
+	// CommandOutputTailBytes - max size of the output kept in the status of a command
+	CommandOutputTailBytes = 2048
+)
+
+// secretValues - matches the values of the secret keys, session tokens and passwords printed by mc, as text or JSON
+var secretValues = regexp.MustCompile(`(?i)((?:secret[ _-]?key|session[ _-]?token|password)"?\s*[:=]\s*"?)[^\s",}]+`)
+
+// OutputSecretName - name of the Secret keeping the full output of the commands of a MinIOJob
+func OutputSecretName(jobCRName string) string {
+	return fmt.Sprintf("%s-output", jobCRName)
+}
+
+// RedactOutput - hide the secret keys, session tokens and passwords printed in the output
+func RedactOutput(output string) string {
+	return secretValues.ReplaceAllString(output, "${1}[REDACTED]")
+}
+
+// OutputHasCredentials - check if the command prints new credentials, like `mc admin user svcacct add`, its output
+// is never captured
+func (jobCommand *MinIOIntervalJobCommand) OutputHasCredentials() bool {
+	words := strings.Split(jobCommand.MCOperation, "/")
+	if len(jobCommand.CommandSpec.Command) > 0 {
+		words = strings.Fields(strings.Join(jobCommand.CommandSpec.Command, " "))
+	}
+	for i := 1; i < len(words); i++ {
+		if (words[i-1] == "svcacct" && words[i] == "add") || (words[i-1] == "accesskey" && words[i] == "create") {
+			return true
+		}
+	}
+	return false
+}
+
+// TailOutput - keep the last lines of the output, within the size limit of the command status, with the secrets
+// redacted
+func TailOutput(output string) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > CommandOutputTailLines {
+		lines = lines[len(lines)-CommandOutputTailLines:]
+	}
+	output = RedactOutput(strings.Join(lines, "\n"))
+	if len(output) > CommandOutputTailBytes {
+		output = output[len(output)-CommandOutputTailBytes:]
+		// don't start in the middle of a multibyte character
+		for len(output) > 0 && !utf8.RuneStart(output[0]) {
+			output = output[1:]
+		}
+	}
+	return output
+}
+
+// JobCompletionTime - get when the job succeeded or failed, nil if it's still running
+func JobCompletionTime(job *batchjobv1.Job) *metav1.Time {
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime
+	}
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchjobv1.JobComplete || condition.Type == batchjobv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			completionTime := condition.LastTransitionTime
+			return &completionTime
+		}
+	}
+	return nil
+}
+
+// LatestPod - get the most recently created pod, nil if there is none
+func LatestPod(pods []corev1.Pod) *corev1.Pod {
+	var latest *corev1.Pod
+	for i := range pods {
+		if latest == nil || latest.CreationTimestamp.Before(&pods[i].CreationTimestamp) {
+			latest = &pods[i]
+		}
+	}
+	return latest
+}
+
+// terminationMessagePolicy - the logs of a command printing credentials never make it to the termination message
+func (jobCommand *MinIOIntervalJobCommand) terminationMessagePolicy() corev1.TerminationMessagePolicy {
+	if jobCommand.OutputHasCredentials() {
+		return corev1.TerminationMessageReadFile
+	}
+	return corev1.TerminationMessageFallbackToLogsOnError
+}
+
+// LoadPodState - record the exit code and termination message of the command from the pods of its job
+func (jobCommand *MinIOIntervalJobCommand) LoadPodState(pods []corev1.Pod) {
+	if jobCommand == nil {
+		return
+	}
+	jobCommand.mutex.Lock()
+	defer jobCommand.mutex.Unlock()
+	latest := LatestPod(pods)
+	if latest == nil {
+		return
+	}
+	for _, containerStatus := range latest.Status.ContainerStatuses {
+		if containerStatus.Name != CommandContainer {
+			continue
+		}
+		terminated := containerStatus.State.Terminated
+		if terminated == nil {
+			// restarting after a failure
+			terminated = containerStatus.LastTerminationState.Terminated
+		}
+		if terminated == nil {
+			return
+		}
+		exitCode := terminated.ExitCode
+		jobCommand.ExitCode = &exitCode
+		if terminated.Message != "" && !jobCommand.OutputHasCredentials() {
+			jobCommand.Output = TailOutput(terminated.Message)
+		}
+	}
+}
+
+// SetOutput - set the tail of the output of the command, unless it prints credentials
+func (jobCommand *MinIOIntervalJobCommand) SetOutput(output string) {
+	if jobCommand == nil || jobCommand.OutputHasCredentials() {
+		return
+	}
+	jobCommand.mutex.Lock()
+	jobCommand.Output = TailOutput(output)
+	jobCommand.mutex.Unlock()
+}
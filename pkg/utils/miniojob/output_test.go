@@ -0,0 +1,181 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	batchjobv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTailOutput(t *testing.T) {
+	lines := []string{}
+	for i := 0; i < 30; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	tail := TailOutput(strings.Join(lines, "\n") + "\n")
+	if tail != strings.Join(lines[10:], "\n") {
+		t.Errorf("TailOutput() = %q", tail)
+	}
+	long := strings.Repeat("é", CommandOutputTailBytes)
+	tail = TailOutput(long)
+	if len(tail) > CommandOutputTailBytes || !strings.HasPrefix(tail, "é") {
+		t.Errorf("TailOutput() kept %d bytes, starting with %q", len(tail), tail[:2])
+	}
+	if tail = TailOutput("done"); tail != "done" {
+		t.Errorf("TailOutput() = %q", tail)
+	}
+}
+
+func TestLoadPodState(t *testing.T) {
+	now := time.Now()
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "first", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  CommandContainer,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2, Message: "first error"}},
+			}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "second", CreationTimestamp: metav1.NewTime(now)},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 CommandContainer,
+				RestartCount:         1,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "mc: <ERROR> Unable to make bucket"}},
+			}}},
+		},
+	}
+	command := &MinIOIntervalJobCommand{JobName: "mb"}
+	command.LoadPodState(pods)
+	if command.ExitCode == nil || *command.ExitCode != 1 {
+		t.Errorf("LoadPodState() exit code = %v, expect 1", command.ExitCode)
+	}
+	if command.Output != "mc: <ERROR> Unable to make bucket" {
+		t.Errorf("LoadPodState() output = %q", command.Output)
+	}
+	// the state is kept once the pods are deleted
+	command.LoadPodState(nil)
+	if command.ExitCode == nil || *command.ExitCode != 1 {
+		t.Errorf("LoadPodState() exit code = %v, expect 1", command.ExitCode)
+	}
+}
+
+func TestRedactOutput(t *testing.T) {
+	testCases := map[string]string{
+		"Access Key: ABCDEF\nSecret Key: s3cr3t/key+\nExpiration: no-expiry":                  "Access Key: ABCDEF\nSecret Key: [REDACTED]\nExpiration: no-expiry",
+		`{"status":"success","accessKey":"ABCDEF","secretKey":"s3cr3t","sessionToken":"tok"}`: `{"status":"success","accessKey":"ABCDEF","secretKey":"[REDACTED]","sessionToken":"[REDACTED]"}`,
+		"Added user `alice` successfully.":                                                    "Added user `alice` successfully.",
+	}
+	for output, expect := range testCases {
+		if redacted := RedactOutput(output); redacted != expect {
+			t.Errorf("RedactOutput(%q) = %q, expect %q", output, redacted, expect)
+		}
+	}
+}
+
+func TestOutputHasCredentials(t *testing.T) {
+	testCases := []struct {
+		spec   v1alpha1.CommandSpec
+		expect bool
+	}{
+		{spec: v1alpha1.CommandSpec{Operation: "admin/accesskey/create", Args: map[string]string{"user": "alice"}}, expect: true},
+		{spec: v1alpha1.CommandSpec{Operation: "admin/user/svcacct/add", Args: map[string]string{"user": "alice"}}, expect: true},
+		{spec: v1alpha1.CommandSpec{Command: []string{"mc", "admin", "accesskey", "create", "myminio", "alice"}}, expect: true},
+		{spec: v1alpha1.CommandSpec{Command: []string{"sh", "-c", "mc admin user svcacct add myminio alice"}}, expect: true},
+		{spec: v1alpha1.CommandSpec{Operation: "admin/user/add", Args: map[string]string{"user": "alice", "password": "secret"}}},
+		{spec: v1alpha1.CommandSpec{Command: []string{"mc", "admin", "accesskey", "ls", "myminio"}}},
+	}
+	for _, tc := range testCases {
+		command, err := GenerateMinIOIntervalJobCommand(tc.spec, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := command.OutputHasCredentials(); got != tc.expect {
+			t.Errorf("OutputHasCredentials() of %v = %v, expect %v", tc.spec, got, tc.expect)
+		}
+		if policy := command.terminationMessagePolicy(); (policy == corev1.TerminationMessageReadFile) != tc.expect {
+			t.Errorf("unexpected termination message policy %s for %v", policy, tc.spec)
+		}
+	}
+
+	// neither the termination message nor the logs of the command are kept
+	command, _ := GenerateMinIOIntervalJobCommand(testCases[0].spec, 0)
+	command.LoadPodState([]corev1.Pod{{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:  CommandContainer,
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Message: "Secret Key: s3cr3t"}},
+	}}}}})
+	command.SetOutput("Access Key: ABCDEF\nSecret Key: s3cr3t")
+	if command.ExitCode == nil || command.Output != "" {
+		t.Errorf("expected only the exit code of the command, got output %q", command.Output)
+	}
+}
+
+func TestLoadStateExecution(t *testing.T) {
+	startTime := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	failedTime := metav1.NewTime(time.Now().Truncate(time.Second))
+	retries := int32(2)
+	intervalJob := &MinIOIntervalJob{CommandMap: map[string]*MinIOIntervalJobCommand{}}
+	for _, name := range []string{"mb", "policy"} {
+		command := &MinIOIntervalJobCommand{JobName: name, Policy: v1alpha1.CommandPolicy{Retries: &retries}}
+		intervalJob.Command = append(intervalJob.Command, command)
+		intervalJob.CommandMap[name] = command
+	}
+	exitCode := int32(0)
+	commandsStatus := []v1alpha1.CommandStatus{{
+		Name:           "mb",
+		Result:         MinioJobCommandSuccess,
+		ExitCode:       &exitCode,
+		StartTime:      &startTime,
+		CompletionTime: &startTime,
+		Attempts:       1,
+		Output:         "Bucket created successfully",
+	}}
+	jobs := []batchjobv1.Job{{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{MinioJobName: "policy", MinioJobAttempt: "3"}},
+		Status: batchjobv1.JobStatus{
+			StartTime: &startTime,
+			Failed:    1,
+			Conditions: []batchjobv1.JobCondition{{
+				Type:               batchjobv1.JobFailed,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: failedTime,
+				Message:            "Job has reached the specified backoff limit",
+			}},
+		},
+	}}
+	intervalJob.LoadState(commandsStatus, jobs)
+	status := intervalJob.GetMinioJobStatus(context.Background())
+	mb, policy := status.CommandsStatus[0], status.CommandsStatus[1]
+	if mb.Output != "Bucket created successfully" || mb.ExitCode == nil || *mb.ExitCode != 0 || mb.Attempts != 1 {
+		t.Errorf("status not restored: %+v", mb)
+	}
+	if policy.Result != MinioJobCommandFailed || policy.Attempts != 3 {
+		t.Errorf("unexpected failed command status: %+v", policy)
+	}
+	if policy.StartTime == nil || !policy.StartTime.Equal(&startTime) || policy.CompletionTime == nil || !policy.CompletionTime.Equal(&failedTime) {
+		t.Errorf("unexpected failed command times: %v - %v", policy.StartTime, policy.CompletionTime)
+	}
+}
@@ -0,0 +1,66 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"fmt"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+)
+
+// Plan - render the commands of the job and the order they run in, the commands must have been validated
+// In sequential mode every command is a stage of its own, in parallel mode a command runs in the stage after the last
+// of its dependencies.
+func (intervalJob *MinIOIntervalJob) Plan() []v1alpha1.CommandPlan {
+	plan := make([]v1alpha1.CommandPlan, len(intervalJob.Command))
+	stages := make(map[string]int32, len(intervalJob.Command))
+	var stage func(command *MinIOIntervalJobCommand) int32
+	stage = func(command *MinIOIntervalJobCommand) int32 {
+		if s, found := stages[command.JobName]; found {
+			return s
+		}
+		s := int32(0)
+		for _, dep := range command.CommandSpec.DependsOn {
+			if depStage := stage(intervalJob.CommandMap[dep]) + 1; depStage > s {
+				s = depStage
+			}
+		}
+		stages[command.JobName] = s
+		return s
+	}
+	for i, command := range intervalJob.Command {
+		plan[i] = v1alpha1.CommandPlan{
+			Name:      command.JobName,
+			Command:   command.MCCommand(),
+			DependsOn: command.CommandSpec.DependsOn,
+			Stage:     int32(i),
+		}
+		if intervalJob.JobCR.Spec.Execution != v1alpha1.Sequential {
+			plan[i].Stage = stage(command)
+		}
+	}
+	return plan
+}
+
+// GetPlanStatus - get the status of a dry run of the job
+func (intervalJob *MinIOIntervalJob) GetPlanStatus() v1alpha1.MinIOJobStatus {
+	return v1alpha1.MinIOJobStatus{
+		Phase:   MinioJobPhasePlanned,
+		Message: fmt.Sprintf("Dry run, %d commands planned, no job created", len(intervalJob.Command)),
+		Plan:    intervalJob.Plan(),
+	}
+}
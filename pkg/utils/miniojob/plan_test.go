@@ -0,0 +1,58 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+)
+
+func TestPlan(t *testing.T) {
+	bucket := v1alpha1.CommandSpec{Name: "bucket", Operation: "make-bucket", Args: map[string]string{"name": "memes"}}
+	specs := []v1alpha1.CommandSpec{command("user"), bucket, command("policy", "user", "bucket"), command("attach", "policy"), command("ls")}
+
+	intervalJob := newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure, specs...)
+	status := intervalJob.GetPlanStatus()
+	if status.Phase != MinioJobPhasePlanned || len(status.Plan) != len(specs) {
+		t.Fatalf("unexpected plan status %+v", status)
+	}
+	stages := []int32{}
+	for _, command := range status.Plan {
+		stages = append(stages, command.Stage)
+	}
+	if !reflect.DeepEqual(stages, []int32{0, 0, 1, 2, 0}) {
+		t.Errorf("parallel stages = %v", stages)
+	}
+	if got := strings.Join(status.Plan[1].Command, " "); got != "mc mb myminio/memes --ignore-existing" {
+		t.Errorf("rendered command = %s", got)
+	}
+	if !reflect.DeepEqual(status.Plan[2].DependsOn, []string{"user", "bucket"}) {
+		t.Errorf("dependsOn = %v", status.Plan[2].DependsOn)
+	}
+
+	intervalJob = newTestIntervalJob(t, v1alpha1.Sequential, v1alpha1.ContinueOnFailure, specs...)
+	stages = []int32{}
+	for _, command := range intervalJob.Plan() {
+		stages = append(stages, command.Stage)
+	}
+	if !reflect.DeepEqual(stages, []int32{0, 1, 2, 3, 4}) {
+		t.Errorf("sequential stages = %v", stages)
+	}
+}
@@ -0,0 +1,192 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	batchjobv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultRetries - times a failed command runs again by default, the same as the backoff limit of a Kubernetes Job
+	DefaultRetries = 6
+	// DefaultBackoffSeconds - delay before the first retry of a failed command by default
+	DefaultBackoffSeconds = 10
+	// DefaultMaxBackoffSeconds - max delay between two retries of a command by default
+	DefaultMaxBackoffSeconds = 360
+)
+
+// MergePolicy - get the policy of a command, the fields it doesn't set are taken from the defaults of the MinIOJob
+func MergePolicy(defaults, policy v1alpha1.CommandPolicy) v1alpha1.CommandPolicy {
+	if policy.Retries == nil {
+		policy.Retries = defaults.Retries
+	}
+	if policy.BackoffSeconds == nil {
+		policy.BackoffSeconds = defaults.BackoffSeconds
+	}
+	if policy.MaxBackoffSeconds == nil {
+		policy.MaxBackoffSeconds = defaults.MaxBackoffSeconds
+	}
+	if policy.TimeoutSeconds == nil {
+		policy.TimeoutSeconds = defaults.TimeoutSeconds
+	}
+	if policy.TTLSecondsAfterFinished == nil {
+		policy.TTLSecondsAfterFinished = defaults.TTLSecondsAfterFinished
+	}
+	return policy
+}
+
+// JobAttempt - get the attempt of the command a job runs, jobs created before the operator managed the retries
+// count as the first attempt
+func JobAttempt(job *batchjobv1.Job) int32 {
+	attempt, err := strconv.ParseInt(job.Labels[MinioJobAttempt], 10, 32)
+	if err != nil || attempt < 1 {
+		return 1
+	}
+	return int32(attempt)
+}
+
+// retries - number of times the command runs again after a failure
+func (jobCommand *MinIOIntervalJobCommand) retries() int32 {
+	if jobCommand.Policy.Retries == nil {
+		return DefaultRetries
+	}
+	return *jobCommand.Policy.Retries
+}
+
+// backoff - delay before running the command again after the given attempt failed
+func (jobCommand *MinIOIntervalJobCommand) backoff(attempt int32) time.Duration {
+	backoffSeconds, maxBackoffSeconds := int32(DefaultBackoffSeconds), int32(DefaultMaxBackoffSeconds)
+	if jobCommand.Policy.BackoffSeconds != nil {
+		backoffSeconds = *jobCommand.Policy.BackoffSeconds
+	}
+	if jobCommand.Policy.MaxBackoffSeconds != nil {
+		maxBackoffSeconds = *jobCommand.Policy.MaxBackoffSeconds
+	}
+	backoff := time.Duration(backoffSeconds) * time.Second
+	maxBackoff := time.Duration(maxBackoffSeconds) * time.Second
+	for i := int32(1); i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// loadJob - update the state of the command from the job of its latest attempt
+func (jobCommand *MinIOIntervalJobCommand) loadJob(job *batchjobv1.Job) {
+	attempt := JobAttempt(job)
+	_, managed := job.Labels[MinioJobAttempt]
+	jobCommand.mutex.Lock()
+	defer jobCommand.mutex.Unlock()
+	if managed && attempt < jobCommand.Attempts {
+		// the job of a later attempt was deleted after it finished, the status is more recent
+		return
+	}
+	jobCommand.Created = true
+	jobCommand.Skipped = false
+	jobCommand.RetryAt = nil
+	if attempt == 1 || jobCommand.StartTime == nil {
+		jobCommand.StartTime = job.Status.StartTime
+	}
+	jobCommand.CompletionTime = JobCompletionTime(job)
+	if managed || jobCommand.Attempts == 0 {
+		jobCommand.Attempts = attempt
+	}
+	if job.Status.Succeeded > 0 {
+		jobCommand.Succeeded = true
+		jobCommand.Failed = false
+		jobCommand.TimedOut = false
+		jobCommand.Message = ""
+		return
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type != batchjobv1.JobFailed || condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		jobCommand.Message = condition.Message
+		// jobs created before the operator managed the retries already retried within the job
+		if managed && attempt <= jobCommand.retries() && jobCommand.CompletionTime != nil {
+			retryAt := metav1.NewTime(jobCommand.CompletionTime.Add(jobCommand.backoff(attempt)))
+			jobCommand.RetryAt = &retryAt
+			jobCommand.Failed = false
+			jobCommand.TimedOut = false
+			return
+		}
+		jobCommand.Failed = true
+		jobCommand.TimedOut = condition.Reason == batchjobv1.JobReasonDeadlineExceeded
+		return
+	}
+}
+
+// loseJob - fail the attempt of a running command whose job is gone, the command is never created again unless its
+// retry policy runs it again
+func (jobCommand *MinIOIntervalJobCommand) loseJob(now time.Time) {
+	if jobCommand.IsBatch() {
+		return
+	}
+	jobCommand.mutex.Lock()
+	defer jobCommand.mutex.Unlock()
+	if !jobCommand.Created || jobCommand.RetryAt != nil || jobCommand.Succeeded || jobCommand.Failed || jobCommand.Skipped || jobCommand.Cancelled {
+		return
+	}
+	attempt := jobCommand.Attempts
+	if attempt < 1 {
+		attempt = 1
+		jobCommand.Attempts = attempt
+	}
+	jobCommand.Message = fmt.Sprintf("the job of attempt %d was lost before it completed", attempt)
+	if attempt <= jobCommand.retries() {
+		retryAt := metav1.NewTime(now.Add(jobCommand.backoff(attempt)))
+		jobCommand.RetryAt = &retryAt
+		return
+	}
+	jobCommand.Failed = true
+}
+
+// RetryCommands - get the failed commands whose backoff is over, to run them again
+func (intervalJob *MinIOIntervalJob) RetryCommands(now time.Time) []*MinIOIntervalJobCommand {
+	ready := []*MinIOIntervalJobCommand{}
+	for _, command := range intervalJob.Command {
+		command.mutex.RLock()
+		if command.RetryAt != nil && !command.RetryAt.After(now) {
+			ready = append(ready, command)
+		}
+		command.mutex.RUnlock()
+	}
+	return ready
+}
+
+// NextRetryTime - get when the next failed command runs again, zero if no command is waiting for a retry
+func (intervalJob *MinIOIntervalJob) NextRetryTime() time.Time {
+	var next time.Time
+	for _, command := range intervalJob.Command {
+		command.mutex.RLock()
+		if command.RetryAt != nil && (next.IsZero() || command.RetryAt.Time.Before(next)) {
+			next = command.RetryAt.Time
+		}
+		command.mutex.RUnlock()
+	}
+	return next
+}
@@ -0,0 +1,206 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	batchjobv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+func TestMergePolicy(t *testing.T) {
+	timeout := int64(60)
+	defaults := v1alpha1.CommandPolicy{Retries: int32Ptr(2), TimeoutSeconds: &timeout}
+	policy := MergePolicy(defaults, v1alpha1.CommandPolicy{Retries: int32Ptr(0), BackoffSeconds: int32Ptr(5)})
+	if *policy.Retries != 0 || *policy.BackoffSeconds != 5 || *policy.TimeoutSeconds != 60 || policy.MaxBackoffSeconds != nil {
+		t.Errorf("MergePolicy() = %+v", policy)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	command := &MinIOIntervalJobCommand{Policy: v1alpha1.CommandPolicy{BackoffSeconds: int32Ptr(5), MaxBackoffSeconds: int32Ptr(30)}}
+	expected := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second}
+	for i, backoff := range expected {
+		if got := command.backoff(int32(i + 1)); got != backoff {
+			t.Errorf("backoff(%d) = %v, expect %v", i+1, got, backoff)
+		}
+	}
+	command = &MinIOIntervalJobCommand{}
+	if got := command.backoff(100); got != DefaultMaxBackoffSeconds*time.Second {
+		t.Errorf("backoff(100) = %v", got)
+	}
+}
+
+func TestLoadStateRetries(t *testing.T) {
+	intervalJob := newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure,
+		command("failed"), command("timeout"), command("retry"), command("rerun"), command("legacy"))
+	intervalJob.JobCR.Name = "job"
+	for _, command := range intervalJob.Command {
+		command.Policy = v1alpha1.CommandPolicy{Retries: int32Ptr(1), BackoffSeconds: int32Ptr(30)}
+	}
+	finished := metav1.NewTime(time.Now().Truncate(time.Second))
+	failedJob := func(name, attempt, reason string) batchjobv1.Job {
+		labels := map[string]string{MinioJobName: name, MinioJobCRName: "job"}
+		if attempt != "" {
+			labels[MinioJobAttempt] = attempt
+		}
+		return batchjobv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Status: batchjobv1.JobStatus{Failed: 1, Conditions: []batchjobv1.JobCondition{{
+				Type:               batchjobv1.JobFailed,
+				Status:             corev1.ConditionTrue,
+				Reason:             reason,
+				LastTransitionTime: finished,
+			}}},
+		}
+	}
+	rerun := failedJob("rerun", "2", "")
+	rerun.Status = batchjobv1.JobStatus{Active: 1}
+	jobs := []batchjobv1.Job{
+		failedJob("failed", "1", batchjobv1.JobReasonBackoffLimitExceeded),
+		failedJob("failed", "2", batchjobv1.JobReasonBackoffLimitExceeded),
+		failedJob("timeout", "2", batchjobv1.JobReasonDeadlineExceeded),
+		failedJob("retry", "1", batchjobv1.JobReasonDeadlineExceeded),
+		failedJob("rerun", "1", batchjobv1.JobReasonBackoffLimitExceeded),
+		rerun,
+		// jobs created before the retries were managed by the operator are never retried
+		failedJob("legacy", "", batchjobv1.JobReasonBackoffLimitExceeded),
+	}
+	intervalJob.LoadState(nil, jobs)
+	expected := map[string]string{
+		"failed":  MinioJobCommandFailed,
+		"timeout": MinioJobCommandTimedOut,
+		"retry":   MinioJobCommandRetrying,
+		"rerun":   MinioJobCommandRetrying,
+		"legacy":  MinioJobCommandFailed,
+	}
+	for name, result := range expected {
+		if got := intervalJob.CommandMap[name].Result(); got != result {
+			t.Errorf("command %s expected result %s, got %s", name, result, got)
+		}
+	}
+	if attempts := intervalJob.CommandMap["failed"].Attempts; attempts != 2 {
+		t.Errorf("command failed expected 2 attempts, got %d", attempts)
+	}
+	retryAt := finished.Add(30 * time.Second)
+	if next := intervalJob.NextRetryTime(); !next.Equal(retryAt) {
+		t.Errorf("NextRetryTime() = %v, expect %v", next, retryAt)
+	}
+	if ready := intervalJob.RetryCommands(retryAt.Add(-time.Second)); len(ready) != 0 {
+		t.Errorf("RetryCommands() before the backoff expected nothing, got %d commands", len(ready))
+	}
+	ready := intervalJob.RetryCommands(retryAt)
+	if len(ready) != 1 || ready[0].JobName != "retry" {
+		t.Fatalf("RetryCommands() expected command retry, got %v", ready)
+	}
+	status := intervalJob.GetMinioJobStatus(context.Background())
+	if status.Phase != MinioJobPhaseRunning {
+		t.Errorf("expected the job to keep running while a command is retrying, got %s", status.Phase)
+	}
+
+	// the retry runs in a new job, named after the attempt
+	objs, attempt := ready[0].createJob(context.Background(), nil, intervalJob.JobCR, intervalJob.Tenant, 4223)
+	if attempt != 2 || len(objs) != 2 {
+		t.Fatalf("createJob() expected the second attempt, got attempt %d", attempt)
+	}
+	job := objs[1].(*batchjobv1.Job)
+	if job.Name != "job-retry-retry-1" || job.Labels[MinioJobAttempt] != "2" {
+		t.Errorf("unexpected retry job %s, labels %v", job.Name, job.Labels)
+	}
+	if *job.Spec.BackoffLimit != 0 || job.Spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("retries must be managed by the operator, got backoffLimit %d", *job.Spec.BackoffLimit)
+	}
+	// a running command is not created again
+	if objs, _ = intervalJob.CommandMap["rerun"].createJob(context.Background(), nil, intervalJob.JobCR, intervalJob.Tenant, 4223); objs != nil {
+		t.Errorf("createJob() expected nothing for a running command")
+	}
+}
+
+func TestLoadStateRetryFromStatus(t *testing.T) {
+	intervalJob := newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure, command("a"))
+	retryAt := metav1.NewTime(time.Now().Add(time.Minute))
+	// the job of the failed attempt was already deleted after its TTL
+	intervalJob.LoadState([]v1alpha1.CommandStatus{
+		{Name: "a", Result: MinioJobCommandRetrying, Attempts: 2, NextRetryTime: &retryAt, Message: "BackoffLimitExceeded"},
+	}, []batchjobv1.Job{{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{MinioJobName: "a", MinioJobAttempt: "1"}},
+		Status:     batchjobv1.JobStatus{Failed: 1},
+	}})
+	command := intervalJob.CommandMap["a"]
+	if command.Result() != MinioJobCommandRetrying || command.Attempts != 2 || !command.RetryAt.Equal(&retryAt) {
+		t.Errorf("unexpected state: result %s, attempts %d, retry at %v", command.Result(), command.Attempts, command.RetryAt)
+	}
+}
+
+func TestLoadStateLostJob(t *testing.T) {
+	newIntervalJob := func() *MinIOIntervalJob {
+		intervalJob := newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure, command("lost"), command("last"), command("running"))
+		intervalJob.JobCR.Name = "job"
+		for _, command := range intervalJob.Command {
+			command.Policy = v1alpha1.CommandPolicy{Retries: int32Ptr(1), BackoffSeconds: int32Ptr(30)}
+		}
+		return intervalJob
+	}
+	commandsStatus := []v1alpha1.CommandStatus{
+		{Name: "lost", Result: MinioJobCommandRunning, Attempts: 1},
+		{Name: "last", Result: MinioJobCommandRetrying, Attempts: 2},
+		{Name: "running", Result: MinioJobCommandRunning, Attempts: 1},
+	}
+	jobs := []batchjobv1.Job{{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{MinioJobName: "running", MinioJobCRName: "job", MinioJobAttempt: "1"}},
+		Status:     batchjobv1.JobStatus{Active: 1},
+	}}
+
+	intervalJob := newIntervalJob()
+	intervalJob.LoadState(commandsStatus, jobs)
+	expected := map[string]string{
+		"lost":    MinioJobCommandRetrying,
+		"last":    MinioJobCommandFailed,
+		"running": MinioJobCommandRunning,
+	}
+	for name, result := range expected {
+		if got := intervalJob.CommandMap[name].Result(); got != result {
+			t.Errorf("command %s expected result %s, got %s", name, result, got)
+		}
+	}
+	// the lost command only runs again once its backoff is over
+	if ready := intervalJob.ReadyCommands(); len(ready) != 0 {
+		t.Errorf("ReadyCommands() expected nothing, got %d commands", len(ready))
+	}
+	if ready := intervalJob.RetryCommands(time.Now()); len(ready) != 0 {
+		t.Errorf("RetryCommands() before the backoff expected nothing, got %d commands", len(ready))
+	}
+	if ready := intervalJob.RetryCommands(time.Now().Add(time.Minute)); len(ready) != 1 || ready[0].JobName != "lost" {
+		t.Errorf("RetryCommands() expected command lost, got %v", ready)
+	}
+
+	// without the jobs nothing is reported lost
+	intervalJob = newIntervalJob()
+	intervalJob.LoadState(commandsStatus, nil)
+	if got := intervalJob.CommandMap["lost"].Result(); got != MinioJobCommandRunning {
+		t.Errorf("command lost expected result %s, got %s", MinioJobCommandRunning, got)
+	}
+}
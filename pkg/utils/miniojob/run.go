@@ -0,0 +1,37 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RunName - name of the scheduled run started at the given schedule tick
+func RunName(scheduleTime time.Time) string {
+	return strconv.FormatInt(scheduleTime.Unix()/60, 10)
+}
+
+// RunScheduleTime - get the schedule tick a run was started at from its name
+func RunScheduleTime(run string) (time.Time, error) {
+	minutes, err := strconv.ParseInt(run, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid run name %s", run)
+	}
+	return time.Unix(minutes*60, 0).UTC(), nil
+}
@@ -0,0 +1,33 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunScheduleTime(t *testing.T) {
+	now := time.Date(2024, 7, 15, 13, 30, 0, 0, time.UTC)
+	run := RunName(now)
+	if scheduleTime, err := RunScheduleTime(run); err != nil || !scheduleTime.Equal(now) {
+		t.Errorf("RunScheduleTime(%s) = %v, %v", run, scheduleTime, err)
+	}
+	if _, err := RunScheduleTime("not-a-run"); err == nil {
+		t.Error("expected an error for an invalid run name")
+	}
+}
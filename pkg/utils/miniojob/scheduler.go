@@ -0,0 +1,175 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+)
+
+// ValidateCommands - validate the dependencies between the commands of a job
+// every dependency must exist, and they must not form a cycle. In sequential mode
+// a command can only depend on the commands listed before it.
+func ValidateCommands(execution v1alpha1.Execution, commands []*MinIOIntervalJobCommand) error {
+	index := make(map[string]int, len(commands))
+	for i, command := range commands {
+		if _, found := index[command.JobName]; found {
+			return fmt.Errorf("command name %s is duplicated", command.JobName)
+		}
+		index[command.JobName] = i
+	}
+	for i, command := range commands {
+		for _, dep := range command.CommandSpec.DependsOn {
+			depIndex, found := index[dep]
+			if !found {
+				return fmt.Errorf("dependent job %s not found", dep)
+			}
+			if depIndex == i {
+				return fmt.Errorf("command %s depends on itself", command.JobName)
+			}
+			if execution == v1alpha1.Sequential && depIndex > i {
+				return fmt.Errorf("command %s depends on %s which runs after it in sequential execution", command.JobName, dep)
+			}
+		}
+	}
+	if cycle := findCycle(commands, index); len(cycle) > 0 {
+		return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findCycle - depth first search over the dependencies, returns the first cycle found
+func findCycle(commands []*MinIOIntervalJobCommand, index map[string]int) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(commands))
+	var path []string
+	var visit func(i int) []string
+	visit = func(i int) []string {
+		state[i] = visiting
+		path = append(path, commands[i].JobName)
+		for _, dep := range commands[i].CommandSpec.DependsOn {
+			depIndex := index[dep]
+			switch state[depIndex] {
+			case visiting:
+				// the cycle starts at the first appearance of dep in the current path
+				for start, name := range path {
+					if name == dep {
+						return append(append([]string{}, path[start:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(depIndex); len(cycle) > 0 {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+	for i := range commands {
+		if state[i] == unvisited {
+			if cycle := visit(i); len(cycle) > 0 {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// ReadyCommands - get the commands whose job can be created now
+// commands that can no longer run, because a dependency did not succeed or the job
+// stops on failure, are marked as skipped.
+func (intervalJob *MinIOIntervalJob) ReadyCommands() []*MinIOIntervalJobCommand {
+	stopOnFailure := intervalJob.JobCR.Spec.FailureStrategy == v1alpha1.StopOnFailure
+	if stopOnFailure {
+		for _, command := range intervalJob.Command {
+			if failedResult(command.Result()) {
+				intervalJob.skipPending()
+				return nil
+			}
+		}
+	}
+	// skipping a command may prevent a command listed before it from running, repeat until nothing changes
+	for skipped := true; skipped; {
+		skipped = false
+		for _, command := range intervalJob.Command {
+			if command.Result() != MinioJobCommandPending {
+				continue
+			}
+			for _, dep := range command.CommandSpec.DependsOn {
+				result := intervalJob.CommandMap[dep].Result()
+				if failedResult(result) || result == MinioJobCommandSkipped {
+					command.Skip()
+					skipped = true
+					break
+				}
+			}
+		}
+	}
+	ready := []*MinIOIntervalJobCommand{}
+	for _, command := range intervalJob.Command {
+		result := command.Result()
+		if intervalJob.JobCR.Spec.Execution == v1alpha1.Sequential {
+			// commands run strictly one after the other, wait for the previous one to finish
+			if result == MinioJobCommandRunning || result == MinioJobCommandRetrying {
+				return nil
+			}
+			if result == MinioJobCommandPending {
+				if intervalJob.dependenciesSucceeded(command) {
+					ready = append(ready, command)
+				}
+				return ready
+			}
+			continue
+		}
+		if result == MinioJobCommandPending && intervalJob.dependenciesSucceeded(command) {
+			ready = append(ready, command)
+		}
+	}
+	return ready
+}
+
+// failedResult - check if the command failed for good, it won't be retried
+func failedResult(result string) bool {
+	return result == MinioJobCommandFailed || result == MinioJobCommandTimedOut
+}
+
+// dependenciesSucceeded - check if all the dependencies of the command succeeded
+func (intervalJob *MinIOIntervalJob) dependenciesSucceeded(command *MinIOIntervalJobCommand) bool {
+	for _, dep := range command.CommandSpec.DependsOn {
+		if !intervalJob.CommandMap[dep].Success() {
+			return false
+		}
+	}
+	return true
+}
+
+// skipPending - mark all the commands that were not created yet as skipped
+func (intervalJob *MinIOIntervalJob) skipPending() {
+	for _, command := range intervalJob.Command {
+		if command.Result() == MinioJobCommandPending {
+			command.Skip()
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// This file is part of MinIO Operator
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package miniojob
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/operator/pkg/apis/job.min.io/v1alpha1"
+	miniov2 "github.com/minio/operator/pkg/apis/minio.min.io/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestIntervalJob(t *testing.T, execution v1alpha1.Execution, strategy v1alpha1.FailureStrategy, specs ...v1alpha1.CommandSpec) *MinIOIntervalJob {
+	intervalJob := &MinIOIntervalJob{
+		JobCR: &v1alpha1.MinIOJob{
+			Spec: v1alpha1.MinIOJobSpec{
+				Execution:       execution,
+				FailureStrategy: strategy,
+				Commands:        specs,
+			},
+		},
+		CommandMap: map[string]*MinIOIntervalJobCommand{},
+		Tenant:     &miniov2.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "myminio", Namespace: "ns"}},
+	}
+	for i, spec := range specs {
+		command, err := GenerateMinIOIntervalJobCommand(spec, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		intervalJob.Command = append(intervalJob.Command, command)
+		intervalJob.CommandMap[command.JobName] = command
+	}
+	return intervalJob
+}
+
+func readyNames(intervalJob *MinIOIntervalJob) []string {
+	names := []string{}
+	for _, command := range intervalJob.ReadyCommands() {
+		names = append(names, command.JobName)
+		command.Created = true
+	}
+	return names
+}
+
+func command(name string, dependsOn ...string) v1alpha1.CommandSpec {
+	return v1alpha1.CommandSpec{
+		Name:      name,
+		Command:   []string{"mc", "ls", "myminio"},
+		DependsOn: dependsOn,
+	}
+}
+
+func TestValidateCommands(t *testing.T) {
+	testCases := []struct {
+		name        string
+		execution   v1alpha1.Execution
+		commands    []v1alpha1.CommandSpec
+		expectError bool
+	}{
+		{
+			name:      "valid dag",
+			execution: v1alpha1.Parallel,
+			commands:  []v1alpha1.CommandSpec{command("a"), command("b", "a"), command("c", "a", "b")},
+		},
+		{
+			name:      "forward dependency in parallel",
+			execution: v1alpha1.Parallel,
+			commands:  []v1alpha1.CommandSpec{command("a", "b"), command("b")},
+		},
+		{
+			name:        "forward dependency in sequential",
+			execution:   v1alpha1.Sequential,
+			commands:    []v1alpha1.CommandSpec{command("a", "b"), command("b")},
+			expectError: true,
+		},
+		{
+			name:        "missing dependency",
+			execution:   v1alpha1.Parallel,
+			commands:    []v1alpha1.CommandSpec{command("a", "z")},
+			expectError: true,
+		},
+		{
+			name:        "self dependency",
+			execution:   v1alpha1.Parallel,
+			commands:    []v1alpha1.CommandSpec{command("a", "a")},
+			expectError: true,
+		},
+		{
+			name:        "cycle",
+			execution:   v1alpha1.Parallel,
+			commands:    []v1alpha1.CommandSpec{command("a", "c"), command("b", "a"), command("c", "b")},
+			expectError: true,
+		},
+		{
+			name:        "duplicated names",
+			execution:   v1alpha1.Parallel,
+			commands:    []v1alpha1.CommandSpec{command("a"), command("a")},
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			intervalJob := newTestIntervalJob(t, tc.execution, v1alpha1.ContinueOnFailure, tc.commands...)
+			err := ValidateCommands(tc.execution, intervalJob.Command)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("ValidateCommands() error = %v, expectError %v", err, tc.expectError)
+			}
+		})
+	}
+}
+
+func TestReadyCommandsSequential(t *testing.T) {
+	intervalJob := newTestIntervalJob(t, v1alpha1.Sequential, v1alpha1.ContinueOnFailure, command("a"), command("b"), command("c"))
+	if got := readyNames(intervalJob); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("expected only a to be ready, got %v", got)
+	}
+	if got := readyNames(intervalJob); len(got) != 0 {
+		t.Fatalf("expected nothing ready while a runs, got %v", got)
+	}
+	intervalJob.CommandMap["a"].SetStatus(false, "failed")
+	if got := readyNames(intervalJob); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Fatalf("expected b to run after a failed with continueOnFailure, got %v", got)
+	}
+	intervalJob.CommandMap["b"].SetStatus(true, "")
+	if got := readyNames(intervalJob); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Fatalf("expected c to be ready, got %v", got)
+	}
+}
+
+func TestReadyCommandsParallel(t *testing.T) {
+	intervalJob := newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure,
+		command("a"), command("b"), command("c", "a"), command("d", "b"), command("e", "d"))
+	if got := readyNames(intervalJob); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("expected a and b to be ready, got %v", got)
+	}
+	intervalJob.CommandMap["a"].SetStatus(true, "")
+	intervalJob.CommandMap["b"].SetStatus(false, "failed")
+	if got := readyNames(intervalJob); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Fatalf("expected c to be ready, got %v", got)
+	}
+	for _, name := range []string{"d", "e"} {
+		if result := intervalJob.CommandMap[name].Result(); result != MinioJobCommandSkipped {
+			t.Errorf("expected %s to be skipped, got %s", name, result)
+		}
+	}
+	intervalJob.CommandMap["c"].SetStatus(true, "")
+	status := intervalJob.GetMinioJobStatus(context.Background())
+	if status.Phase != MinioJobPhaseFailed {
+		t.Errorf("expected job phase %s, got %s", MinioJobPhaseFailed, status.Phase)
+	}
+}
+
+func TestReadyCommandsStopOnFailure(t *testing.T) {
+	intervalJob := newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.StopOnFailure,
+		command("a"), command("b", "a"), command("c", "a"))
+	if got := readyNames(intervalJob); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Fatalf("expected a to be ready, got %v", got)
+	}
+	intervalJob.CommandMap["a"].SetStatus(false, "failed")
+	if got := readyNames(intervalJob); len(got) != 0 {
+		t.Fatalf("expected nothing ready after a failure, got %v", got)
+	}
+	status := intervalJob.GetMinioJobStatus(context.Background())
+	expected := []string{MinioJobCommandFailed, MinioJobCommandSkipped, MinioJobCommandSkipped}
+	for i, commandStatus := range status.CommandsStatus {
+		if commandStatus.Result != expected[i] {
+			t.Errorf("expected command %s result %s, got %s", commandStatus.Name, expected[i], commandStatus.Result)
+		}
+	}
+	if status.Phase != MinioJobPhaseFailed {
+		t.Errorf("expected job phase %s, got %s", MinioJobPhaseFailed, status.Phase)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	intervalJob := newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure,
+		command("a"), command("b"), command("c", "a"))
+	if got := readyNames(intervalJob); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("expected a and b to be ready, got %v", got)
+	}
+	intervalJob.CommandMap["a"].SetStatus(true, "")
+	intervalJob.Cancel()
+	if got := readyNames(intervalJob); len(got) != 0 {
+		t.Fatalf("expected nothing ready once cancelled, got %v", got)
+	}
+	status := intervalJob.GetMinioJobStatus(context.Background())
+	if status.Phase != MinioJobPhaseCancelled {
+		t.Errorf("expected job phase %s, got %s", MinioJobPhaseCancelled, status.Phase)
+	}
+	expected := []string{MinioJobCommandSuccess, MinioJobCommandCancelled, MinioJobCommandCancelled}
+	for i, commandStatus := range status.CommandsStatus {
+		if commandStatus.Result != expected[i] {
+			t.Errorf("expected command %s result %s, got %s", commandStatus.Name, expected[i], commandStatus.Result)
+		}
+	}
+
+	// the cancellation is kept in the status
+	intervalJob = newTestIntervalJob(t, v1alpha1.Parallel, v1alpha1.ContinueOnFailure,
+		command("a"), command("b"), command("c", "a"))
+	intervalJob.LoadState(status.CommandsStatus, nil)
+	if got := readyNames(intervalJob); len(got) != 0 {
+		t.Fatalf("expected nothing ready once cancelled, got %v", got)
+	}
+	if phase := intervalJob.GetMinioJobStatus(context.Background()).Phase; phase != MinioJobPhaseCancelled {
+		t.Errorf("expected job phase %s, got %s", MinioJobPhaseCancelled, phase)
+	}
+}
+
+func TestRerunCommandsStatus(t *testing.T) {
+	commandsStatus := []v1alpha1.CommandStatus{
+		{Name: "a", Result: MinioJobCommandSuccess},
+		{Name: "b", Result: MinioJobCommandFailed},
+		{Name: "c", Result: MinioJobCommandSkipped},
+	}
+	if kept := RerunCommandsStatus(commandsStatus, true); len(kept) != 0 {
+		t.Errorf("expected all the commands to run again, kept %v", kept)
+	}
+	kept := RerunCommandsStatus(commandsStatus, false)
+	if len(kept) != 1 || kept[0].Name != "a" {
+		t.Fatalf("expected only the succeeded command to be kept, got %v", kept)
+	}
+	// the commands kept don't run again in the rerun
+	intervalJob := newTestIntervalJob(t, v1alpha1.Sequential, v1alpha1.ContinueOnFailure,
+		command("a"), command("b"), command("c", "b"))
+	intervalJob.StartRun(RerunName(time.Now()))
+	intervalJob.LoadState(kept, nil)
+	if got := readyNames(intervalJob); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("expected the rerun to start from b, got %v", got)
+	}
+}
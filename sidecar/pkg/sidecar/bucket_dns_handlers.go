@@ -71,14 +71,6 @@ func (c *Controller) BucketSrvHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusForbidden)
 		return
 	}
-	if ok {
-		if err = c.kubeClient.CoreV1().Services(namespace).Delete(r.Context(), bucket, metav1.DeleteOptions{}); err != nil {
-			klog.Errorf("failed to delete service:%s for tenant:%s/%s, err:%s", name, namespace, name, err)
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		return
-	}
 
 	// Find the tenant
 	tenant, err := c.controllerClient.MinioV2().Tenants(namespace).Get(r.Context(), name, metav1.GetOptions{})
@@ -88,6 +80,29 @@ func (c *Controller) BucketSrvHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if ok {
+		// Several tenants can live in the namespace, only the service of a bucket of this tenant is deleted
+		service, err := c.kubeClient.CoreV1().Services(namespace).Get(r.Context(), bucket, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return
+		}
+		if err == nil && !metav1.IsControlledBy(service, tenant) {
+			klog.Infof("Service:%s is not owned by tenant:%s/%s, keeping it", bucket, namespace, name)
+			return
+		}
+		if err == nil {
+			err = c.kubeClient.CoreV1().Services(namespace).Delete(r.Context(), bucket, metav1.DeleteOptions{
+				Preconditions: &metav1.Preconditions{UID: &service.UID},
+			})
+		}
+		if err != nil {
+			klog.Errorf("failed to delete service:%s for tenant:%s/%s, err:%s", bucket, namespace, name, err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		return
+	}
+
 	tenant.EnsureDefaults()
 
 	// Validate the MinIO Tenant
@@ -104,10 +119,18 @@ func (c *Controller) BucketSrvHandler(w http.ResponseWriter, r *http.Request) {
 	service := services.ServiceForBucket(tenant, bucket)
 	_, err = c.kubeClient.CoreV1().Services(namespace).Create(r.Context(), service, metav1.CreateOptions{})
 	if err != nil && k8serrors.IsAlreadyExists(err) {
+		// The service names are shared by all the tenants of the namespace, a bucket with the same name in another
+		// tenant, or any other service, already holds the name
+		existing, gerr := c.kubeClient.CoreV1().Services(namespace).Get(r.Context(), bucket, metav1.GetOptions{})
+		if gerr == nil && !metav1.IsControlledBy(existing, tenant) {
+			klog.Errorf("Bucket:%s of tenant:%s/%s conflicts with existing service:%s", bucket, namespace, name, bucket)
+			http.Error(w, fmt.Sprintf("service %s already exists in namespace %s and is not owned by tenant %s", bucket, namespace, name), http.StatusConflict)
+			return
+		}
 		klog.Infof("Bucket:%s already exists for tenant:%s/%s err:%s ", bucket, namespace, name, err)
 		// This might be a previously failed bucket creation. The service is expected to be the same as the one
 		// already in place so clear the error.
-		err = nil
+		err = gerr
 	}
 	if err != nil {
 		klog.Errorf("Unable to create service for tenant:%s/%s for the bucket:%s request. err:%s", namespace, name, bucket, err)